@@ -5,11 +5,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"sync"
 	"sync/atomic"
 
 	"github.com/mark3labs/mcp-go/client/transport"
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/tracing"
 )
 
 // Client implements the MCP client.
@@ -23,6 +25,8 @@ type Client struct {
 	clientCapabilities mcp.ClientCapabilities
 	serverCapabilities mcp.ServerCapabilities
 	samplingHandler    SamplingHandler
+	elicitationHandler ElicitationHandler
+	tracer             tracing.Tracer
 }
 
 type ClientOption func(*Client)
@@ -42,6 +46,24 @@ func WithSamplingHandler(handler SamplingHandler) ClientOption {
 	}
 }
 
+// WithElicitationHandler sets the elicitation handler for the client.
+// When set, the client will declare elicitation capability during initialization.
+func WithElicitationHandler(handler ElicitationHandler) ClientOption {
+	return func(c *Client) {
+		c.elicitationHandler = handler
+	}
+}
+
+// WithTracer traces the client with the given tracer: a span is started
+// around every request sent to the server and every request received from
+// it, e.g. sampling requests. Whether or not a tracer is set, requests sent
+// to the server carry the current span context in their _meta field.
+func WithTracer(tracer tracing.Tracer) ClientOption {
+	return func(c *Client) {
+		c.tracer = tracer
+	}
+}
+
 // WithSession assumes a MCP Session has already been initialized
 func WithSession() ClientOption {
 	return func(c *Client) {
@@ -113,6 +135,7 @@ func (c *Client) OnNotification(
 
 // sendRequest sends a JSON-RPC request to the server and waits for a response.
 // Returns the raw JSON response message or an error if the request fails.
+// The current span context is propagated in the _meta field of the request.
 func (c *Client) sendRequest(
 	ctx context.Context,
 	method string,
@@ -122,6 +145,15 @@ func (c *Client) sendRequest(
 		return nil, fmt.Errorf("client not initialized")
 	}
 
+	ctx, span := tracing.Start(ctx, c.tracer, method, tracing.Attribute{Key: "mcp.method.name", Value: method})
+	defer span.End()
+
+	params, err := tracing.InjectMeta(ctx, params)
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to encode %s request: %w", method, err)
+	}
+
 	id := c.requestID.Add(1)
 
 	request := transport.JSONRPCRequest{
@@ -133,11 +165,14 @@ func (c *Client) sendRequest(
 
 	response, err := c.transport.SendRequest(ctx, request)
 	if err != nil {
+		span.RecordError(err)
 		return nil, transport.NewError(err)
 	}
 
 	if response.Error != nil {
-		return nil, errors.New(response.Error.Message)
+		err := errors.New(response.Error.Message)
+		span.RecordError(err)
+		return nil, err
 	}
 
 	return &response.Result, nil
@@ -149,11 +184,14 @@ func (c *Client) Initialize(
 	ctx context.Context,
 	request mcp.InitializeRequest,
 ) (*mcp.InitializeResult, error) {
-	// Merge client capabilities with sampling capability if handler is configured
+	// Merge client capabilities with sampling and elicitation capabilities if handlers are configured
 	capabilities := request.Params.Capabilities
 	if c.samplingHandler != nil {
 		capabilities.Sampling = &struct{}{}
 	}
+	if c.elicitationHandler != nil {
+		capabilities.Elicitation = &struct{}{}
+	}
 
 	// Ensure we send a params object with all required fields
 	params := struct {
@@ -179,6 +217,13 @@ func (c *Client) Initialize(
 	// Store serverCapabilities
 	c.serverCapabilities = result.Capabilities
 
+	// HTTP transports send the negotiated version with every request, as
+	// long as it is one the client knows about
+	if httpConn, ok := c.transport.(transport.HTTPConnection); ok &&
+		slices.Contains(mcp.ValidProtocolVersions, result.ProtocolVersion) {
+		httpConn.SetProtocolVersion(result.ProtocolVersion)
+	}
+
 	// Send initialized notification
 	notification := mcp.JSONRPCNotification{
 		JSONRPC: mcp.JSONRPC_VERSION,
@@ -428,10 +473,28 @@ func (c *Client) Complete(
 
 // handleIncomingRequest processes incoming requests from the server.
 // This is the main entry point for server-to-client requests like sampling.
-func (c *Client) handleIncomingRequest(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
+func (c *Client) handleIncomingRequest(ctx context.Context, request transport.JSONRPCRequest) (response *transport.JSONRPCResponse, err error) {
+	// Continue the trace of the server, propagated in _meta
+	if params, ok := request.Params.(map[string]any); ok {
+		if meta, ok := params["_meta"].(map[string]any); ok {
+			if sc, ok := tracing.FromMeta(meta); ok {
+				ctx = tracing.ContextWithRemoteParent(ctx, sc)
+			}
+		}
+	}
+	ctx, span := tracing.Start(ctx, c.tracer, request.Method, tracing.Attribute{Key: "mcp.method.name", Value: request.Method})
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+		}
+		span.End()
+	}()
+
 	switch request.Method {
 	case string(mcp.MethodSamplingCreateMessage):
 		return c.handleSamplingRequestTransport(ctx, request)
+	case string(mcp.MethodElicitationCreate):
+		return c.handleElicitationRequestTransport(ctx, request)
 	default:
 		return nil, fmt.Errorf("unsupported request method: %s", request.Method)
 	}
@@ -484,6 +547,59 @@ func (c *Client) handleSamplingRequestTransport(ctx context.Context, request tra
 
 	return response, nil
 }
+
+// handleElicitationRequestTransport handles elicitation requests at the transport level.
+func (c *Client) handleElicitationRequestTransport(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
+	if c.elicitationHandler == nil {
+		return nil, fmt.Errorf("no elicitation handler configured")
+	}
+
+	// Parse the request parameters
+	var params mcp.ElicitParams
+	if request.Params != nil {
+		paramsBytes, err := json.Marshal(request.Params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal params: %w", err)
+		}
+		if err := json.Unmarshal(paramsBytes, &params); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal params: %w", err)
+		}
+	}
+
+	// Create the MCP request
+	mcpRequest := mcp.ElicitRequest{
+		Request: mcp.Request{
+			Method: string(mcp.MethodElicitationCreate),
+		},
+		Params: params,
+	}
+
+	// Call the elicitation handler
+	result, err := c.elicitationHandler.Elicit(ctx, mcpRequest)
+	if err != nil {
+		return nil, err
+	}
+	if result.Action != mcp.ElicitationActionAccept {
+		// Content is only sent back when the user accepted
+		result.Content = nil
+	}
+
+	// Marshal the result
+	resultBytes, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	// Create the transport response
+	response := &transport.JSONRPCResponse{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      request.ID,
+		Result:  json.RawMessage(resultBytes),
+	}
+
+	return response, nil
+}
+
 func listByPage[T any](
 	ctx context.Context,
 	client *Client,
@@ -0,0 +1,21 @@
+package client
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ElicitationHandler defines the interface for handling elicitation requests from servers.
+// Clients can implement this interface to let servers ask the user for additional information.
+type ElicitationHandler interface {
+	// Elicit handles an elicitation request from the server and returns the user's response.
+	// The implementation should:
+	// 1. Present the request message and a form matching the requested schema to the user
+	// 2. Let the user submit the information, decline the request or dismiss it
+	// 3. Return the result with the matching action:
+	//    - mcp.ElicitationActionAccept with the submitted Content
+	//    - mcp.ElicitationActionDecline when the user explicitly declined
+	//    - mcp.ElicitationActionCancel when the user dismissed the request
+	Elicit(ctx context.Context, request mcp.ElicitRequest) (*mcp.ElicitResult, error)
+}
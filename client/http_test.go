@@ -1,8 +1,13 @@
 package client
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"sync"
 	"testing"
 	"time"
@@ -203,6 +208,163 @@ func TestHTTPClient(t *testing.T) {
 	})
 }
 
+func TestHTTPClient_ProtocolVersionHeader(t *testing.T) {
+	mcpServer := server.NewMCPServer("test-server", "1.0.0")
+
+	var (
+		mu      sync.Mutex
+		headers = map[string]string{}
+	)
+	handler := server.NewStreamableHTTPServer(mcpServer)
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var message struct {
+			Method string `json:"method"`
+		}
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &message)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		mu.Lock()
+		headers[message.Method] = r.Header.Get("MCP-Protocol-Version")
+		mu.Unlock()
+		handler.ServeHTTP(w, r)
+	}))
+	defer testServer.Close()
+
+	client, err := NewStreamableHttpClient(testServer.URL)
+	if err != nil {
+		t.Fatalf("create client failed %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	if err := client.Start(ctx); err != nil {
+		t.Fatalf("Failed to start client: %v", err)
+	}
+
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{Name: "test-client", Version: "1.0.0"}
+	if _, err := client.Initialize(ctx, initRequest); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	if err := client.Ping(ctx); err != nil {
+		t.Fatalf("Failed to ping: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if got := headers["initialize"]; got != "" {
+		t.Errorf("Expected no protocol version header on initialize, got %q", got)
+	}
+	for _, method := range []string{"notifications/initialized", "ping"} {
+		if got := headers[method]; got != mcp.LATEST_PROTOCOL_VERSION {
+			t.Errorf("Expected protocol version header %q on %s, got %q", mcp.LATEST_PROTOCOL_VERSION, method, got)
+		}
+	}
+}
+
+func TestHTTPClient_Sampling(t *testing.T) {
+	mcpServer := server.NewMCPServer("test-server", "1.0.0")
+	mcpServer.EnableSampling()
+	sample := func(ctx context.Context) (string, error) {
+		result, err := mcpServer.RequestSampling(ctx, mcp.CreateMessageRequest{
+			CreateMessageParams: mcp.CreateMessageParams{
+				Messages: []mcp.SamplingMessage{
+					{Role: mcp.RoleUser, Content: mcp.NewTextContent("Hello")},
+				},
+				MaxTokens: 100,
+			},
+		})
+		if err != nil {
+			return "", err
+		}
+		return result.Model, nil
+	}
+	mcpServer.AddTool(mcp.NewTool("sample"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		model, err := sample(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return mcp.NewToolResultText("sampled by " + model), nil
+	})
+	background := make(chan string, 1)
+	mcpServer.AddTool(mcp.NewTool("sampleLater"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Sample once the tool call is complete, over the listening stream
+		ctx = context.WithoutCancel(ctx)
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			model, err := sample(ctx)
+			if err != nil {
+				model = err.Error()
+			}
+			background <- model
+		}()
+		return mcp.NewToolResultText("started"), nil
+	})
+
+	testServer := server.NewTestStreamableHTTPServer(mcpServer)
+	defer testServer.Close()
+
+	trans, err := transport.NewStreamableHTTP(testServer.URL, transport.WithContinuousListening())
+	if err != nil {
+		t.Fatalf("create transport failed %v", err)
+	}
+	client := NewClient(trans, WithSamplingHandler(&mockSamplingHandler{
+		result: &mcp.CreateMessageResult{
+			SamplingMessage: mcp.SamplingMessage{
+				Role:    mcp.RoleAssistant,
+				Content: mcp.NewTextContent("Hi there"),
+			},
+			Model: "test-model",
+		},
+	}))
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Start(ctx); err != nil {
+		t.Fatalf("Failed to start client: %v", err)
+	}
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{Name: "test-client", Version: "1.0.0"}
+	if _, err := client.Initialize(ctx, initRequest); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	t.Run("request on the stream of a tool call", func(t *testing.T) {
+		request := mcp.CallToolRequest{}
+		request.Params.Name = "sample"
+		result, err := client.CallTool(ctx, request)
+		if err != nil {
+			t.Fatalf("CallTool failed: %v", err)
+		}
+		if len(result.Content) != 1 {
+			t.Fatalf("Expected 1 content item, got %d", len(result.Content))
+		}
+		if text, ok := result.Content[0].(mcp.TextContent); !ok || text.Text != "sampled by test-model" {
+			t.Errorf("Expected the sampled result, got %+v", result.Content[0])
+		}
+	})
+
+	t.Run("request on the listening stream", func(t *testing.T) {
+		request := mcp.CallToolRequest{}
+		request.Params.Name = "sampleLater"
+		if _, err := client.CallTool(ctx, request); err != nil {
+			t.Fatalf("CallTool failed: %v", err)
+		}
+		select {
+		case model := <-background:
+			if model != "test-model" {
+				t.Errorf("Expected the sampled result, got %q", model)
+			}
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for sampling result")
+		}
+	})
+}
+
 type SafeMap struct {
 	mu   sync.RWMutex
 	data map[string]int
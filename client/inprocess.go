@@ -28,6 +28,17 @@ func NewInProcessClientWithSamplingHandler(server *server.MCPServer, handler Sam
 	return client, nil
 }
 
+// NewInProcessClientWithElicitationHandler creates an in-process client with elicitation support
+func NewInProcessClientWithElicitationHandler(server *server.MCPServer, handler ElicitationHandler) (*Client, error) {
+	inProcessTransport := transport.NewInProcessTransportWithOptions(server,
+		transport.WithElicitationHandler(handler))
+
+	client := NewClient(inProcessTransport)
+	client.elicitationHandler = handler
+
+	return client, nil
+}
+
 // inProcessSamplingHandlerWrapper wraps client.SamplingHandler to implement server.SamplingHandler
 type inProcessSamplingHandlerWrapper struct {
 	handler SamplingHandler
@@ -0,0 +1,131 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// MockElicitationHandler implements ElicitationHandler for testing
+type MockElicitationHandler struct {
+	action mcp.ElicitationAction
+}
+
+func (h *MockElicitationHandler) Elicit(ctx context.Context, request mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+	if request.Params.Message == "" {
+		return nil, fmt.Errorf("missing message")
+	}
+	return &mcp.ElicitResult{
+		Action:  h.action,
+		Content: map[string]any{"name": "octocat"},
+	}, nil
+}
+
+func TestInProcessElicitation(t *testing.T) {
+	hooks := &server.Hooks{}
+	hooks.AddAfterInitialize(func(ctx context.Context, id any, message *mcp.InitializeRequest, result *mcp.InitializeResult) {
+		if message.Params.Capabilities.Elicitation == nil {
+			t.Errorf("Expected the client to declare the elicitation capability")
+		}
+	})
+	mcpServer := server.NewMCPServer("test-server", "1.0.0", server.WithHooks(hooks))
+	mcpServer.AddTool(mcp.NewTool("greet"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := mcpServer.RequestElicitation(ctx, mcp.ElicitRequest{
+			Params: mcp.ElicitParams{
+				Message: "What is your name?",
+				RequestedSchema: mcp.ElicitationSchema{
+					Type: "object",
+					Properties: map[string]mcp.PrimitiveSchemaDefinition{
+						"name": {Type: "string", Title: "Name"},
+					},
+					Required: []string{"name"},
+				},
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if result.Action != mcp.ElicitationActionAccept {
+			return mcp.NewToolResultText(string(result.Action)), nil
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("Hello, %s!", result.Content["name"])), nil
+	})
+
+	tests := []struct {
+		action   mcp.ElicitationAction
+		expected string
+	}{
+		{action: mcp.ElicitationActionAccept, expected: "Hello, octocat!"},
+		{action: mcp.ElicitationActionDecline, expected: "decline"},
+		{action: mcp.ElicitationActionCancel, expected: "cancel"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.action), func(t *testing.T) {
+			client, err := NewInProcessClientWithElicitationHandler(mcpServer, &MockElicitationHandler{action: tt.action})
+			if err != nil {
+				t.Fatalf("Failed to create client: %v", err)
+			}
+			defer client.Close()
+
+			if err := client.Start(context.Background()); err != nil {
+				t.Fatalf("Failed to start client: %v", err)
+			}
+
+			initRequest := mcp.InitializeRequest{}
+			initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+			initRequest.Params.ClientInfo = mcp.Implementation{
+				Name:    "test-client",
+				Version: "1.0.0",
+			}
+			if _, err := client.Initialize(context.Background(), initRequest); err != nil {
+				t.Fatalf("Failed to initialize: %v", err)
+			}
+
+			request := mcp.CallToolRequest{}
+			request.Params.Name = "greet"
+			result, err := client.CallTool(context.Background(), request)
+			if err != nil {
+				t.Fatalf("CallTool failed: %v", err)
+			}
+			if result.IsError {
+				t.Fatalf("Tool returned an error: %+v", result.Content)
+			}
+			if text := result.Content[0].(mcp.TextContent).Text; text != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, text)
+			}
+		})
+	}
+}
+
+func TestClient_HandleElicitationRequest(t *testing.T) {
+	client := &Client{elicitationHandler: &MockElicitationHandler{action: mcp.ElicitationActionDecline}}
+
+	response, err := client.handleIncomingRequest(context.Background(), transport.JSONRPCRequest{
+		ID:     mcp.NewRequestId(int64(1)),
+		Method: string(mcp.MethodElicitationCreate),
+		Params: map[string]any{
+			"message":         "What is your name?",
+			"requestedSchema": map[string]any{"type": "object", "properties": map[string]any{}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to handle elicitation request: %v", err)
+	}
+
+	var result mcp.ElicitResult
+	if err := json.Unmarshal(response.Result, &result); err != nil {
+		t.Fatalf("Failed to unmarshal result: %v", err)
+	}
+	if result.Action != mcp.ElicitationActionDecline {
+		t.Errorf("Expected action decline, got %s", result.Action)
+	}
+	if result.Content != nil {
+		t.Errorf("Expected no content for a declined request, got %v", result.Content)
+	}
+}
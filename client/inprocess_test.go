@@ -3,6 +3,7 @@ package client
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -419,3 +420,58 @@ func TestInProcessMCPClient(t *testing.T) {
 		}
 	})
 }
+
+func TestInProcessMCPClient_Progress(t *testing.T) {
+	mcpServer := server.NewMCPServer("test-server", "1.0.0", server.WithProgressInterval(0))
+	mcpServer.AddTool(mcp.NewTool("progress-tool"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		for i := 1; i <= 3; i++ {
+			server.ProgressFromContext(ctx).Report(float64(i), 3, "step")
+		}
+		return mcp.NewToolResultText("done"), nil
+	})
+
+	client, err := NewInProcessClient(mcpServer)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	progress := make(chan mcp.JSONRPCNotification, 10)
+	client.OnNotification(func(notification mcp.JSONRPCNotification) {
+		if notification.Method == mcp.MethodNotificationProgress {
+			progress <- notification
+		}
+	})
+
+	if err := client.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start client: %v", err)
+	}
+
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{
+		Name:    "test-client",
+		Version: "1.0.0",
+	}
+	if _, err := client.Initialize(context.Background(), initRequest); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "progress-tool"
+	request.Params.Meta = &mcp.Meta{ProgressToken: "token-1"}
+	if _, err := client.CallTool(context.Background(), request); err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+
+	for i := 1; i <= 3; i++ {
+		select {
+		case notification := <-progress:
+			if got := notification.Params.AdditionalFields["progress"]; got != float64(i) {
+				t.Errorf("Expected progress %d, got %v", i, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for progress notification %d", i)
+		}
+	}
+}
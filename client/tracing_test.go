@@ -0,0 +1,135 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/tracing"
+)
+
+const testTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+type testSpan struct {
+	name   string
+	parent string
+	ended  bool
+}
+
+func (s *testSpan) SetAttributes(...tracing.Attribute) {}
+func (s *testSpan) RecordError(error)                  {}
+func (s *testSpan) End()                               { s.ended = true }
+func (s *testSpan) SpanContext() tracing.SpanContext {
+	return tracing.SpanContext{TraceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-b7ad6b7169203331-01"}
+}
+
+type testTracer struct {
+	spans []*testSpan
+}
+
+func (t *testTracer) Start(ctx context.Context, name string, attrs ...tracing.Attribute) (context.Context, tracing.Span) {
+	span := &testSpan{name: name}
+	if sc, ok := tracing.CurrentSpanContext(ctx); ok {
+		span.parent = sc.TraceParent
+	}
+	t.spans = append(t.spans, span)
+	return ctx, span
+}
+
+func TestClient_SendRequestInjectsTraceContext(t *testing.T) {
+	tests := []struct {
+		name   string
+		tracer *testTracer
+		want   string
+	}{
+		{
+			name: "without tracer",
+			want: testTraceParent,
+		},
+		{
+			name:   "with tracer",
+			tracer: &testTracer{},
+			want:   "00-4bf92f3577b34da6a3ce929d0e0e4736-b7ad6b7169203331-01",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTransport := newMockTransport()
+			options := []ClientOption{WithSession()}
+			if tt.tracer != nil {
+				options = append(options, WithTracer(tt.tracer))
+			}
+			client := NewClient(mockTransport, options...)
+
+			mockTransport.responseChan <- &transport.JSONRPCResponse{
+				JSONRPC: mcp.JSONRPC_VERSION,
+				ID:      mcp.NewRequestId(1),
+				Result:  json.RawMessage(`{"tools": []}`),
+			}
+
+			ctx := tracing.ContextWithRemoteParent(context.Background(), tracing.SpanContext{TraceParent: testTraceParent})
+			if _, err := client.ListTools(ctx, mcp.ListToolsRequest{}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			request := <-mockTransport.requestChan
+			params, ok := request.Params.(map[string]any)
+			if !ok {
+				t.Fatalf("expected params to be an object, got %#v", request.Params)
+			}
+			meta, _ := params["_meta"].(map[string]any)
+			if meta[tracing.TraceParentKey] != tt.want {
+				t.Errorf("expected traceparent %q, got %v", tt.want, meta[tracing.TraceParentKey])
+			}
+
+			if tt.tracer != nil {
+				if len(tt.tracer.spans) != 1 {
+					t.Fatalf("expected 1 span, got %d", len(tt.tracer.spans))
+				}
+				span := tt.tracer.spans[0]
+				if span.name != string(mcp.MethodToolsList) || span.parent != testTraceParent || !span.ended {
+					t.Errorf("unexpected span: %+v", span)
+				}
+			}
+		})
+	}
+}
+
+func TestClient_HandleIncomingRequestContinuesTrace(t *testing.T) {
+	tracer := &testTracer{}
+	client := &Client{
+		samplingHandler: &mockSamplingHandler{result: &mcp.CreateMessageResult{}},
+		tracer:          tracer,
+	}
+
+	request := transport.JSONRPCRequest{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      mcp.NewRequestId(1),
+		Method:  string(mcp.MethodSamplingCreateMessage),
+		Params: map[string]any{
+			"messages":  []any{},
+			"maxTokens": 100,
+			"_meta":     map[string]any{tracing.TraceParentKey: testTraceParent},
+		},
+	}
+	if _, err := client.handleIncomingRequest(context.Background(), request); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(tracer.spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(tracer.spans))
+	}
+	span := tracer.spans[0]
+	if span.name != string(mcp.MethodSamplingCreateMessage) {
+		t.Errorf("expected span %q, got %q", mcp.MethodSamplingCreateMessage, span.name)
+	}
+	if span.parent != testTraceParent {
+		t.Errorf("expected parent %q, got %q", testTraceParent, span.parent)
+	}
+	if !span.ended {
+		t.Error("expected span to be ended")
+	}
+}
@@ -11,13 +11,17 @@ import (
 )
 
 type InProcessTransport struct {
-	server          *server.MCPServer
-	samplingHandler server.SamplingHandler
-	session         *server.InProcessSession
-	sessionID       string
+	server             *server.MCPServer
+	samplingHandler    server.SamplingHandler
+	rootsHandler       server.RootsHandler
+	elicitationHandler server.ElicitationHandler
+	session            *server.InProcessSession
+	sessionID          string
 
 	onNotification func(mcp.JSONRPCNotification)
 	notifyMu       sync.RWMutex
+	done           chan struct{}
+	closeOnce      sync.Once
 }
 
 type InProcessOption func(*InProcessTransport)
@@ -28,9 +32,25 @@ func WithSamplingHandler(handler server.SamplingHandler) InProcessOption {
 	}
 }
 
+// WithRootsHandler sets the handler that answers roots/list requests from the server.
+func WithRootsHandler(handler server.RootsHandler) InProcessOption {
+	return func(t *InProcessTransport) {
+		t.rootsHandler = handler
+	}
+}
+
+// WithElicitationHandler sets the handler that answers elicitation requests from the server.
+func WithElicitationHandler(handler server.ElicitationHandler) InProcessOption {
+	return func(t *InProcessTransport) {
+		t.elicitationHandler = handler
+	}
+}
+
 func NewInProcessTransport(server *server.MCPServer) *InProcessTransport {
 	return &InProcessTransport{
-		server: server,
+		server:    server,
+		sessionID: server.GenerateInProcessSessionID(),
+		done:      make(chan struct{}),
 	}
 }
 
@@ -38,6 +58,7 @@ func NewInProcessTransportWithOptions(server *server.MCPServer, opts ...InProces
 	t := &InProcessTransport{
 		server:    server,
 		sessionID: server.GenerateInProcessSessionID(),
+		done:      make(chan struct{}),
 	}
 
 	for _, opt := range opts {
@@ -48,16 +69,36 @@ func NewInProcessTransportWithOptions(server *server.MCPServer, opts ...InProces
 }
 
 func (c *InProcessTransport) Start(ctx context.Context) error {
-	// Create and register session if we have a sampling handler
-	if c.samplingHandler != nil {
-		c.session = server.NewInProcessSession(c.sessionID, c.samplingHandler)
-		if err := c.server.RegisterSession(ctx, c.session); err != nil {
-			return fmt.Errorf("failed to register session: %w", err)
-		}
+	// Create and register a session so that the server can send notifications
+	// (and sampling requests, if we have a sampling handler) to the client
+	c.session = server.NewInProcessSession(c.sessionID, c.samplingHandler)
+	c.session.SetRootsHandler(c.rootsHandler)
+	c.session.SetElicitationHandler(c.elicitationHandler)
+	if err := c.server.RegisterSession(ctx, c.session); err != nil {
+		return fmt.Errorf("failed to register session: %w", err)
 	}
+	go c.forwardNotifications()
 	return nil
 }
 
+// forwardNotifications delivers notifications sent by the server on the
+// session to the notification handler until the transport is closed.
+func (c *InProcessTransport) forwardNotifications() {
+	for {
+		select {
+		case notification := <-c.session.Notifications():
+			c.notifyMu.RLock()
+			handler := c.onNotification
+			c.notifyMu.RUnlock()
+			if handler != nil {
+				handler(notification)
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
 func (c *InProcessTransport) SendRequest(ctx context.Context, request JSONRPCRequest) (*JSONRPCResponse, error) {
 	requestBytes, err := json.Marshal(request)
 	if err != nil {
@@ -90,6 +131,12 @@ func (c *InProcessTransport) SendNotification(ctx context.Context, notification
 		return fmt.Errorf("failed to marshal notification: %w", err)
 	}
 	notificationBytes = append(notificationBytes, '\n')
+
+	// Add session to context if available
+	if c.session != nil {
+		ctx = c.server.WithContext(ctx, c.session)
+	}
+
 	c.server.HandleMessage(ctx, notificationBytes)
 
 	return nil
@@ -105,6 +152,9 @@ func (c *InProcessTransport) Close() error {
 	if c.session != nil {
 		c.server.UnregisterSession(context.Background(), c.sessionID)
 	}
+	c.closeOnce.Do(func() {
+		close(c.done)
+	})
 	return nil
 }
 
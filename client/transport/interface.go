@@ -47,6 +47,15 @@ type BidirectionalInterface interface {
 	SetRequestHandler(handler RequestHandler)
 }
 
+// HTTPConnection is a Transport that runs over HTTP and sends the negotiated
+// protocol version with every request after initialization.
+type HTTPConnection interface {
+	Interface
+
+	// SetProtocolVersion sets the protocol version negotiated with the server.
+	SetProtocolVersion(version string)
+}
+
 type JSONRPCRequest struct {
 	JSONRPC string        `json:"jsonrpc"`
 	ID      mcp.RequestId `json:"id"`
@@ -0,0 +1,12 @@
+package transport
+
+// Keys of the attributes identifying sessions and messages in the log
+// records of the transports.
+const (
+	logKeySessionID = "sessionID"
+	logKeyMethod    = "method"
+	logKeyRequestID = "requestID"
+	logKeyEventID   = "eventID"
+	logKeyEndpoint  = "endpoint"
+	logKeyError     = "error"
+)
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strings"
@@ -40,6 +41,8 @@ type SSE struct {
 
 	// OAuth support
 	oauthHandler *OAuthHandler
+
+	logger *slog.Logger
 }
 
 type ClientOption func(*SSE)
@@ -68,6 +71,14 @@ func WithOAuth(config OAuthConfig) ClientOption {
 	}
 }
 
+// WithSSELogger sets the structured logger of the transport, slog.Default()
+// by default.
+func WithSSELogger(logger *slog.Logger) ClientOption {
+	return func(sc *SSE) {
+		sc.logger = logger
+	}
+}
+
 // NewSSE creates a new SSE-based MCP client with the given base URL.
 // Returns an error if the URL is invalid.
 func NewSSE(baseURL string, options ...ClientOption) (*SSE, error) {
@@ -82,6 +93,7 @@ func NewSSE(baseURL string, options ...ClientOption) (*SSE, error) {
 		responses:    make(map[string]chan *JSONRPCResponse),
 		endpointChan: make(chan struct{}),
 		headers:      make(map[string]string),
+		logger:       slog.Default(),
 	}
 
 	for _, opt := range options {
@@ -204,7 +216,7 @@ func (c *SSE) readSSE(reader io.ReadCloser) {
 				break
 			}
 			if !c.closed.Load() {
-				fmt.Printf("SSE stream error: %v\n", err)
+				c.logger.Error("SSE stream error", logKeyError, err)
 			}
 			return
 		}
@@ -240,11 +252,11 @@ func (c *SSE) handleSSEEvent(event, data string) {
 	case "endpoint":
 		endpoint, err := c.baseURL.Parse(data)
 		if err != nil {
-			fmt.Printf("Error parsing endpoint URL: %v\n", err)
+			c.logger.Error("Error parsing endpoint URL", logKeyEndpoint, data, logKeyError, err)
 			return
 		}
 		if endpoint.Host != c.baseURL.Host {
-			fmt.Printf("Endpoint origin does not match connection origin\n")
+			c.logger.Error("Endpoint origin does not match connection origin", logKeyEndpoint, endpoint.String())
 			return
 		}
 		c.endpoint = endpoint
@@ -253,7 +265,7 @@ func (c *SSE) handleSSEEvent(event, data string) {
 	case "message":
 		var baseMessage JSONRPCResponse
 		if err := json.Unmarshal([]byte(data), &baseMessage); err != nil {
-			fmt.Printf("Error unmarshaling message: %v\n", err)
+			c.logger.Error("Error unmarshaling message", logKeyError, err)
 			return
 		}
 
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"os/exec"
 	"sync"
@@ -36,6 +37,7 @@ type Stdio struct {
 	requestMu      sync.RWMutex
 	ctx            context.Context
 	ctxMu          sync.RWMutex
+	logger         *slog.Logger
 }
 
 // StdioOption defines a function that configures a Stdio transport instance.
@@ -56,6 +58,14 @@ func WithCommandFunc(f CommandFunc) StdioOption {
 	}
 }
 
+// WithStdioLogger sets the structured logger of the transport, slog.Default()
+// by default.
+func WithStdioLogger(logger *slog.Logger) StdioOption {
+	return func(s *Stdio) {
+		s.logger = logger
+	}
+}
+
 // NewIO returns a new stdio-based transport using existing input, output, and
 // logging streams instead of spawning a subprocess.
 // This is useful for testing and simulating client behavior.
@@ -68,6 +78,7 @@ func NewIO(input io.Reader, output io.WriteCloser, logging io.ReadCloser) *Stdio
 		responses: make(map[string]chan *JSONRPCResponse),
 		done:      make(chan struct{}),
 		ctx:       context.Background(),
+		logger:    slog.Default(),
 	}
 }
 
@@ -101,6 +112,7 @@ func NewStdioWithOptions(
 		responses: make(map[string]chan *JSONRPCResponse),
 		done:      make(chan struct{}),
 		ctx:       context.Background(),
+		logger:    slog.Default(),
 	}
 
 	for _, opt := range opts {
@@ -238,7 +250,7 @@ func (c *Stdio) readResponses() {
 			line, err := c.stdout.ReadString('\n')
 			if err != nil {
 				if err != io.EOF {
-					fmt.Printf("Error reading response: %v\n", err)
+					c.logger.Error("Error reading response", logKeyError, err)
 				}
 				return
 			}
@@ -449,13 +461,13 @@ func (c *Stdio) handleIncomingRequest(request JSONRPCRequest) {
 func (c *Stdio) sendResponse(response JSONRPCResponse) {
 	responseBytes, err := json.Marshal(response)
 	if err != nil {
-		fmt.Printf("Error marshaling response: %v\n", err)
+		c.logger.Error("Error marshaling response", logKeyRequestID, response.ID.Value(), logKeyError, err)
 		return
 	}
 	responseBytes = append(responseBytes, '\n')
 
 	if _, err := c.stdin.Write(responseBytes); err != nil {
-		fmt.Printf("Error writing response: %v\n", err)
+		c.logger.Error("Error writing response", logKeyRequestID, response.ID.Value(), logKeyError, err)
 	}
 }
 
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"mime"
 	"net/http"
 	"net/url"
@@ -69,6 +70,14 @@ func WithHTTPOAuth(config OAuthConfig) StreamableHTTPCOption {
 }
 
 func WithLogger(logger util.Logger) StreamableHTTPCOption {
+	return func(sc *StreamableHTTP) {
+		sc.logger = util.ToSlog(logger)
+	}
+}
+
+// WithHTTPLogger sets the structured logger of the transport, slog.Default()
+// by default.
+func WithHTTPLogger(logger *slog.Logger) StreamableHTTPCOption {
 	return func(sc *StreamableHTTP) {
 		sc.logger = logger
 	}
@@ -89,20 +98,25 @@ func WithSession(sessionID string) StreamableHTTPCOption {
 //
 // https://modelcontextprotocol.io/specification/2025-03-26/basic/transports
 //
+// When the server assigns IDs to its SSE events, a stream that ends early is
+// resumed with the Last-Event-ID header.
+// (https://modelcontextprotocol.io/specification/2025-03-26/basic/transports#resumability-and-redelivery)
+//
+// Requests sent by the server on any SSE stream are passed to the handler set
+// with SetRequestHandler, and the response is posted back to the server.
+//
 // The current implementation does not support the following features:
 //   - batching
-//   - resuming stream
-//     (https://modelcontextprotocol.io/specification/2025-03-26/basic/transports#resumability-and-redelivery)
-//   - server -> client request
 type StreamableHTTP struct {
 	serverURL           *url.URL
 	httpClient          *http.Client
 	headers             map[string]string
 	headerFunc          HTTPHeaderFunc
-	logger              util.Logger
+	logger              *slog.Logger
 	getListeningEnabled bool
 
-	sessionID atomic.Value // string
+	sessionID       atomic.Value // string
+	protocolVersion atomic.Value // string
 
 	initialized     chan struct{}
 	initializedOnce sync.Once
@@ -110,6 +124,9 @@ type StreamableHTTP struct {
 	notificationHandler func(mcp.JSONRPCNotification)
 	notifyMu            sync.RWMutex
 
+	requestHandler RequestHandler
+	requestMu      sync.RWMutex
+
 	closed chan struct{}
 
 	// OAuth support
@@ -117,6 +134,10 @@ type StreamableHTTP struct {
 	wg           sync.WaitGroup
 }
 
+// The client only handles requests from the server, such as sampling, with
+// transports implementing BidirectionalInterface.
+var _ BidirectionalInterface = (*StreamableHTTP)(nil)
+
 // NewStreamableHTTP creates a new Streamable HTTP transport with the given server URL.
 // Returns an error if the URL is invalid.
 func NewStreamableHTTP(serverURL string, options ...StreamableHTTPCOption) (*StreamableHTTP, error) {
@@ -130,10 +151,11 @@ func NewStreamableHTTP(serverURL string, options ...StreamableHTTPCOption) (*Str
 		httpClient:  &http.Client{},
 		headers:     make(map[string]string),
 		closed:      make(chan struct{}),
-		logger:      util.DefaultLogger(),
+		logger:      slog.Default(),
 		initialized: make(chan struct{}),
 	}
 	smc.sessionID.Store("") // set initial value to simplify later usage
+	smc.protocolVersion.Store("")
 
 	for _, opt := range options {
 		if opt != nil {
@@ -191,13 +213,16 @@ func (c *StreamableHTTP) Close() error {
 			defer cancel()
 			req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.serverURL.String(), nil)
 			if err != nil {
-				c.logger.Errorf("failed to create close request: %v", err)
+				c.sessionLogger().Error("failed to create close request", logKeyError, err)
 				return
 			}
 			req.Header.Set(headerKeySessionID, sessionId)
+			if version := c.protocolVersion.Load().(string); version != "" {
+				req.Header.Set(headerKeyProtocolVersion, version)
+			}
 			res, err := c.httpClient.Do(req)
 			if err != nil {
-				c.logger.Errorf("failed to send close request: %v", err)
+				c.sessionLogger().Error("failed to send close request", logKeyError, err)
 				return
 			}
 			res.Body.Close()
@@ -208,7 +233,9 @@ func (c *StreamableHTTP) Close() error {
 }
 
 const (
-	headerKeySessionID = "Mcp-Session-Id"
+	headerKeySessionID       = "Mcp-Session-Id"
+	headerKeyProtocolVersion = "MCP-Protocol-Version"
+	headerKeyLastEventID     = "Last-Event-ID"
 )
 
 // ErrOAuthAuthorizationRequired is a sentinel error for OAuth authorization required
@@ -323,6 +350,17 @@ func (c *StreamableHTTP) sendHTTP(
 	method string,
 	body io.Reader,
 	acceptType string,
+) (resp *http.Response, err error) {
+	return c.sendHTTPWithHeaders(ctx, method, body, acceptType, nil)
+}
+
+// sendHTTPWithHeaders is like sendHTTP, with additional request headers.
+func (c *StreamableHTTP) sendHTTPWithHeaders(
+	ctx context.Context,
+	method string,
+	body io.Reader,
+	acceptType string,
+	extraHeaders map[string]string,
 ) (resp *http.Response, err error) {
 	// Create HTTP request
 	req, err := http.NewRequestWithContext(ctx, method, c.serverURL.String(), body)
@@ -337,9 +375,15 @@ func (c *StreamableHTTP) sendHTTP(
 	if sessionID != "" {
 		req.Header.Set(headerKeySessionID, sessionID)
 	}
+	if version := c.protocolVersion.Load().(string); version != "" {
+		req.Header.Set(headerKeyProtocolVersion, version)
+	}
 	for k, v := range c.headers {
 		req.Header.Set(k, v)
 	}
+	for k, v := range extraHeaders {
+		req.Header.Set(k, v)
+	}
 
 	// Add OAuth authorization if configured
 	if c.oauthHandler != nil {
@@ -380,24 +424,83 @@ func (c *StreamableHTTP) sendHTTP(
 // handleSSEResponse processes an SSE stream for a specific request.
 // It returns the final result for the request once received, or an error.
 // If ignoreResponse is true, it won't return when a response messge is received. This is for continuous listening.
+//
+// If the stream ends early and the server assigned IDs to its events, the
+// stream is resumed with a GET request carrying the Last-Event-ID header.
 func (c *StreamableHTTP) handleSSEResponse(ctx context.Context, reader io.ReadCloser, ignoreResponse bool) (*JSONRPCResponse, error) {
+	var lastEventID string
+	attempts := 0
+	for {
+		response, eventID, err := c.readSSEResponse(ctx, reader, ignoreResponse)
+		if response != nil || err != nil {
+			return response, err
+		}
+
+		// The stream ended without the response
+		if eventID != "" {
+			// Events were received, so the server is reachable again
+			lastEventID = eventID
+			attempts = 0
+		}
+		if lastEventID == "" || attempts >= maxResumeAttempts {
+			return nil, fmt.Errorf("unexpected nil response")
+		}
+		attempts++
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(retryInterval):
+		}
+
+		reader, err = c.resumeStream(ctx, lastEventID)
+		if err != nil {
+			c.sessionLogger().Error("failed to resume SSE stream", logKeyEventID, lastEventID, logKeyError, err)
+			reader = io.NopCloser(strings.NewReader(""))
+		}
+	}
+}
+
+// readSSEResponse reads an SSE stream until the response to the request is
+// received, or until the stream ends. It returns the response, if any, and
+// the ID of the last event received on the stream.
+func (c *StreamableHTTP) readSSEResponse(ctx context.Context, reader io.ReadCloser, ignoreResponse bool) (*JSONRPCResponse, string, error) {
 	// Create a channel for this specific request
 	responseChan := make(chan *JSONRPCResponse, 1)
 
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
+	var lastEventID atomic.Value // string
+	lastEventID.Store("")
+
 	// Start a goroutine to process the SSE stream
 	go func() {
 		// only close responseChan after readingSSE()
 		defer close(responseChan)
 
-		c.readSSE(ctx, reader, func(event, data string) {
-			// (unsupported: batching)
+		c.readSSE(ctx, reader, func(id, event, data string) {
+			if id != "" {
+				lastEventID.Store(id)
+			}
 
 			var message JSONRPCResponse
 			if err := json.Unmarshal([]byte(data), &message); err != nil {
-				c.logger.Errorf("failed to unmarshal message: %v", err)
+				c.sessionLogger().Error("failed to unmarshal message", logKeyError, err)
+				return
+			}
+
+			// Handle request from the server
+			var baseMessage struct {
+				Method string `json:"method"`
+			}
+			if err := json.Unmarshal([]byte(data), &baseMessage); err == nil && baseMessage.Method != "" && !message.ID.IsNil() {
+				var request JSONRPCRequest
+				if err := json.Unmarshal([]byte(data), &request); err != nil {
+					c.sessionLogger().Error("failed to unmarshal request", logKeyMethod, baseMessage.Method, logKeyRequestID, message.ID.Value(), logKeyError, err)
+					return
+				}
+				c.handleIncomingRequest(ctx, request)
 				return
 			}
 
@@ -405,7 +508,7 @@ func (c *StreamableHTTP) handleSSEResponse(ctx context.Context, reader io.ReadCl
 			if message.ID.IsNil() {
 				var notification mcp.JSONRPCNotification
 				if err := json.Unmarshal([]byte(data), &notification); err != nil {
-					c.logger.Errorf("failed to unmarshal notification: %v", err)
+					c.sessionLogger().Error("failed to unmarshal notification", logKeyError, err)
 					return
 				}
 				c.notifyMu.RLock()
@@ -425,22 +528,40 @@ func (c *StreamableHTTP) handleSSEResponse(ctx context.Context, reader io.ReadCl
 	// Wait for the response or context cancellation
 	select {
 	case response := <-responseChan:
-		if response == nil {
-			return nil, fmt.Errorf("unexpected nil response")
-		}
-		return response, nil
+		return response, lastEventID.Load().(string), nil
 	case <-ctx.Done():
-		return nil, ctx.Err()
+		return nil, "", ctx.Err()
 	}
 }
 
-// readSSE reads the SSE stream(reader) and calls the handler for each event and data pair.
+// resumeStream reconnects to the SSE stream on which the given event was
+// sent, asking the server to replay the events sent after it.
+func (c *StreamableHTTP) resumeStream(ctx context.Context, lastEventID string) (io.ReadCloser, error) {
+	resp, err := c.sendHTTPWithHeaders(ctx, http.MethodGet, nil, "text/event-stream", map[string]string{
+		headerKeyLastEventID: lastEventID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("request failed with status %d", resp.StatusCode)
+	}
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType != "text/event-stream" {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected content type: %s", resp.Header.Get("Content-Type"))
+	}
+	return resp.Body, nil
+}
+
+// readSSE reads the SSE stream(reader) and calls the handler for each event and data pair,
+// along with the last event ID set by the stream.
 // It will end when the reader is closed (or the context is done).
-func (c *StreamableHTTP) readSSE(ctx context.Context, reader io.ReadCloser, handler func(event, data string)) {
+func (c *StreamableHTTP) readSSE(ctx context.Context, reader io.ReadCloser, handler func(id, event, data string)) {
 	defer reader.Close()
 
 	br := bufio.NewReader(reader)
-	var event, data string
+	var id, event, data string
 
 	for {
 		select {
@@ -456,7 +577,7 @@ func (c *StreamableHTTP) readSSE(ctx context.Context, reader io.ReadCloser, hand
 						if event == "" {
 							event = "message"
 						}
-						handler(event, data)
+						handler(id, event, data)
 					}
 					return
 				}
@@ -464,7 +585,7 @@ func (c *StreamableHTTP) readSSE(ctx context.Context, reader io.ReadCloser, hand
 				case <-ctx.Done():
 					return
 				default:
-					c.logger.Errorf("SSE stream error: %v", err)
+					c.sessionLogger().Error("SSE stream error", logKeyError, err)
 					return
 				}
 			}
@@ -478,14 +599,16 @@ func (c *StreamableHTTP) readSSE(ctx context.Context, reader io.ReadCloser, hand
 					if event == "" {
 						event = "message"
 					}
-					handler(event, data)
+					handler(id, event, data)
 					event = ""
 					data = ""
 				}
 				continue
 			}
 
-			if strings.HasPrefix(line, "event:") {
+			if strings.HasPrefix(line, "id:") {
+				id = strings.TrimSpace(strings.TrimPrefix(line, "id:"))
+			} else if strings.HasPrefix(line, "event:") {
 				event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
 			} else if strings.HasPrefix(line, "data:") {
 				data = strings.TrimSpace(strings.TrimPrefix(line, "data:"))
@@ -540,6 +663,88 @@ func (c *StreamableHTTP) GetSessionId() string {
 	return c.sessionID.Load().(string)
 }
 
+// SetProtocolVersion sets the protocol version sent in the MCP-Protocol-Version
+// header of subsequent requests.
+func (c *StreamableHTTP) SetProtocolVersion(version string) {
+	c.protocolVersion.Store(version)
+}
+
+// SetRequestHandler sets the handler function to be called when a request is received from the server.
+// This enables bidirectional communication for features like sampling.
+func (c *StreamableHTTP) SetRequestHandler(handler RequestHandler) {
+	c.requestMu.Lock()
+	defer c.requestMu.Unlock()
+	c.requestHandler = handler
+}
+
+// handleIncomingRequest processes a request received from the server on an
+// SSE stream. It calls the registered request handler and posts the response
+// back to the server.
+func (c *StreamableHTTP) handleIncomingRequest(ctx context.Context, request JSONRPCRequest) {
+	c.requestMu.RLock()
+	handler := c.requestHandler
+	c.requestMu.RUnlock()
+
+	// The request outlives the stream it was received on, which ends once the
+	// response to the client's own request is received
+	ctx, cancel := c.contextAwareOfClientClose(context.WithoutCancel(ctx))
+
+	// Handle the request in a goroutine to avoid blocking the stream
+	go func() {
+		defer cancel()
+
+		if handler == nil {
+			c.sendResponse(ctx, newErrorResponse(request.ID, mcp.METHOD_NOT_FOUND, "No request handler configured"))
+			return
+		}
+
+		response, err := handler(ctx, request)
+		if err != nil {
+			c.sendResponse(ctx, newErrorResponse(request.ID, mcp.INTERNAL_ERROR, err.Error()))
+			return
+		}
+		if response != nil {
+			c.sendResponse(ctx, *response)
+		}
+	}()
+}
+
+// sendResponse posts a response to a request received from the server.
+func (c *StreamableHTTP) sendResponse(ctx context.Context, response JSONRPCResponse) {
+	responseBody, err := json.Marshal(response)
+	if err != nil {
+		c.sessionLogger().Error("failed to marshal response", logKeyRequestID, response.ID.Value(), logKeyError, err)
+		return
+	}
+
+	resp, err := c.sendHTTP(ctx, http.MethodPost, bytes.NewReader(responseBody), "application/json, text/event-stream")
+	if err != nil {
+		c.sessionLogger().Error("failed to send response", logKeyRequestID, response.ID.Value(), logKeyError, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		body, _ := io.ReadAll(resp.Body)
+		c.sessionLogger().Error("response failed", logKeyRequestID, response.ID.Value(), "status", resp.StatusCode, "body", string(body))
+	}
+}
+
+func newErrorResponse(id mcp.RequestId, code int, message string) JSONRPCResponse {
+	return JSONRPCResponse{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      id,
+		Error: &struct {
+			Code    int             `json:"code"`
+			Message string          `json:"message"`
+			Data    json.RawMessage `json:"data"`
+		}{
+			Code:    code,
+			Message: message,
+		},
+	}
+}
+
 // GetOAuthHandler returns the OAuth handler if configured
 func (c *StreamableHTTP) GetOAuthHandler() *OAuthHandler {
 	return c.oauthHandler
@@ -550,13 +755,19 @@ func (c *StreamableHTTP) IsOAuthEnabled() bool {
 	return c.oauthHandler != nil
 }
 
+// sessionLogger returns the logger of the transport, with the ID of the
+// current session.
+func (c *StreamableHTTP) sessionLogger() *slog.Logger {
+	return c.logger.With(logKeySessionID, c.sessionID.Load())
+}
+
 func (c *StreamableHTTP) listenForever(ctx context.Context) {
-	c.logger.Infof("listening to server forever")
+	c.sessionLogger().Info("listening to server forever")
 	for {
 		err := c.createGETConnectionToServer(ctx)
 		if errors.Is(err, ErrGetMethodNotAllowed) {
 			// server does not support listening
-			c.logger.Errorf("server does not support listening")
+			c.sessionLogger().Error("server does not support listening")
 			return
 		}
 
@@ -567,7 +778,7 @@ func (c *StreamableHTTP) listenForever(ctx context.Context) {
 		}
 
 		if err != nil {
-			c.logger.Errorf("failed to listen to server. retry in 1 second: %v", err)
+			c.sessionLogger().Error("failed to listen to server. retry in 1 second", logKeyError, err)
 		}
 		time.Sleep(retryInterval)
 	}
@@ -578,6 +789,10 @@ var (
 	ErrGetMethodNotAllowed = fmt.Errorf("GET method not allowed")
 
 	retryInterval = 1 * time.Second // a variable is convenient for testing
+
+	// maxResumeAttempts bounds the consecutive attempts to resume an SSE
+	// stream that don't receive any event
+	maxResumeAttempts = 3
 )
 
 func (c *StreamableHTTP) createGETConnectionToServer(ctx context.Context) error {
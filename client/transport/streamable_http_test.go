@@ -9,6 +9,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -781,3 +782,67 @@ func (l *testLogger) Infof(format string, args ...any) {
 func (l *testLogger) Errorf(format string, args ...any) {
 	l.logChan <- fmt.Sprintf(format, args...)
 }
+
+func TestStreamableHTTP_ResumeStream(t *testing.T) {
+	retryInterval = 10 * time.Millisecond
+
+	var lastEventID, pendingResponse atomic.Value
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		switch r.Method {
+		case http.MethodPost:
+			// Drop the stream after the first event
+			var request map[string]any
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, "id: e1\nevent: message\ndata: %s\n\n", `{"jsonrpc":"2.0","method":"test/first"}`)
+			response, _ := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": request["id"], "result": "resumed"})
+			pendingResponse.Store(string(response))
+		case http.MethodGet:
+			lastEventID.Store(r.Header.Get(headerKeyLastEventID))
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, "id: e2\nevent: message\ndata: %s\n\n", pendingResponse.Load())
+		}
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	trans, err := NewStreamableHTTP(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer trans.Close()
+
+	notifications := make(chan string, 1)
+	trans.SetNotificationHandler(func(notification mcp.JSONRPCNotification) {
+		notifications <- notification.Method
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	response, err := trans.SendRequest(ctx, JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      mcp.NewRequestId(int64(7)),
+		Method:  "tools/call",
+	})
+	if err != nil {
+		t.Fatalf("SendRequest failed: %v", err)
+	}
+	if string(response.Result) != `"resumed"` {
+		t.Errorf("Expected the response from the resumed stream, got %s", response.Result)
+	}
+	if got := lastEventID.Load(); got != "e1" {
+		t.Errorf("Expected Last-Event-ID e1, got %v", got)
+	}
+	select {
+	case method := <-notifications:
+		if method != "test/first" {
+			t.Errorf("Expected notification test/first, got %s", method)
+		}
+	default:
+		t.Errorf("Expected the notification sent before the stream was dropped")
+	}
+}
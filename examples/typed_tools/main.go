@@ -8,16 +8,17 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 )
 
-// Define a struct for our typed arguments
+// Define a struct for our typed arguments. The tool's input schema is
+// derived from it, so the schema and the arguments can't drift apart.
 type GreetingArgs struct {
-	Name      string   `json:"name"`
-	Age       int      `json:"age"`
-	IsVIP     bool     `json:"is_vip"`
-	Languages []string `json:"languages"`
-	Metadata  struct {
-		Location string `json:"location"`
-		Timezone string `json:"timezone"`
-	} `json:"metadata"`
+	Name      string   `json:"name" jsonschema:"description=Name of the person to greet"`
+	Age       int      `json:"age,omitempty" jsonschema:"description=Age of the person,minimum=0,maximum=150"`
+	IsVIP     bool     `json:"is_vip,omitempty" jsonschema:"description=Whether the person is a VIP,default=false"`
+	Languages []string `json:"languages,omitempty" jsonschema:"description=Languages the person speaks"`
+	Metadata  *struct {
+		Location string `json:"location,omitempty" jsonschema:"description=Current location"`
+		Timezone string `json:"timezone,omitempty" jsonschema:"description=Timezone"`
+	} `json:"metadata" jsonschema:"description=Additional information about the person"`
 }
 
 func main() {
@@ -28,43 +29,13 @@ func main() {
 		server.WithToolCapabilities(false),
 	)
 
-	// Add tool with complex schema
-	tool := mcp.NewTool("greeting",
+	// Add tool with a schema derived from GreetingArgs
+	tool := mcp.NewTypedTool[GreetingArgs]("greeting",
 		mcp.WithDescription("Generate a personalized greeting"),
-		mcp.WithString("name",
-			mcp.Required(),
-			mcp.Description("Name of the person to greet"),
-		),
-		mcp.WithNumber("age",
-			mcp.Description("Age of the person"),
-			mcp.Min(0),
-			mcp.Max(150),
-		),
-		mcp.WithBoolean("is_vip",
-			mcp.Description("Whether the person is a VIP"),
-			mcp.DefaultBool(false),
-		),
-		mcp.WithArray("languages",
-			mcp.Description("Languages the person speaks"),
-			mcp.Items(map[string]any{"type": "string"}),
-		),
-		mcp.WithObject("metadata",
-			mcp.Description("Additional information about the person"),
-			mcp.Properties(map[string]any{
-				"location": map[string]any{
-					"type":        "string",
-					"description": "Current location",
-				},
-				"timezone": map[string]any{
-					"type":        "string",
-					"description": "Timezone",
-				},
-			}),
-		),
 	)
 
 	// Add tool handler using the typed handler
-	s.AddTool(tool, mcp.NewTypedToolHandler(typedGreetingHandler))
+	server.AddTypedTool(s, tool, typedGreetingHandler)
 
 	// Start the stdio server
 	if err := server.ServeStdio(s); err != nil {
@@ -93,7 +64,7 @@ func typedGreetingHandler(ctx context.Context, request mcp.CallToolRequest, args
 		greeting += fmt.Sprintf(" You speak %d languages: %v.", len(args.Languages), args.Languages)
 	}
 
-	if args.Metadata.Location != "" {
+	if args.Metadata != nil && args.Metadata.Location != "" {
 		greeting += fmt.Sprintf(" I see you're from %s.", args.Metadata.Location)
 
 		if args.Metadata.Timezone != "" {
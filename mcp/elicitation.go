@@ -0,0 +1,132 @@
+package mcp
+
+import (
+	"fmt"
+	"slices"
+)
+
+const (
+	// MethodElicitationCreate allows servers to request additional information from the user via the client
+	// https://modelcontextprotocol.io/specification/2025-06-18/client/elicitation
+	MethodElicitationCreate MCPMethod = "elicitation/create"
+)
+
+// ElicitRequest is a request from the server to elicit additional
+// information from the user via the client.
+type ElicitRequest struct {
+	Request
+	Params ElicitParams `json:"params"`
+}
+
+type ElicitParams struct {
+	// The message to present to the user.
+	Message string `json:"message"`
+	// The shape of the information requested from the user.
+	RequestedSchema ElicitationSchema `json:"requestedSchema"`
+}
+
+// ElicitationSchema is the restricted subset of JSON Schema allowed for
+// elicitation: a flat object whose properties are primitive values.
+type ElicitationSchema struct {
+	Type       string                               `json:"type"` // Must be "object"
+	Properties map[string]PrimitiveSchemaDefinition `json:"properties"`
+	Required   []string                             `json:"required,omitempty"`
+}
+
+// PrimitiveSchemaDefinition describes a single property of an elicitation
+// schema. Type is one of "string", "number", "integer" or "boolean"; the
+// other fields only apply to some of the types.
+type PrimitiveSchemaDefinition struct {
+	Type        string `json:"type"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	// String constraints. Format is one of "email", "uri", "date" or "date-time".
+	MinLength *int   `json:"minLength,omitempty"`
+	MaxLength *int   `json:"maxLength,omitempty"`
+	Format    string `json:"format,omitempty"`
+
+	// Number and integer constraints.
+	Minimum *float64 `json:"minimum,omitempty"`
+	Maximum *float64 `json:"maximum,omitempty"`
+
+	// Enum restricts a string to the given values, with optional display names.
+	Enum      []string `json:"enum,omitempty"`
+	EnumNames []string `json:"enumNames,omitempty"`
+
+	// Default value, only allowed for booleans.
+	Default any `json:"default,omitempty"`
+}
+
+var elicitationStringFormats = []string{"email", "uri", "date", "date-time"}
+
+// Validate reports whether the schema stays within the subset of JSON Schema
+// that clients are required to support for elicitation.
+func (s ElicitationSchema) Validate() error {
+	if s.Type != "object" {
+		return fmt.Errorf("requested schema must be of type object, got %q", s.Type)
+	}
+	for name, property := range s.Properties {
+		if err := property.validate(); err != nil {
+			return fmt.Errorf("property %q: %w", name, err)
+		}
+	}
+	for _, name := range s.Required {
+		if _, ok := s.Properties[name]; !ok {
+			return fmt.Errorf("required property %q is not defined", name)
+		}
+	}
+	return nil
+}
+
+func (p PrimitiveSchemaDefinition) validate() error {
+	switch p.Type {
+	case "string":
+		if p.Format != "" && !slices.Contains(elicitationStringFormats, p.Format) {
+			return fmt.Errorf("unsupported string format %q", p.Format)
+		}
+		if len(p.EnumNames) > 0 && len(p.EnumNames) != len(p.Enum) {
+			return fmt.Errorf("enumNames must have the same length as enum")
+		}
+	case "number", "integer", "boolean":
+		if len(p.Enum) > 0 {
+			return fmt.Errorf("enum is only allowed for strings")
+		}
+	default:
+		return fmt.Errorf("unsupported type %q, must be a primitive type", p.Type)
+	}
+	if p.Type != "string" && (p.MinLength != nil || p.MaxLength != nil || p.Format != "") {
+		return fmt.Errorf("string constraints are not allowed for type %q", p.Type)
+	}
+	if p.Type != "number" && p.Type != "integer" && (p.Minimum != nil || p.Maximum != nil) {
+		return fmt.Errorf("numeric constraints are not allowed for type %q", p.Type)
+	}
+	if p.Default != nil {
+		if _, ok := p.Default.(bool); !ok || p.Type != "boolean" {
+			return fmt.Errorf("default is only allowed for booleans")
+		}
+	}
+	return nil
+}
+
+// ElicitationAction is the user's response to an elicitation request.
+type ElicitationAction string
+
+const (
+	// ElicitationActionAccept means the user submitted the requested information.
+	ElicitationActionAccept ElicitationAction = "accept"
+	// ElicitationActionDecline means the user explicitly declined the request.
+	ElicitationActionDecline ElicitationAction = "decline"
+	// ElicitationActionCancel means the user dismissed the request without choosing.
+	ElicitationActionCancel ElicitationAction = "cancel"
+)
+
+// ElicitResult is the client's response to an elicitation/create request.
+type ElicitResult struct {
+	Result
+	// The user's action in response to the request.
+	Action ElicitationAction `json:"action"`
+	// The submitted data, matching the requested schema. Only present when
+	// the action is "accept".
+	Content map[string]any `json:"content,omitempty"`
+}
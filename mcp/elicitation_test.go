@@ -0,0 +1,90 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestElicitationSchema_Validate(t *testing.T) {
+	minLength := 1
+	minimum := 0.0
+
+	tests := []struct {
+		name    string
+		schema  ElicitationSchema
+		wantErr string
+	}{
+		{
+			name: "valid primitive properties",
+			schema: ElicitationSchema{
+				Type: "object",
+				Properties: map[string]PrimitiveSchemaDefinition{
+					"email":   {Type: "string", Format: "email", MinLength: &minLength},
+					"age":     {Type: "integer", Minimum: &minimum},
+					"confirm": {Type: "boolean", Default: true},
+					"color":   {Type: "string", Enum: []string{"red", "blue"}, EnumNames: []string{"Red", "Blue"}},
+				},
+				Required: []string{"email"},
+			},
+		},
+		{
+			name:    "not an object",
+			schema:  ElicitationSchema{Type: "string"},
+			wantErr: "must be of type object",
+		},
+		{
+			name: "nested object",
+			schema: ElicitationSchema{
+				Type:       "object",
+				Properties: map[string]PrimitiveSchemaDefinition{"address": {Type: "object"}},
+			},
+			wantErr: `property "address": unsupported type "object"`,
+		},
+		{
+			name: "unsupported format",
+			schema: ElicitationSchema{
+				Type:       "object",
+				Properties: map[string]PrimitiveSchemaDefinition{"phone": {Type: "string", Format: "phone"}},
+			},
+			wantErr: `unsupported string format "phone"`,
+		},
+		{
+			name: "string constraint on number",
+			schema: ElicitationSchema{
+				Type:       "object",
+				Properties: map[string]PrimitiveSchemaDefinition{"age": {Type: "number", MinLength: &minLength}},
+			},
+			wantErr: "string constraints are not allowed",
+		},
+		{
+			name: "mismatched enum names",
+			schema: ElicitationSchema{
+				Type: "object",
+				Properties: map[string]PrimitiveSchemaDefinition{
+					"color": {Type: "string", Enum: []string{"red", "blue"}, EnumNames: []string{"Red"}},
+				},
+			},
+			wantErr: "enumNames must have the same length as enum",
+		},
+		{
+			name: "undefined required property",
+			schema: ElicitationSchema{
+				Type:     "object",
+				Required: []string{"name"},
+			},
+			wantErr: `required property "name" is not defined`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.schema.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
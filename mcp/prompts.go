@@ -49,11 +49,15 @@ type GetPromptResult struct {
 type Prompt struct {
 	// The name of the prompt or prompt template.
 	Name string `json:"name"`
+	// A human-readable title for the prompt, intended for UI display.
+	Title string `json:"title,omitempty"`
 	// An optional description of what this prompt provides
 	Description string `json:"description,omitempty"`
 	// A list of arguments to use for templating the prompt.
 	// The presence of arguments indicates this is a template prompt.
 	Arguments []PromptArgument `json:"arguments,omitempty"`
+	// Additional metadata attached to the prompt.
+	Meta map[string]any `json:"_meta,omitempty"`
 }
 
 // GetName returns the name of the prompt.
@@ -72,6 +76,9 @@ type PromptArgument struct {
 	// Whether this argument must be provided.
 	// If true, clients must include this argument when calling prompts/get.
 	Required bool `json:"required,omitempty"`
+	// Enum restricts the argument to a fixed set of values. It is not sent
+	// over the wire; servers use it to answer completion requests.
+	Enum []string `json:"-"`
 }
 
 // Role represents the sender or recipient of messages and data in a
@@ -172,3 +179,11 @@ func RequiredArgument() ArgumentOption {
 		arg.Required = true
 	}
 }
+
+// ArgumentEnum restricts a prompt argument to a fixed set of values.
+// Servers offer these values as completions for the argument.
+func ArgumentEnum(values ...string) ArgumentOption {
+	return func(arg *PromptArgument) {
+		arg.Enum = values
+	}
+}
@@ -9,7 +9,10 @@ import (
 	"strconv"
 )
 
-var errToolSchemaConflict = errors.New("provide either InputSchema or RawInputSchema, not both")
+var (
+	errToolSchemaConflict       = errors.New("provide either InputSchema or RawInputSchema, not both")
+	errToolOutputSchemaConflict = errors.New("provide either OutputSchema or RawOutputSchema, not both")
+)
 
 // ListToolsRequest is sent from the client to request a list of tools the
 // server has.
@@ -42,6 +45,9 @@ type CallToolResult struct {
 	//
 	// If not set, this is assumed to be false (the call was successful).
 	IsError bool `json:"isError,omitempty"`
+	// Structured result of the tool call. If the tool declares an output
+	// schema, this must conform to it.
+	StructuredContent any `json:"structuredContent,omitempty"`
 }
 
 // CallToolRequest is used by the client to invoke a tool provided by the server.
@@ -484,6 +490,11 @@ func (r CallToolResult) MarshalJSON() ([]byte, error) {
 	if r.IsError {
 		m["isError"] = r.IsError
 	}
+
+	// Marshal StructuredContent if present
+	if r.StructuredContent != nil {
+		m["structuredContent"] = r.StructuredContent
+	}
 	
 	return json.Marshal(m)
 }
@@ -526,6 +537,11 @@ func (r *CallToolResult) UnmarshalJSON(data []byte) error {
 			r.IsError = isErrorBool
 		}
 	}
+
+	// Unmarshal StructuredContent
+	if structuredContent, ok := raw["structuredContent"]; ok {
+		r.StructuredContent = structuredContent
+	}
 	
 	return nil
 }
@@ -541,14 +557,24 @@ type ToolListChangedNotification struct {
 type Tool struct {
 	// The name of the tool.
 	Name string `json:"name"`
+	// A human-readable title for the tool, intended for UI display.
+	// It takes precedence over Annotations.Title.
+	Title string `json:"title,omitempty"`
 	// A human-readable description of the tool.
 	Description string `json:"description,omitempty"`
 	// A JSON Schema object defining the expected parameters for the tool.
 	InputSchema ToolInputSchema `json:"inputSchema"`
 	// Alternative to InputSchema - allows arbitrary JSON Schema to be provided
 	RawInputSchema json.RawMessage `json:"-"` // Hide this from JSON marshaling
+	// An optional JSON Schema object defining the structure of the tool's
+	// structured output, returned in CallToolResult.StructuredContent.
+	OutputSchema ToolOutputSchema `json:"outputSchema,omitempty"`
+	// Alternative to OutputSchema - allows arbitrary JSON Schema to be provided
+	RawOutputSchema json.RawMessage `json:"-"` // Hide this from JSON marshaling
 	// Optional properties describing tool behavior
 	Annotations ToolAnnotation `json:"annotations"`
+	// Additional metadata attached to the tool.
+	Meta map[string]any `json:"_meta,omitempty"`
 }
 
 // GetName returns the name of the tool.
@@ -557,13 +583,17 @@ func (t Tool) GetName() string {
 }
 
 // MarshalJSON implements the json.Marshaler interface for Tool.
-// It handles marshaling either InputSchema or RawInputSchema based on which is set.
+// It handles marshaling either InputSchema or RawInputSchema based on which is set,
+// and likewise for OutputSchema and RawOutputSchema.
 func (t Tool) MarshalJSON() ([]byte, error) {
 	// Create a map to build the JSON structure
 	m := make(map[string]any, 3)
 
 	// Add the name and description
 	m["name"] = t.Name
+	if t.Title != "" {
+		m["title"] = t.Title
+	}
 	if t.Description != "" {
 		m["description"] = t.Description
 	}
@@ -579,8 +609,22 @@ func (t Tool) MarshalJSON() ([]byte, error) {
 		m["inputSchema"] = t.InputSchema
 	}
 
+	// The output schema is optional; only include it when one is set
+	if t.RawOutputSchema != nil {
+		if t.OutputSchema.Type != "" {
+			return nil, fmt.Errorf("tool %s has both OutputSchema and RawOutputSchema set: %w", t.Name, errToolOutputSchemaConflict)
+		}
+		m["outputSchema"] = t.RawOutputSchema
+	} else if t.OutputSchema.Type != "" {
+		m["outputSchema"] = t.OutputSchema
+	}
+
 	m["annotations"] = t.Annotations
 
+	if t.Meta != nil {
+		m["_meta"] = t.Meta
+	}
+
 	return json.Marshal(m)
 }
 
@@ -607,6 +651,15 @@ func (tis ToolInputSchema) MarshalJSON() ([]byte, error) {
 	return json.Marshal(m)
 }
 
+// ToolOutputSchema is the JSON Schema describing a tool's structured output.
+// It shares its shape with the input schema.
+type ToolOutputSchema = ToolInputSchema
+
+// HasOutputSchema reports whether the tool declares an output schema.
+func (t Tool) HasOutputSchema() bool {
+	return t.RawOutputSchema != nil || t.OutputSchema.Type != ""
+}
+
 type ToolAnnotation struct {
 	// Human-readable title for the tool
 	Title string `json:"title,omitempty"`
@@ -731,6 +784,32 @@ func WithOpenWorldHintAnnotation(value bool) ToolOption {
 	}
 }
 
+// WithOutputSchema declares an object-type output schema for the Tool.
+// The schema is built with the same property helpers used for the input
+// schema, e.g. WithOutputSchema(WithString("summary", Required())).
+func WithOutputSchema(opts ...ToolOption) ToolOption {
+	return func(t *Tool) {
+		output := Tool{
+			InputSchema: ToolInputSchema{
+				Type:       "object",
+				Properties: make(map[string]any),
+			},
+		}
+		for _, opt := range opts {
+			opt(&output)
+		}
+		t.OutputSchema = output.InputSchema
+	}
+}
+
+// WithRawOutputSchema sets an arbitrary JSON Schema as the Tool's output schema.
+// It must not be combined with WithOutputSchema.
+func WithRawOutputSchema(schema json.RawMessage) ToolOption {
+	return func(t *Tool) {
+		t.RawOutputSchema = schema
+	}
+}
+
 //
 // Common Property Options
 //
@@ -712,3 +712,134 @@ func TestNewItemsAPICompatibility(t *testing.T) {
 		})
 	}
 }
+
+func TestToolWithOutputSchema(t *testing.T) {
+	tool := NewTool("weather",
+		WithDescription("Get the weather"),
+		WithString("city", Required()),
+		WithOutputSchema(
+			WithNumber("temperature", Required(), Description("Temperature in Celsius")),
+			WithString("conditions"),
+		),
+	)
+
+	data, err := json.Marshal(tool)
+	assert.NoError(t, err)
+
+	var result map[string]any
+	err = json.Unmarshal(data, &result)
+	assert.NoError(t, err)
+
+	outputSchema, ok := result["outputSchema"].(map[string]any)
+	assert.True(t, ok)
+	assert.Equal(t, "object", outputSchema["type"])
+	assert.Equal(t, []any{"temperature"}, outputSchema["required"])
+
+	properties, ok := outputSchema["properties"].(map[string]any)
+	assert.True(t, ok)
+	assert.Contains(t, properties, "temperature")
+	assert.Contains(t, properties, "conditions")
+
+	// The input schema is left untouched
+	inputSchema := result["inputSchema"].(map[string]any)
+	assert.Equal(t, []any{"city"}, inputSchema["required"])
+	assert.NotContains(t, inputSchema["properties"], "temperature")
+
+	// Tools without an output schema omit the field
+	data, err = json.Marshal(NewTool("plain"))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "outputSchema")
+
+	// Decoding restores the output schema
+	var decoded Tool
+	err = json.Unmarshal(data, &decoded)
+	assert.NoError(t, err)
+	assert.False(t, decoded.HasOutputSchema())
+
+	data, err = json.Marshal(tool)
+	assert.NoError(t, err)
+	err = json.Unmarshal(data, &decoded)
+	assert.NoError(t, err)
+	assert.True(t, decoded.HasOutputSchema())
+	assert.Equal(t, []string{"temperature"}, decoded.OutputSchema.Required)
+}
+
+func TestToolWithRawOutputSchema(t *testing.T) {
+	rawSchema := json.RawMessage(`{"type":"object","properties":{"count":{"type":"integer"}}}`)
+	tool := NewTool("counter", WithRawOutputSchema(rawSchema))
+
+	data, err := json.Marshal(tool)
+	assert.NoError(t, err)
+
+	var result map[string]any
+	err = json.Unmarshal(data, &result)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"count": map[string]any{"type": "integer"}},
+	}, result["outputSchema"])
+
+	// Mixing the raw schema with the DSL is an error
+	tool.OutputSchema = ToolOutputSchema{Type: "object"}
+	_, err = json.Marshal(tool)
+	assert.ErrorIs(t, err, errToolOutputSchemaConflict)
+}
+
+func TestCallToolResultStructuredContent(t *testing.T) {
+	structured := map[string]any{"temperature": 21.5, "conditions": "sunny"}
+	result := NewToolResultStructured(structured)
+
+	assert.False(t, result.IsError)
+	assert.Equal(t, structured, result.StructuredContent)
+	assert.Len(t, result.Content, 1)
+	text, ok := result.Content[0].(TextContent)
+	assert.True(t, ok)
+	assert.JSONEq(t, `{"temperature":21.5,"conditions":"sunny"}`, text.Text)
+
+	data, err := json.Marshal(result)
+	assert.NoError(t, err)
+
+	var decoded CallToolResult
+	err = json.Unmarshal(data, &decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, structured, decoded.StructuredContent)
+	assert.Equal(t, result.Content, decoded.Content)
+
+	raw := json.RawMessage(data)
+	parsed, err := ParseCallToolResult(&raw)
+	assert.NoError(t, err)
+	assert.Equal(t, structured, parsed.StructuredContent)
+	assert.Equal(t, result.Content, parsed.Content)
+
+	// Results without structured content omit the field
+	data, err = json.Marshal(NewToolResultText("hello"))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "structuredContent")
+}
+
+func TestToolTitleAndMeta(t *testing.T) {
+	tool := NewTool("weather", WithDescription("Get the weather"))
+	tool.Title = "Weather Lookup"
+	tool.Meta = map[string]any{"vendor": "example"}
+
+	data, err := json.Marshal(tool)
+	assert.NoError(t, err)
+
+	var result map[string]any
+	err = json.Unmarshal(data, &result)
+	assert.NoError(t, err)
+	assert.Equal(t, "Weather Lookup", result["title"])
+	assert.Equal(t, map[string]any{"vendor": "example"}, result["_meta"])
+
+	var decoded Tool
+	err = json.Unmarshal(data, &decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, "Weather Lookup", decoded.Title)
+	assert.Equal(t, tool.Meta, decoded.Meta)
+
+	// Both fields are omitted when unset
+	data, err = json.Marshal(NewTool("plain"))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), `"title"`)
+	assert.NotContains(t, string(data), "_meta")
+}
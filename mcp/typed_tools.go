@@ -3,6 +3,10 @@ package mcp
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // TypedToolHandlerFunc is a function that handles a tool call with typed arguments
@@ -18,3 +22,224 @@ func NewTypedToolHandler[T any](handler TypedToolHandlerFunc[T]) func(ctx contex
 		return handler(ctx, request, args)
 	}
 }
+
+// NewTypedTool creates a new Tool whose input schema is derived from the
+// fields of the struct type T, so that the schema and the arguments bound by
+// NewTypedToolHandler share a single definition. Options are applied after
+// the schema is derived, and can add to it.
+//
+// Properties are named after the fields' json tags. A field is required
+// unless it is a pointer or tagged omitempty, or if its jsonschema tag says
+// so. The jsonschema tag holds comma-separated keywords; commas within a
+// value are escaped with a backslash:
+//
+//	type SearchArgs struct {
+//		Query string   `json:"query" jsonschema:"description=Text to search for"`
+//		Order string   `json:"order,omitempty" jsonschema:"enum=asc,enum=desc,default=asc"`
+//		Limit *int     `json:"limit" jsonschema:"minimum=1,maximum=100"`
+//		Tags  []string `json:"tags,omitempty" jsonschema:"minItems=1"`
+//	}
+//
+// Supported keywords are required, description, title, enum (repeated for
+// each value), default, format, pattern, minimum, maximum, minLength,
+// maxLength, minItems and maxItems. Nested structs, slices, maps with string
+// keys and time.Time (a date-time string) are supported.
+//
+// NewTypedTool panics if T is not a struct type.
+func NewTypedTool[T any](name string, opts ...ToolOption) Tool {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("mcp: NewTypedTool requires a struct type, got %s", t))
+	}
+
+	tool := NewTool(name)
+	properties, required := structSchema(t, map[reflect.Type]bool{})
+	tool.InputSchema.Properties = properties
+	tool.InputSchema.Required = required
+
+	for _, opt := range opts {
+		opt(&tool)
+	}
+	return tool
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// structSchema returns the properties and the required properties of the
+// object described by the struct type t. Fields of embedded structs are
+// promoted, like encoding/json does.
+func structSchema(t reflect.Type, visiting map[reflect.Type]bool) (map[string]any, []string) {
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	properties := make(map[string]any)
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		jsonTag := field.Tag.Get("json")
+		if jsonTag == "-" {
+			continue
+		}
+		name, jsonOptions, _ := strings.Cut(jsonTag, ",")
+
+		fieldType := field.Type
+		optional := false
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+			optional = true
+		}
+
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			embedded, embeddedRequired := structSchema(fieldType, visiting)
+			for key, value := range embedded {
+				if _, ok := properties[key]; !ok {
+					properties[key] = value
+				}
+			}
+			required = append(required, embeddedRequired...)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		for _, option := range strings.Split(jsonOptions, ",") {
+			if option == "omitempty" || option == "omitzero" {
+				optional = true
+			}
+		}
+
+		schema := typeSchema(fieldType, visiting)
+		if applySchemaTag(schema, fieldType, field.Tag.Get("jsonschema")) {
+			optional = false
+		}
+		properties[name] = schema
+		if !optional {
+			required = append(required, name)
+		}
+	}
+	return properties, required
+}
+
+// typeSchema returns the JSON Schema of values of type t.
+func typeSchema(t reflect.Type, visiting map[reflect.Type]bool) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// encoding/json encodes byte slices as base64 strings
+			return map[string]any{"type": "string"}
+		}
+		return map[string]any{"type": "array", "items": typeSchema(t.Elem(), visiting)}
+	case reflect.Map:
+		schema := map[string]any{"type": "object"}
+		if t.Key().Kind() == reflect.String {
+			schema["additionalProperties"] = typeSchema(t.Elem(), visiting)
+		}
+		return schema
+	case reflect.Struct:
+		if visiting[t] {
+			// Recursive types are not expanded further
+			return map[string]any{"type": "object"}
+		}
+		properties, required := structSchema(t, visiting)
+		schema := map[string]any{"type": "object", "properties": properties}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema
+	default:
+		// Interfaces accept any value
+		return map[string]any{}
+	}
+}
+
+// applySchemaTag adds the keywords of a jsonschema struct tag to schema, and
+// reports whether the tag marks the property as required.
+func applySchemaTag(schema map[string]any, t reflect.Type, tag string) (required bool) {
+	if tag == "" {
+		return false
+	}
+	var enum []any
+	for _, item := range splitSchemaTag(tag) {
+		key, value, _ := strings.Cut(item, "=")
+		switch key {
+		case "required":
+			required = true
+		case "description", "title", "format", "pattern":
+			schema[key] = value
+		case "enum":
+			enum = append(enum, parseSchemaTagValue(t, value))
+		case "default":
+			schema[key] = parseSchemaTagValue(t, value)
+		case "minimum", "maximum", "minLength", "maxLength", "minItems", "maxItems":
+			if n, err := strconv.ParseFloat(value, 64); err == nil {
+				schema[key] = n
+			}
+		}
+	}
+	if enum != nil {
+		schema["enum"] = enum
+	}
+	return required
+}
+
+// splitSchemaTag splits a jsonschema tag on the commas not escaped with a
+// backslash.
+func splitSchemaTag(tag string) []string {
+	var (
+		items   []string
+		current strings.Builder
+	)
+	for i := 0; i < len(tag); i++ {
+		switch {
+		case tag[i] == '\\' && i+1 < len(tag) && tag[i+1] == ',':
+			current.WriteByte(',')
+			i++
+		case tag[i] == ',':
+			items = append(items, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(tag[i])
+		}
+	}
+	return append(items, current.String())
+}
+
+// parseSchemaTagValue converts a value of a jsonschema tag to the JSON type
+// of the field, falling back to the string itself.
+func parseSchemaTagValue(t reflect.Type, value string) any {
+	switch t.Kind() {
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			return n
+		}
+	}
+	return value
+}
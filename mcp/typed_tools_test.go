@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -302,3 +303,74 @@ func TestTypedToolHandlerWithComplexObjects(t *testing.T) {
 	assert.Contains(t, result.Content[0].(TextContent).Text, "Theme: system")
 	assert.Contains(t, result.Content[0].(TextContent).Text, "Subscribed to 1 newsletters")
 }
+
+func TestNewTypedTool(t *testing.T) {
+	type Address struct {
+		City string `json:"city"`
+	}
+	type Base struct {
+		ID string `json:"id" jsonschema:"description=Identifier"`
+	}
+	type Node struct {
+		Value    int     `json:"value"`
+		Children []*Node `json:"children,omitempty"`
+	}
+	type Args struct {
+		Base
+		Query    string         `json:"query" jsonschema:"description=Text\\, to search for,minLength=2"`
+		Order    string         `json:"order,omitempty" jsonschema:"enum=asc,enum=desc,default=asc"`
+		Limit    *int           `json:"limit" jsonschema:"minimum=1,maximum=100"`
+		Strict   *bool          `json:"strict" jsonschema:"required"`
+		Tags     []string       `json:"tags,omitempty" jsonschema:"minItems=1"`
+		Labels   map[string]int `json:"labels,omitempty"`
+		Address  Address        `json:"address"`
+		Since    time.Time      `json:"since,omitempty"`
+		Tree     Node           `json:"tree,omitempty"`
+		Extra    any            `json:"extra,omitempty"`
+		Ignored  string         `json:"-"`
+		internal string
+		Headers  map[string]string `json:"headers,omitzero"`
+	}
+
+	tool := NewTypedTool[Args]("search", WithDescription("Search things"))
+	assert.Equal(t, "search", tool.Name)
+	assert.Equal(t, "Search things", tool.Description)
+	assert.Equal(t, "object", tool.InputSchema.Type)
+	assert.ElementsMatch(t, []string{"id", "query", "strict", "address"}, tool.InputSchema.Required)
+
+	props := tool.InputSchema.Properties
+	assert.Len(t, props, 12)
+	assert.Equal(t, map[string]any{"type": "string", "description": "Identifier"}, props["id"])
+	assert.Equal(t, map[string]any{"type": "string", "description": "Text, to search for", "minLength": float64(2)}, props["query"])
+	assert.Equal(t, map[string]any{"type": "string", "enum": []any{"asc", "desc"}, "default": "asc"}, props["order"])
+	assert.Equal(t, map[string]any{"type": "integer", "minimum": float64(1), "maximum": float64(100)}, props["limit"])
+	assert.Equal(t, map[string]any{"type": "boolean"}, props["strict"])
+	assert.Equal(t, map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "minItems": float64(1)}, props["tags"])
+	assert.Equal(t, map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "integer"}}, props["labels"])
+	assert.Equal(t, map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"city": map[string]any{"type": "string"}},
+		"required":   []string{"city"},
+	}, props["address"])
+	assert.Equal(t, map[string]any{"type": "string", "format": "date-time"}, props["since"])
+	assert.Equal(t, map[string]any{}, props["extra"])
+	assert.Equal(t, map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"value":    map[string]any{"type": "integer"},
+			"children": map[string]any{"type": "array", "items": map[string]any{"type": "object"}},
+		},
+		"required": []string{"value"},
+	}, props["tree"])
+
+	// The schema is plain JSON Schema
+	data, err := json.Marshal(tool)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"inputSchema":{"properties":`)
+}
+
+func TestNewTypedToolPanicsOnNonStruct(t *testing.T) {
+	assert.Panics(t, func() {
+		NewTypedTool[string]("invalid")
+	})
+}
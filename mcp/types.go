@@ -35,6 +35,14 @@ const (
 	// https://modelcontextprotocol.io/specification/2024-11-05/server/resources/
 	MethodResourcesRead MCPMethod = "resources/read"
 
+	// MethodResourcesSubscribe requests resources/updated notifications for a specific resource URI.
+	// https://modelcontextprotocol.io/specification/2025-03-26/server/resources#subscriptions
+	MethodResourcesSubscribe MCPMethod = "resources/subscribe"
+
+	// MethodResourcesUnsubscribe cancels a previous resources/subscribe request.
+	// https://modelcontextprotocol.io/specification/2025-03-26/server/resources#subscriptions
+	MethodResourcesUnsubscribe MCPMethod = "resources/unsubscribe"
+
 	// MethodPromptsList lists all available prompt templates.
 	// https://modelcontextprotocol.io/specification/2024-11-05/server/prompts/
 	MethodPromptsList MCPMethod = "prompts/list"
@@ -55,10 +63,24 @@ const (
 	// https://modelcontextprotocol.io/specification/2025-03-26/server/utilities/logging
 	MethodSetLogLevel MCPMethod = "logging/setLevel"
 
+	// MethodCompletionComplete requests completion options for a prompt argument or resource template variable.
+	// https://modelcontextprotocol.io/specification/2025-03-26/server/utilities/completion
+	MethodCompletionComplete MCPMethod = "completion/complete"
+
+	// MethodNotificationCancelled notifies the receiver that a previously-issued request is cancelled.
+	// https://modelcontextprotocol.io/specification/2025-03-26/basic/utilities/cancellation
+	MethodNotificationCancelled = "notifications/cancelled"
+
+	// MethodNotificationProgress informs the receiver about the progress of a long-running request.
+	// https://modelcontextprotocol.io/specification/2025-03-26/basic/utilities/progress
+	MethodNotificationProgress = "notifications/progress"
+
 	// MethodNotificationResourcesListChanged notifies when the list of available resources changes.
 	// https://modelcontextprotocol.io/specification/2025-03-26/server/resources#list-changed-notification
 	MethodNotificationResourcesListChanged = "notifications/resources/list_changed"
 
+	// MethodNotificationResourceUpdated notifies subscribed clients that a resource has changed.
+	// https://modelcontextprotocol.io/specification/2025-03-26/server/resources#subscriptions
 	MethodNotificationResourceUpdated = "notifications/resources/updated"
 
 	// MethodNotificationPromptsListChanged notifies when the list of available prompt templates changes.
@@ -97,14 +119,21 @@ func (t *URITemplate) UnmarshalJSON(data []byte) error {
 type JSONRPCMessage any
 
 // LATEST_PROTOCOL_VERSION is the most recent version of the MCP protocol.
-const LATEST_PROTOCOL_VERSION = "2025-03-26"
+const LATEST_PROTOCOL_VERSION = "2025-06-18"
 
 // ValidProtocolVersions lists all known valid MCP protocol versions.
 var ValidProtocolVersions = []string{
 	"2024-11-05",
+	"2025-03-26",
 	LATEST_PROTOCOL_VERSION,
 }
 
+// ProtocolVersionAllowsBatching reports whether JSON-RPC batches may be sent
+// under the given protocol version. Batching was removed in 2025-06-18.
+func ProtocolVersionAllowsBatching(protocolVersion string) bool {
+	return protocolVersion < "2025-06-18"
+}
+
 // JSONRPC_VERSION is the version of JSON-RPC used by MCP.
 const JSONRPC_VERSION = "2.0"
 
@@ -356,6 +385,12 @@ const (
 // MCP error codes
 const (
 	RESOURCE_NOT_FOUND = -32002
+	// SERVER_BUSY is returned when the server rejects a request because it
+	// is already handling as many similar requests as it allows.
+	SERVER_BUSY = -32003
+	// RATE_LIMITED is returned when a request exceeds a rate limit or quota
+	// of the server.
+	RATE_LIMITED = -32004
 )
 
 /* Empty result */
@@ -448,6 +483,8 @@ type ClientCapabilities struct {
 	} `json:"roots,omitempty"`
 	// Present if the client supports sampling from an LLM.
 	Sampling *struct{} `json:"sampling,omitempty"`
+	// Present if the client supports eliciting information from the user.
+	Elicitation *struct{} `json:"elicitation,omitempty"`
 }
 
 // ServerCapabilities represents capabilities that a server may support. Known
@@ -471,6 +508,8 @@ type ServerCapabilities struct {
 		// list.
 		ListChanged bool `json:"listChanged,omitempty"`
 	} `json:"resources,omitempty"`
+	// Present if the server supports argument autocompletion suggestions.
+	Completions *struct{} `json:"completions,omitempty"`
 	// Present if the server offers any tools to call.
 	Tools *struct {
 		// Whether this server supports notifications for changes to the tool list.
@@ -480,8 +519,12 @@ type ServerCapabilities struct {
 
 // Implementation describes the name and version of an MCP implementation.
 type Implementation struct {
-	Name    string `json:"name"`
+	Name string `json:"name"`
+	// A human-readable title, intended for UI display.
+	Title   string `json:"title,omitempty"`
 	Version string `json:"version"`
+	// Additional metadata attached to the implementation.
+	Meta map[string]any `json:"_meta,omitempty"`
 }
 
 /* Ping */
@@ -649,6 +692,9 @@ type Resource struct {
 	//
 	// This can be used by clients to populate UI elements.
 	Name string `json:"name"`
+	// A human-readable title for this resource, intended for UI display.
+	// If not provided, the name should be used for display.
+	Title string `json:"title,omitempty"`
 	// A description of what this resource represents.
 	//
 	// This can be used by clients to improve the LLM's understanding of
@@ -656,6 +702,8 @@ type Resource struct {
 	Description string `json:"description,omitempty"`
 	// The MIME type of this resource, if known.
 	MIMEType string `json:"mimeType,omitempty"`
+	// Additional metadata attached to the resource.
+	Meta map[string]any `json:"_meta,omitempty"`
 }
 
 // GetName returns the name of the resource.
@@ -1033,6 +1081,16 @@ type PromptReference struct {
 
 /* Roots */
 
+const (
+	// MethodListRoots allows servers to request the list of root URIs from clients
+	// https://modelcontextprotocol.io/specification/2025-03-26/client/roots
+	MethodListRoots MCPMethod = "roots/list"
+
+	// MethodNotificationRootsListChanged notifies the server that the client's list of roots has changed
+	// https://modelcontextprotocol.io/specification/2025-03-26/client/roots#root-list-changes
+	MethodNotificationRootsListChanged = "notifications/roots/list_changed"
+)
+
 // ListRootsRequest is sent from the server to request a list of root URIs from the client. Roots allow
 // servers to ask for specific directories or files to operate on. A common example
 // for roots is providing a set of repositories or directories a server should operate
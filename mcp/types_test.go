@@ -138,3 +138,35 @@ func TestCallToolResultWithResourceLink(t *testing.T) {
 	assert.Equal(t, "A test document", resourceLink.Description)
 	assert.Equal(t, "application/pdf", resourceLink.MIMEType)
 }
+
+func TestProtocolVersionAllowsBatching(t *testing.T) {
+	assert.True(t, ProtocolVersionAllowsBatching("2024-11-05"))
+	assert.True(t, ProtocolVersionAllowsBatching("2025-03-26"))
+	assert.False(t, ProtocolVersionAllowsBatching("2025-06-18"))
+	assert.Contains(t, ValidProtocolVersions, "2025-06-18")
+}
+
+func TestTitleAndMetaSerialization(t *testing.T) {
+	meta := map[string]any{"vendor": "example"}
+
+	tests := []struct {
+		name  string
+		value any
+	}{
+		{name: "implementation", value: Implementation{Name: "server", Title: "Server", Version: "1.0.0", Meta: meta}},
+		{name: "resource", value: Resource{URI: "file:///a", Name: "a", Title: "A", Meta: meta}},
+		{name: "prompt", value: Prompt{Name: "greet", Title: "Greet", Meta: meta}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.value)
+			require.NoError(t, err)
+
+			var raw map[string]any
+			require.NoError(t, json.Unmarshal(data, &raw))
+			assert.NotEmpty(t, raw["title"])
+			assert.Equal(t, meta, raw["_meta"])
+		})
+	}
+}
@@ -253,6 +253,26 @@ func NewToolResultText(text string) *CallToolResult {
 	}
 }
 
+// NewToolResultStructured creates a new CallToolResult carrying structured
+// content. The JSON encoding of the value is also included as text content
+// for clients that do not support structured output.
+func NewToolResultStructured(structured any) *CallToolResult {
+	data, err := json.Marshal(structured)
+	if err != nil {
+		return NewToolResultErrorFromErr("failed to marshal structured content", err)
+	}
+
+	return &CallToolResult{
+		Content: []Content{
+			TextContent{
+				Type: "text",
+				Text: string(data),
+			},
+		},
+		StructuredContent: structured,
+	}
+}
+
 // NewToolResultImage creates a new CallToolResult with both text and image content
 func NewToolResultImage(text, imageData, mimeType string) *CallToolResult {
 	return &CallToolResult{
@@ -606,6 +626,10 @@ func ParseCallToolResult(rawMessage *json.RawMessage) (*CallToolResult, error) {
 		}
 	}
 
+	if structuredContent, ok := jsonContent["structuredContent"]; ok {
+		result.StructuredContent = structuredContent
+	}
+
 	contents, ok := jsonContent["content"]
 	if !ok {
 		return nil, fmt.Errorf("content is missing")
@@ -0,0 +1,337 @@
+// Package metrics records the metrics of an MCP server and serves them in
+// the Prometheus text exposition format.
+//
+// Create a Metrics with New, pass it to server.WithMetrics and mount its
+// Handler, e.g. next to the server's StreamableHTTPServer:
+//
+//	m := metrics.New()
+//	s := server.NewMCPServer("demo", "1.0.0", server.WithMetrics(m))
+//	mux := http.NewServeMux()
+//	mux.Handle("/mcp", server.NewStreamableHTTPServer(s))
+//	mux.Handle("/metrics", m.Handler())
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultNamespace is the prefix of the metric names.
+const DefaultNamespace = "mcp"
+
+// DefaultBuckets are the upper bounds, in seconds, of the latency histogram
+// buckets. They are the same as the Prometheus client defaults.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// contentType is the content type of the Prometheus text exposition format.
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Metrics records the metrics of an MCP server. It is safe for concurrent
+// use, and all its recording methods are no-ops on a nil *Metrics.
+type Metrics struct {
+	namespace string
+	buckets   []float64
+
+	mu                sync.Mutex
+	requests          map[requestKey]*histogram
+	errors            map[errorKey]uint64
+	activeSessions    map[string]int64
+	notificationDrops map[string]uint64
+	sampling          *histogram
+}
+
+// Option configures a Metrics.
+type Option func(*Metrics)
+
+// WithNamespace sets the prefix of the metric names, DefaultNamespace by
+// default.
+func WithNamespace(namespace string) Option {
+	return func(m *Metrics) {
+		m.namespace = namespace
+	}
+}
+
+// WithBuckets sets the upper bounds, in seconds, of the latency histogram
+// buckets, DefaultBuckets by default.
+func WithBuckets(buckets []float64) Option {
+	return func(m *Metrics) {
+		m.buckets = append([]float64(nil), buckets...)
+		sort.Float64s(m.buckets)
+	}
+}
+
+// New creates an empty Metrics.
+func New(opts ...Option) *Metrics {
+	m := &Metrics{
+		namespace:         DefaultNamespace,
+		buckets:           DefaultBuckets,
+		requests:          make(map[requestKey]*histogram),
+		errors:            make(map[errorKey]uint64),
+		activeSessions:    make(map[string]int64),
+		notificationDrops: make(map[string]uint64),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	m.sampling = newHistogram(m.buckets)
+	return m
+}
+
+type requestKey struct {
+	method string
+	tool   string
+}
+
+type errorKey struct {
+	method string
+	code   int
+}
+
+// ObserveRequest records a request handled in the given duration. tool is
+// the name of the called tool for tools/call requests, and empty otherwise.
+// Each method and tool creates a series that is kept forever, so they must
+// not be taken from requests unchecked.
+func (m *Metrics) ObserveRequest(method, tool string, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := requestKey{method: method, tool: tool}
+	h, ok := m.requests[key]
+	if !ok {
+		h = newHistogram(m.buckets)
+		m.requests[key] = h
+	}
+	h.observe(duration.Seconds())
+}
+
+// ObserveError records a request answered with a JSON-RPC error.
+func (m *Metrics) ObserveError(method string, code int) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.errors[errorKey{method: method, code: code}]++
+}
+
+// SessionOpened records a session opened on the given transport.
+func (m *Metrics) SessionOpened(transport string) {
+	m.addSessions(transport, 1)
+}
+
+// SessionClosed records a session closed on the given transport.
+func (m *Metrics) SessionClosed(transport string) {
+	m.addSessions(transport, -1)
+}
+
+func (m *Metrics) addSessions(transport string, delta int64) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.activeSessions[transport] += delta
+}
+
+// NotificationDropped records a notification dropped because the channel of
+// the session was full.
+func (m *Metrics) NotificationDropped(method string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.notificationDrops[method]++
+}
+
+// ObserveSampling records the round-trip time of a sampling request sent to
+// a client.
+func (m *Metrics) ObserveSampling(duration time.Duration) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sampling.observe(duration.Seconds())
+}
+
+// Handler returns an http.Handler serving the metrics in the Prometheus text
+// exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", contentType)
+		if r.Method == http.MethodHead {
+			return
+		}
+		_, _ = m.WriteTo(w)
+	})
+}
+
+// WriteTo writes the metrics to w in the Prometheus text exposition format.
+// Series are sorted by labels so that the output is deterministic.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	m.mu.Lock()
+	m.write(&buf)
+	m.mu.Unlock()
+	return buf.WriteTo(w)
+}
+
+func (m *Metrics) write(buf *bytes.Buffer) {
+	requestKeys := make([]requestKey, 0, len(m.requests))
+	for key := range m.requests {
+		requestKeys = append(requestKeys, key)
+	}
+	sort.Slice(requestKeys, func(i, j int) bool {
+		if requestKeys[i].method != requestKeys[j].method {
+			return requestKeys[i].method < requestKeys[j].method
+		}
+		return requestKeys[i].tool < requestKeys[j].tool
+	})
+
+	name := m.name("requests_total")
+	writeHeader(buf, name, "counter", "Total number of requests handled, by method and tool.")
+	for _, key := range requestKeys {
+		writeSample(buf, name, labels("method", key.method, "tool", key.tool), float64(m.requests[key].count))
+	}
+
+	name = m.name("request_duration_seconds")
+	writeHeader(buf, name, "histogram", "Duration of the handling of requests, by method and tool.")
+	for _, key := range requestKeys {
+		m.requests[key].write(buf, name, labels("method", key.method, "tool", key.tool))
+	}
+
+	errorKeys := make([]errorKey, 0, len(m.errors))
+	for key := range m.errors {
+		errorKeys = append(errorKeys, key)
+	}
+	sort.Slice(errorKeys, func(i, j int) bool {
+		if errorKeys[i].method != errorKeys[j].method {
+			return errorKeys[i].method < errorKeys[j].method
+		}
+		return errorKeys[i].code < errorKeys[j].code
+	})
+
+	name = m.name("request_errors_total")
+	writeHeader(buf, name, "counter", "Total number of requests answered with a JSON-RPC error, by method and error code.")
+	for _, key := range errorKeys {
+		writeSample(buf, name, labels("method", key.method, "code", strconv.Itoa(key.code)), float64(m.errors[key]))
+	}
+
+	name = m.name("active_sessions")
+	writeHeader(buf, name, "gauge", "Number of active sessions, by transport.")
+	for _, transport := range sortedKeys(m.activeSessions) {
+		writeSample(buf, name, labels("transport", transport), float64(m.activeSessions[transport]))
+	}
+
+	name = m.name("notifications_dropped_total")
+	writeHeader(buf, name, "counter", "Total number of notifications dropped because the session channel was full, by method.")
+	for _, method := range sortedKeys(m.notificationDrops) {
+		writeSample(buf, name, labels("method", method), float64(m.notificationDrops[method]))
+	}
+
+	name = m.name("sampling_duration_seconds")
+	writeHeader(buf, name, "histogram", "Round-trip time of the sampling requests sent to clients.")
+	m.sampling.write(buf, name, "")
+}
+
+func (m *Metrics) name(suffix string) string {
+	if m.namespace == "" {
+		return suffix
+	}
+	return m.namespace + "_" + suffix
+}
+
+// histogram is a cumulative histogram, as exposed by Prometheus.
+type histogram struct {
+	bounds []float64
+	counts []uint64 // per bucket, not cumulative
+	count  uint64
+	sum    float64
+}
+
+func newHistogram(bounds []float64) *histogram {
+	return &histogram{bounds: bounds, counts: make([]uint64, len(bounds))}
+}
+
+func (h *histogram) observe(v float64) {
+	h.count++
+	h.sum += v
+	if i := sort.SearchFloat64s(h.bounds, v); i < len(h.bounds) {
+		h.counts[i]++
+	}
+}
+
+func (h *histogram) write(buf *bytes.Buffer, name, labels string) {
+	sep := ""
+	if labels != "" {
+		sep = ","
+	}
+	var cumulative uint64
+	for i, bound := range h.bounds {
+		cumulative += h.counts[i]
+		writeSample(buf, name+"_bucket", labels+sep+`le="`+formatFloat(bound)+`"`, float64(cumulative))
+	}
+	writeSample(buf, name+"_bucket", labels+sep+`le="+Inf"`, float64(h.count))
+	writeSample(buf, name+"_sum", labels, h.sum)
+	writeSample(buf, name+"_count", labels, float64(h.count))
+}
+
+func writeHeader(buf *bytes.Buffer, name, typ, help string) {
+	fmt.Fprintf(buf, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+func writeSample(buf *bytes.Buffer, name, labels string, value float64) {
+	buf.WriteString(name)
+	if labels != "" {
+		buf.WriteString("{" + labels + "}")
+	}
+	buf.WriteString(" " + formatFloat(value) + "\n")
+}
+
+// labels formats the given name/value pairs as Prometheus labels.
+func labels(pairs ...string) string {
+	parts := make([]string, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		parts = append(parts, pairs[i]+`="`+labelEscaper.Replace(pairs[i+1])+`"`)
+	}
+	return strings.Join(parts, ",")
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
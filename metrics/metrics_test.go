@@ -0,0 +1,116 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics_WriteTo(t *testing.T) {
+	m := New(WithBuckets([]float64{1, 0.1}))
+	m.ObserveRequest("tools/call", "echo", 50*time.Millisecond)
+	m.ObserveRequest("tools/call", "echo", 500*time.Millisecond)
+	m.ObserveRequest("tools/call", "echo", 2*time.Second)
+	m.ObserveRequest("ping", "", 100*time.Millisecond)
+	m.ObserveError("tools/call", -32004)
+	m.ObserveError("tools/call", -32004)
+	m.ObserveError("tools/call", -32602)
+	m.SessionOpened("sse")
+	m.SessionOpened("sse")
+	m.SessionOpened("stdio")
+	m.SessionClosed("stdio")
+	m.NotificationDropped("notifications/progress")
+	m.ObserveSampling(250 * time.Millisecond)
+
+	var out strings.Builder
+	_, err := m.WriteTo(&out)
+	require.NoError(t, err)
+
+	assert.Equal(t, `# HELP mcp_requests_total Total number of requests handled, by method and tool.
+# TYPE mcp_requests_total counter
+mcp_requests_total{method="ping",tool=""} 1
+mcp_requests_total{method="tools/call",tool="echo"} 3
+# HELP mcp_request_duration_seconds Duration of the handling of requests, by method and tool.
+# TYPE mcp_request_duration_seconds histogram
+mcp_request_duration_seconds_bucket{method="ping",tool="",le="0.1"} 1
+mcp_request_duration_seconds_bucket{method="ping",tool="",le="1"} 1
+mcp_request_duration_seconds_bucket{method="ping",tool="",le="+Inf"} 1
+mcp_request_duration_seconds_sum{method="ping",tool=""} 0.1
+mcp_request_duration_seconds_count{method="ping",tool=""} 1
+mcp_request_duration_seconds_bucket{method="tools/call",tool="echo",le="0.1"} 1
+mcp_request_duration_seconds_bucket{method="tools/call",tool="echo",le="1"} 2
+mcp_request_duration_seconds_bucket{method="tools/call",tool="echo",le="+Inf"} 3
+mcp_request_duration_seconds_sum{method="tools/call",tool="echo"} 2.55
+mcp_request_duration_seconds_count{method="tools/call",tool="echo"} 3
+# HELP mcp_request_errors_total Total number of requests answered with a JSON-RPC error, by method and error code.
+# TYPE mcp_request_errors_total counter
+mcp_request_errors_total{method="tools/call",code="-32602"} 1
+mcp_request_errors_total{method="tools/call",code="-32004"} 2
+# HELP mcp_active_sessions Number of active sessions, by transport.
+# TYPE mcp_active_sessions gauge
+mcp_active_sessions{transport="sse"} 2
+mcp_active_sessions{transport="stdio"} 0
+# HELP mcp_notifications_dropped_total Total number of notifications dropped because the session channel was full, by method.
+# TYPE mcp_notifications_dropped_total counter
+mcp_notifications_dropped_total{method="notifications/progress"} 1
+# HELP mcp_sampling_duration_seconds Round-trip time of the sampling requests sent to clients.
+# TYPE mcp_sampling_duration_seconds histogram
+mcp_sampling_duration_seconds_bucket{le="0.1"} 0
+mcp_sampling_duration_seconds_bucket{le="1"} 1
+mcp_sampling_duration_seconds_bucket{le="+Inf"} 1
+mcp_sampling_duration_seconds_sum 0.25
+mcp_sampling_duration_seconds_count 1
+`, out.String())
+}
+
+func TestMetrics_Namespace(t *testing.T) {
+	m := New(WithNamespace("demo"))
+	m.NotificationDropped(`a"b\c`)
+
+	var out strings.Builder
+	_, err := m.WriteTo(&out)
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), `demo_notifications_dropped_total{method="a\"b\\c"} 1`)
+	assert.NotContains(t, out.String(), "mcp_")
+}
+
+func TestMetrics_Nil(t *testing.T) {
+	var m *Metrics
+	assert.NotPanics(t, func() {
+		m.ObserveRequest("ping", "", time.Second)
+		m.ObserveError("ping", -32603)
+		m.SessionOpened("sse")
+		m.SessionClosed("sse")
+		m.NotificationDropped("notifications/message")
+		m.ObserveSampling(time.Second)
+	})
+}
+
+func TestMetrics_Handler(t *testing.T) {
+	m := New()
+	m.ObserveRequest("ping", "", time.Millisecond)
+
+	server := httptest.NewServer(m.Handler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", resp.Header.Get("Content-Type"))
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `mcp_requests_total{method="ping",tool=""} 1`)
+
+	resp, err = http.Post(server.URL, "text/plain", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
@@ -0,0 +1,257 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ProtectedResourceMetadataPath is the well-known path of the OAuth 2.0
+// protected resource metadata (RFC 9728). For a resource with a path, the
+// path of the resource is appended to it.
+const ProtectedResourceMetadataPath = "/.well-known/oauth-protected-resource"
+
+// AuthInfo is the identity of the bearer of a verified access token.
+type AuthInfo struct {
+	// Subject identifies the resource owner, e.g. the user.
+	Subject string
+	// ClientID identifies the OAuth client the token was issued to.
+	ClientID string
+	// Scopes are the scopes granted to the token.
+	Scopes []string
+	// ExpiresAt is the expiration time of the token. Tokens past it are
+	// rejected; the zero value means no expiration.
+	ExpiresAt time.Time
+	// Extra holds any other claim of the token.
+	Extra map[string]any
+}
+
+// HasScope reports whether the token was granted the given scope.
+func (a *AuthInfo) HasScope(scope string) bool {
+	return a != nil && slices.Contains(a.Scopes, scope)
+}
+
+// TokenVerifier verifies the bearer tokens of the requests to a server, for
+// instance by validating a JWT or by introspection (RFC 7662).
+//
+// VerifyToken must check that the token was issued for this server, i.e.
+// that its audience is the resource of the server (RFC 8707), and return an
+// error, ideally wrapping ErrInvalidToken, if it is not valid.
+type TokenVerifier interface {
+	VerifyToken(ctx context.Context, token string) (*AuthInfo, error)
+}
+
+// TokenVerifierFunc is an adapter to use a function as a TokenVerifier.
+type TokenVerifierFunc func(ctx context.Context, token string) (*AuthInfo, error)
+
+// VerifyToken calls f(ctx, token).
+func (f TokenVerifierFunc) VerifyToken(ctx context.Context, token string) (*AuthInfo, error) {
+	return f(ctx, token)
+}
+
+// ProtectedResourceMetadata is the OAuth 2.0 protected resource metadata of a
+// server (RFC 9728), which tells clients where to obtain access tokens.
+type ProtectedResourceMetadata struct {
+	// Resource is the URL identifying the server, e.g. "https://example.com/mcp".
+	Resource string `json:"resource"`
+	// AuthorizationServers are the issuer URLs of the authorization servers
+	// issuing tokens for the server.
+	AuthorizationServers []string `json:"authorization_servers,omitempty"`
+	ScopesSupported      []string `json:"scopes_supported,omitempty"`
+	// BearerMethodsSupported defaults to "header", the only method accepted.
+	BearerMethodsSupported []string `json:"bearer_methods_supported,omitempty"`
+	ResourceName           string   `json:"resource_name,omitempty"`
+	ResourceDocumentation  string   `json:"resource_documentation,omitempty"`
+}
+
+// AuthConfig configures the authorization of the requests to a server acting
+// as an OAuth 2.1 resource server.
+type AuthConfig struct {
+	// Verifier verifies the bearer tokens. Without it, all requests are
+	// rejected.
+	Verifier TokenVerifier
+	// Metadata is served at the well-known metadata URL of the resource.
+	Metadata ProtectedResourceMetadata
+	// RequiredScopes are the scopes a token must have been granted for any
+	// request. Requests with tokens lacking one are rejected with 403.
+	RequiredScopes []string
+	// ResourceMetadataURL is the URL of the metadata, advertised in the
+	// WWW-Authenticate header of rejected requests. It defaults to the
+	// well-known URL derived from Metadata.Resource. If neither is an
+	// absolute URL, it is resolved against the host the request was sent to,
+	// which behind a proxy may not be the one clients use.
+	ResourceMetadataURL string
+}
+
+type authInfoKey struct{}
+
+// ContextWithAuthInfo returns a copy of ctx holding the identity of the
+// bearer of the request's access token.
+func ContextWithAuthInfo(ctx context.Context, info *AuthInfo) context.Context {
+	return context.WithValue(ctx, authInfoKey{}, info)
+}
+
+// AuthInfoFromContext returns the identity of the bearer of the request's
+// access token, or nil if the request was not authorized with a token.
+func AuthInfoFromContext(ctx context.Context) *AuthInfo {
+	info, _ := ctx.Value(authInfoKey{}).(*AuthInfo)
+	return info
+}
+
+// ProtectedResourceMetadataHandler returns an http.Handler serving the
+// protected resource metadata of config. Servers configured with
+// authorization serve it themselves on the well-known path; this handler is
+// for mounting it elsewhere, e.g. on a router with a dynamic base path.
+func ProtectedResourceMetadataHandler(config AuthConfig) http.Handler {
+	return http.HandlerFunc(newAuthorizer(config).serveMetadata)
+}
+
+// authorizer authorizes requests with bearer tokens, as configured.
+type authorizer struct {
+	config       AuthConfig
+	metadataURL  string
+	metadataPath string
+}
+
+func newAuthorizer(config AuthConfig) *authorizer {
+	a := &authorizer{config: config}
+	if len(a.config.Metadata.BearerMethodsSupported) == 0 {
+		a.config.Metadata.BearerMethodsSupported = []string{"header"}
+	}
+
+	// The metadata of https://example.com/mcp is found at
+	// https://example.com/.well-known/oauth-protected-resource/mcp
+	a.metadataPath = ProtectedResourceMetadataPath
+	if resource, err := url.Parse(config.Metadata.Resource); err == nil {
+		a.metadataPath += strings.TrimSuffix(resource.EscapedPath(), "/")
+		if resource.Scheme != "" && resource.Host != "" {
+			a.metadataURL = resource.Scheme + "://" + resource.Host + a.metadataPath
+		}
+	}
+	if config.ResourceMetadataURL != "" {
+		a.metadataURL = config.ResourceMetadataURL
+		if u, err := url.Parse(config.ResourceMetadataURL); err == nil {
+			a.metadataPath = u.EscapedPath()
+		}
+	}
+	return a
+}
+
+// authorize verifies the bearer token of r. It returns r with the identity
+// of the bearer in its context, or writes an error response and returns
+// false if the request is not authorized.
+func (a *authorizer) authorize(w http.ResponseWriter, r *http.Request) (*http.Request, bool) {
+	token, ok := bearerToken(r)
+	if !ok {
+		a.writeError(w, r, http.StatusUnauthorized, "", "missing bearer token")
+		return r, false
+	}
+	if a.config.Verifier == nil {
+		a.writeError(w, r, http.StatusUnauthorized, "invalid_token", "no token verifier configured")
+		return r, false
+	}
+
+	info, err := a.config.Verifier.VerifyToken(r.Context(), token)
+	switch {
+	case err != nil, info == nil:
+		// The error of the verifier may tell more than the client should know
+		a.writeError(w, r, http.StatusUnauthorized, "invalid_token", ErrInvalidToken.Error())
+		return r, false
+	case !info.ExpiresAt.IsZero() && time.Now().After(info.ExpiresAt):
+		a.writeError(w, r, http.StatusUnauthorized, "invalid_token", "token expired")
+		return r, false
+	}
+	for _, scope := range a.config.RequiredScopes {
+		if !info.HasScope(scope) {
+			a.writeError(w, r, http.StatusForbidden, "insufficient_scope", fmt.Sprintf("missing scope %q", scope))
+			return r, false
+		}
+	}
+
+	return r.WithContext(ContextWithAuthInfo(r.Context(), info)), true
+}
+
+// writeError rejects a request with a WWW-Authenticate challenge pointing to
+// the resource metadata (RFC 6750 and RFC 9728).
+func (a *authorizer) writeError(w http.ResponseWriter, r *http.Request, status int, code, description string) {
+	params := []string{authParam("resource_metadata", a.resourceMetadataURL(r))}
+	if code != "" {
+		params = append(params, authParam("error", code), authParam("error_description", description))
+	}
+	if len(a.config.RequiredScopes) > 0 {
+		params = append(params, authParam("scope", strings.Join(a.config.RequiredScopes, " ")))
+	}
+	w.Header().Set("WWW-Authenticate", "Bearer "+strings.Join(params, ", "))
+	http.Error(w, description, status)
+}
+
+// resourceMetadataURL returns the absolute URL of the metadata, resolving
+// it against the URL r was sent to if it was not configured as one.
+func (a *authorizer) resourceMetadataURL(r *http.Request) string {
+	metadataURL := a.metadataURL
+	if metadataURL == "" {
+		metadataURL = a.metadataPath
+	}
+	u, err := url.Parse(metadataURL)
+	if err != nil || u.IsAbs() {
+		return metadataURL
+	}
+	base := &url.URL{Scheme: "http", Host: r.Host}
+	if r.TLS != nil {
+		base.Scheme = "https"
+	}
+	return base.ResolveReference(u).String()
+}
+
+// serveMetadata serves the protected resource metadata.
+func (a *authorizer) serveMetadata(w http.ResponseWriter, r *http.Request) {
+	// Browser-based clients fetch the metadata from another origin
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+	case http.MethodOptions:
+		w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodGet {
+		_ = json.NewEncoder(w).Encode(a.config.Metadata)
+	}
+}
+
+// sameIdentity reports whether two verified tokens were issued to the same
+// subject and client.
+func sameIdentity(a, b *AuthInfo) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Subject == b.Subject && a.ClientID == b.ClientID
+}
+
+// bearerToken returns the bearer token of the Authorization header of r.
+// Tokens in the query string are not accepted, as required by OAuth 2.1.
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+var authParamEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+func authParam(name, value string) string {
+	return name + `="` + authParamEscaper.Replace(value) + `"`
+}
@@ -0,0 +1,313 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// testTokens are the tokens accepted by testVerifier.
+var testTokens = map[string]*AuthInfo{
+	"alice-token":   {Subject: "alice", ClientID: "app", Scopes: []string{"mcp:read", "mcp:write"}},
+	"bob-token":     {Subject: "bob", ClientID: "app", Scopes: []string{"mcp:read", "mcp:write"}},
+	"reader-token":  {Subject: "carol", ClientID: "app", Scopes: []string{"mcp:read"}},
+	"expired-token": {Subject: "dave", ClientID: "app", Scopes: []string{"mcp:read", "mcp:write"}, ExpiresAt: time.Now().Add(-time.Minute)},
+}
+
+var testVerifier = TokenVerifierFunc(func(ctx context.Context, token string) (*AuthInfo, error) {
+	if info, ok := testTokens[token]; ok {
+		return info, nil
+	}
+	return nil, fmt.Errorf("unknown token: %w", ErrInvalidToken)
+})
+
+func testAuthConfig() AuthConfig {
+	return AuthConfig{
+		Verifier: testVerifier,
+		Metadata: ProtectedResourceMetadata{
+			Resource:             "https://mcp.example.com/mcp",
+			AuthorizationServers: []string{"https://auth.example.com"},
+			ScopesSupported:      []string{"mcp:read", "mcp:write"},
+		},
+		RequiredScopes: []string{"mcp:write"},
+	}
+}
+
+func newAuthTestServer() *MCPServer {
+	mcpServer := NewMCPServer("test-server", "1.0.0")
+	mcpServer.AddTool(mcp.NewTool("whoami"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		info := AuthInfoFromContext(ctx)
+		if info == nil {
+			return mcp.NewToolResultError("anonymous"), nil
+		}
+		return mcp.NewToolResultText(info.Subject), nil
+	})
+	return mcpServer
+}
+
+func postWithToken(t *testing.T, url, token string, body string, headers map[string]string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	return resp
+}
+
+func TestStreamableHTTPServer_Auth(t *testing.T) {
+	server := NewTestStreamableHTTPServer(newAuthTestServer(), WithStreamableHTTPAuth(testAuthConfig()))
+	defer server.Close()
+	endpoint := server.URL + "/mcp"
+	metadataURL := "https://mcp.example.com/.well-known/oauth-protected-resource/mcp"
+
+	t.Run("serves the protected resource metadata", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/.well-known/oauth-protected-resource/mcp")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+		assert.Equal(t, "*", resp.Header.Get("Access-Control-Allow-Origin"))
+
+		var metadata map[string]any
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&metadata))
+		assert.Equal(t, map[string]any{
+			"resource":                 "https://mcp.example.com/mcp",
+			"authorization_servers":    []any{"https://auth.example.com"},
+			"scopes_supported":         []any{"mcp:read", "mcp:write"},
+			"bearer_methods_supported": []any{"header"},
+		}, metadata)
+	})
+
+	initialize := `{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {"protocolVersion": "2025-03-26", "clientInfo": {"name": "test", "version": "1.0.0"}}}`
+
+	tests := []struct {
+		name      string
+		token     string
+		status    int
+		challenge string
+	}{
+		{
+			name:      "missing token",
+			status:    http.StatusUnauthorized,
+			challenge: `Bearer resource_metadata="` + metadataURL + `", scope="mcp:write"`,
+		},
+		{
+			name:      "invalid token",
+			token:     "forged-token",
+			status:    http.StatusUnauthorized,
+			challenge: `Bearer resource_metadata="` + metadataURL + `", error="invalid_token", error_description="invalid token", scope="mcp:write"`,
+		},
+		{
+			name:      "expired token",
+			token:     "expired-token",
+			status:    http.StatusUnauthorized,
+			challenge: `Bearer resource_metadata="` + metadataURL + `", error="invalid_token", error_description="token expired", scope="mcp:write"`,
+		},
+		{
+			name:      "insufficient scope",
+			token:     "reader-token",
+			status:    http.StatusForbidden,
+			challenge: `Bearer resource_metadata="` + metadataURL + `", error="insufficient_scope", error_description="missing scope \"mcp:write\"", scope="mcp:write"`,
+		},
+		{
+			name:   "valid token",
+			token:  "alice-token",
+			status: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := postWithToken(t, endpoint, tt.token, initialize, nil)
+			defer resp.Body.Close()
+			assert.Equal(t, tt.status, resp.StatusCode)
+			assert.Equal(t, tt.challenge, resp.Header.Get("WWW-Authenticate"))
+		})
+	}
+
+	t.Run("handlers see the identity", func(t *testing.T) {
+		resp := postWithToken(t, endpoint, "alice-token", initialize, nil)
+		resp.Body.Close()
+		sessionID := resp.Header.Get(headerKeySessionID)
+		require.NotEmpty(t, sessionID)
+
+		resp = postWithToken(t, endpoint, "alice-token",
+			`{"jsonrpc": "2.0", "id": 2, "method": "tools/call", "params": {"name": "whoami"}}`,
+			map[string]string{headerKeySessionID: sessionID})
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var response struct {
+			Result mcp.CallToolResult `json:"result"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+		require.Len(t, response.Result.Content, 1)
+		assert.Equal(t, "alice", response.Result.Content[0].(mcp.TextContent).Text)
+	})
+
+	t.Run("sessions are bound to their identity", func(t *testing.T) {
+		resp := postWithToken(t, endpoint, "alice-token", initialize, nil)
+		resp.Body.Close()
+		sessionID := resp.Header.Get(headerKeySessionID)
+		require.NotEmpty(t, sessionID)
+
+		send := func(method, token string) *http.Response {
+			req, err := http.NewRequest(method, endpoint, nil)
+			require.NoError(t, err)
+			req.Header.Set("Authorization", "Bearer "+token)
+			req.Header.Set(headerKeySessionID, sessionID)
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+			return resp
+		}
+		ping := `{"jsonrpc": "2.0", "id": 2, "method": "ping"}`
+		headers := map[string]string{headerKeySessionID: sessionID}
+
+		resp = postWithToken(t, endpoint, "bob-token", ping, headers)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Equal(t, http.StatusForbidden, send(http.MethodGet, "bob-token").StatusCode)
+		assert.Equal(t, http.StatusForbidden, send(http.MethodDelete, "bob-token").StatusCode)
+
+		resp = postWithToken(t, endpoint, "alice-token", ping, headers)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, http.StatusOK, send(http.MethodDelete, "alice-token").StatusCode)
+
+		// Once ended, the session has no owner anymore
+		resp = postWithToken(t, endpoint, "alice-token", ping, headers)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("sessions without an owner are rejected", func(t *testing.T) {
+		resp := postWithToken(t, endpoint, "alice-token",
+			`{"jsonrpc": "2.0", "id": 2, "method": "ping"}`,
+			map[string]string{headerKeySessionID: idPrefix + "00000000-0000-0000-0000-000000000000"})
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestStreamableHTTPServer_AuthRelativeResource(t *testing.T) {
+	config := testAuthConfig()
+	config.Metadata.Resource = "/mcp"
+	server := NewTestStreamableHTTPServer(newAuthTestServer(), WithStreamableHTTPAuth(config))
+	defer server.Close()
+
+	resp := postWithToken(t, server.URL+"/mcp", "", `{"jsonrpc": "2.0", "id": 1, "method": "ping"}`, nil)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, `Bearer resource_metadata="`+server.URL+`/.well-known/oauth-protected-resource/mcp", scope="mcp:write"`,
+		resp.Header.Get("WWW-Authenticate"))
+}
+
+func TestSSEServer_Auth(t *testing.T) {
+	server := NewTestServer(newAuthTestServer(), WithSSEAuth(testAuthConfig()))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/.well-known/oauth-protected-resource/mcp")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = http.Get(server.URL + "/sse")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("WWW-Authenticate"), `resource_metadata="https://mcp.example.com/.well-known/oauth-protected-resource/mcp"`)
+
+	// Open a session as alice
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/sse", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer alice-token")
+	stream, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer stream.Body.Close()
+	require.Equal(t, http.StatusOK, stream.StatusCode)
+
+	reader := bufio.NewReader(stream.Body)
+	var messageURL string
+	for messageURL == "" {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		if data, ok := strings.CutPrefix(line, "data: "); ok {
+			messageURL = strings.TrimSpace(data)
+		}
+	}
+
+	ping := `{"jsonrpc": "2.0", "id": 1, "method": "ping"}`
+
+	resp = postWithToken(t, messageURL, "", ping, nil)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	// The session can't be used with the token of another identity
+	resp = postWithToken(t, messageURL, "bob-token", ping, nil)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode, string(body))
+
+	resp = postWithToken(t, messageURL, "alice-token", ping, nil)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+}
+
+func TestNewAuthorizer_MetadataURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   AuthConfig
+		wantURL  string
+		wantPath string
+	}{
+		{
+			name:     "resource without path",
+			config:   AuthConfig{Metadata: ProtectedResourceMetadata{Resource: "https://example.com"}},
+			wantURL:  "https://example.com/.well-known/oauth-protected-resource",
+			wantPath: "/.well-known/oauth-protected-resource",
+		},
+		{
+			name:     "resource with path",
+			config:   AuthConfig{Metadata: ProtectedResourceMetadata{Resource: "https://example.com/api/mcp/"}},
+			wantURL:  "https://example.com/.well-known/oauth-protected-resource/api/mcp",
+			wantPath: "/.well-known/oauth-protected-resource/api/mcp",
+		},
+		{
+			name: "explicit metadata URL",
+			config: AuthConfig{
+				Metadata:            ProtectedResourceMetadata{Resource: "https://example.com/mcp"},
+				ResourceMetadataURL: "https://example.com/meta.json",
+			},
+			wantURL:  "https://example.com/meta.json",
+			wantPath: "/meta.json",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newAuthorizer(tt.config)
+			assert.Equal(t, tt.wantURL, a.metadataURL)
+			assert.Equal(t, tt.wantPath, a.metadataPath)
+		})
+	}
+}
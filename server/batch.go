@@ -0,0 +1,159 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// DefaultMaxBatchSize is the maximum number of messages in a JSON-RPC
+	// batch by default. See WithMaxBatchSize.
+	DefaultMaxBatchSize = 100
+	// DefaultBatchConcurrency is the number of messages of a JSON-RPC batch
+	// processed at the same time by default. See WithBatchConcurrency.
+	DefaultBatchConcurrency = 10
+)
+
+// WithMaxBatchSize sets the maximum number of messages in a JSON-RPC batch.
+// Larger batches are rejected as a whole with an INVALID_REQUEST error. A
+// non-positive size means DefaultMaxBatchSize.
+func WithMaxBatchSize(size int) ServerOption {
+	return func(s *MCPServer) {
+		s.maxBatchSize = size
+	}
+}
+
+// WithBatchConcurrency sets how many messages of a JSON-RPC batch are
+// processed at the same time. A non-positive value means
+// DefaultBatchConcurrency.
+func WithBatchConcurrency(concurrency int) ServerOption {
+	return func(s *MCPServer) {
+		s.batchConcurrency = concurrency
+	}
+}
+
+// sessionWithClientResponses is implemented by sessions that send requests to
+// the client and can route the client's responses back to the caller.
+type sessionWithClientResponses interface {
+	deliverResponse(message json.RawMessage) bool
+}
+
+// isJSONRPCBatch reports whether the message is a JSON-RPC batch, i.e. an
+// array of messages rather than a single object.
+func isJSONRPCBatch(message []byte) bool {
+	trimmed := bytes.TrimLeft(message, " \t\r\n")
+	return len(trimmed) > 0 && trimmed[0] == '['
+}
+
+// batchingErrorMessage describes why a JSON-RPC batch was rejected.
+func batchingErrorMessage(protocolVersion string) string {
+	if protocolVersion != "" && !mcp.ProtocolVersionAllowsBatching(protocolVersion) {
+		return fmt.Sprintf("JSON-RPC batching is not supported in protocol version %s", protocolVersion)
+	}
+	return "JSON-RPC batching is not supported"
+}
+
+// handleBatch processes the members of a JSON-RPC batch concurrently, up to
+// the batch concurrency, each through HandleMessage so that hooks run per
+// message. It returns the
+// responses to the requests of the batch as a []mcp.JSONRPCMessage, or nil if
+// the batch only held notifications and responses.
+func (s *MCPServer) handleBatch(ctx context.Context, message json.RawMessage) mcp.JSONRPCMessage {
+	if version := sessionProtocolVersion(ctx); !mcp.ProtocolVersionAllowsBatching(version) {
+		return createErrorResponse(nil, mcp.INVALID_REQUEST, batchingErrorMessage(version))
+	}
+
+	var members []json.RawMessage
+	if err := json.Unmarshal(message, &members); err != nil {
+		return createErrorResponse(nil, mcp.PARSE_ERROR, "Failed to parse message")
+	}
+	if len(members) == 0 {
+		return createErrorResponse(nil, mcp.INVALID_REQUEST, "Empty batch")
+	}
+	maxSize := s.maxBatchSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxBatchSize
+	}
+	if len(members) > maxSize {
+		return createErrorResponse(
+			nil,
+			mcp.INVALID_REQUEST,
+			fmt.Sprintf("Batch of %d messages exceeds the limit of %d", len(members), maxSize),
+		)
+	}
+
+	session, _ := ClientSessionFromContext(ctx).(sessionWithClientResponses)
+
+	workers := s.batchConcurrency
+	if workers <= 0 {
+		workers = DefaultBatchConcurrency
+	}
+	workers = min(workers, len(members))
+
+	results := make([]mcp.JSONRPCMessage, len(members))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = s.handleBatchMember(ctx, session, members[i])
+			}
+		}()
+	}
+	for i := range members {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	// Only requests get a response, in the order they appear in the batch
+	responses := make([]mcp.JSONRPCMessage, 0, len(results))
+	for _, result := range results {
+		if result != nil {
+			responses = append(responses, result)
+		}
+	}
+	if len(responses) == 0 {
+		return nil
+	}
+	return responses
+}
+
+func (s *MCPServer) handleBatchMember(
+	ctx context.Context,
+	session sessionWithClientResponses,
+	member json.RawMessage,
+) mcp.JSONRPCMessage {
+	if isJSONRPCBatch(member) {
+		return createErrorResponse(nil, mcp.INVALID_REQUEST, "Nested batches are not allowed")
+	}
+
+	var baseMessage struct {
+		Method mcp.MCPMethod `json:"method"`
+		ID     any           `json:"id,omitempty"`
+	}
+	if err := json.Unmarshal(member, &baseMessage); err != nil {
+		return createErrorResponse(nil, mcp.INVALID_REQUEST, "Invalid batch member")
+	}
+	if baseMessage.Method == mcp.MethodInitialize {
+		return createErrorResponse(
+			baseMessage.ID,
+			mcp.INVALID_REQUEST,
+			"initialize request must not be part of a JSON-RPC batch",
+		)
+	}
+
+	// Responses to requests sent to the client are routed to the waiting caller
+	if baseMessage.Method == "" && session != nil && session.deliverResponse(member) {
+		return nil
+	}
+
+	return s.HandleMessage(ctx, member)
+}
@@ -0,0 +1,188 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCPServer_HandleBatch(t *testing.T) {
+	var beforeAny atomic.Int32
+	hooks := &Hooks{}
+	hooks.AddBeforeAny(func(ctx context.Context, id any, method mcp.MCPMethod, message any) {
+		beforeAny.Add(1)
+	})
+
+	server := NewMCPServer("test-server", "1.0.0", WithHooks(hooks))
+
+	// Both tools wait for each other, so the batch only completes if its
+	// members run concurrently
+	started := make(chan struct{}, 2)
+	waitForOther := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		started <- struct{}{}
+		for len(started) < 2 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(time.Millisecond):
+			}
+		}
+		return mcp.NewToolResultText(request.Params.Name), nil
+	}
+	server.AddTool(mcp.NewTool("first"), waitForOther)
+	server.AddTool(mcp.NewTool("second"), waitForOther)
+
+	session := NewInProcessSession("batch-session", nil)
+	session.SetProtocolVersion("2025-03-26")
+	ctx, cancel := context.WithTimeout(server.WithContext(context.Background(), session), 5*time.Second)
+	defer cancel()
+
+	response := server.HandleMessage(ctx, []byte(`[
+		{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": {"name": "first"}},
+		{"jsonrpc": "2.0", "method": "notifications/initialized"},
+		{"jsonrpc": "2.0", "id": 2, "method": "tools/call", "params": {"name": "second"}},
+		{"jsonrpc": "2.0", "id": 3, "method": "ping"}
+	]`))
+
+	responses, ok := response.([]mcp.JSONRPCMessage)
+	require.True(t, ok, "expected a batch response, got %T", response)
+	require.Len(t, responses, 3)
+
+	// Responses keep the order of the requests in the batch
+	for i, want := range []string{"1", "2", "3"} {
+		resp, ok := responses[i].(mcp.JSONRPCResponse)
+		require.True(t, ok)
+		id, err := json.Marshal(resp.ID)
+		require.NoError(t, err)
+		assert.Equal(t, want, string(id))
+	}
+
+	// Hooks run for every request of the batch
+	assert.Equal(t, int32(3), beforeAny.Load())
+
+	data, err := json.Marshal(response)
+	require.NoError(t, err)
+	var decoded []map[string]any
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Len(t, decoded, 3)
+}
+
+func TestMCPServer_HandleBatchErrors(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0")
+	session := NewInProcessSession("batch-errors-session", nil)
+	session.SetProtocolVersion("2025-03-26")
+	ctx := server.WithContext(context.Background(), session)
+
+	tests := []struct {
+		name    string
+		message string
+		check   func(t *testing.T, response mcp.JSONRPCMessage)
+	}{
+		{
+			name:    "empty batch",
+			message: `[]`,
+			check: func(t *testing.T, response mcp.JSONRPCMessage) {
+				errorResponse, ok := response.(mcp.JSONRPCError)
+				require.True(t, ok)
+				assert.Equal(t, mcp.INVALID_REQUEST, errorResponse.Error.Code)
+			},
+		},
+		{
+			name:    "only notifications",
+			message: `[{"jsonrpc": "2.0", "method": "notifications/initialized"}]`,
+			check: func(t *testing.T, response mcp.JSONRPCMessage) {
+				assert.Nil(t, response)
+			},
+		},
+		{
+			name:    "invalid members",
+			message: `[1, [{"jsonrpc": "2.0", "id": 1, "method": "ping"}], {"jsonrpc": "2.0", "id": 2, "method": "initialize", "params": {}}]`,
+			check: func(t *testing.T, response mcp.JSONRPCMessage) {
+				responses, ok := response.([]mcp.JSONRPCMessage)
+				require.True(t, ok)
+				require.Len(t, responses, 3)
+				for _, resp := range responses {
+					errorResponse, ok := resp.(mcp.JSONRPCError)
+					require.True(t, ok)
+					assert.Equal(t, mcp.INVALID_REQUEST, errorResponse.Error.Code)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.check(t, server.HandleMessage(ctx, []byte(tt.message)))
+		})
+	}
+}
+
+func TestMCPServer_HandleBatchLimits(t *testing.T) {
+	pings := func(n int) []byte {
+		members := make([]string, n)
+		for i := range members {
+			members[i] = fmt.Sprintf(`{"jsonrpc": "2.0", "id": %d, "method": "ping"}`, i+1)
+		}
+		return []byte("[" + strings.Join(members, ",") + "]")
+	}
+	newContext := func(server *MCPServer) context.Context {
+		session := NewInProcessSession("batch-limits-session", nil)
+		session.SetProtocolVersion("2025-03-26")
+		return server.WithContext(context.Background(), session)
+	}
+
+	t.Run("batches over the size limit are rejected", func(t *testing.T) {
+		server := NewMCPServer("test-server", "1.0.0", WithMaxBatchSize(2))
+		ctx := newContext(server)
+
+		responses, ok := server.HandleMessage(ctx, pings(2)).([]mcp.JSONRPCMessage)
+		require.True(t, ok)
+		assert.Len(t, responses, 2)
+
+		errorResponse, ok := server.HandleMessage(ctx, pings(3)).(mcp.JSONRPCError)
+		require.True(t, ok)
+		assert.Equal(t, mcp.INVALID_REQUEST, errorResponse.Error.Code)
+	})
+
+	t.Run("default size limit", func(t *testing.T) {
+		server := NewMCPServer("test-server", "1.0.0")
+		errorResponse, ok := server.HandleMessage(newContext(server), pings(DefaultMaxBatchSize+1)).(mcp.JSONRPCError)
+		require.True(t, ok)
+		assert.Equal(t, mcp.INVALID_REQUEST, errorResponse.Error.Code)
+	})
+
+	t.Run("members run up to the batch concurrency", func(t *testing.T) {
+		server := NewMCPServer("test-server", "1.0.0", WithBatchConcurrency(2))
+		var running, maxRunning atomic.Int32
+		server.AddTool(mcp.NewTool("slow"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				current := maxRunning.Load()
+				if n <= current || maxRunning.CompareAndSwap(current, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			return mcp.NewToolResultText("done"), nil
+		})
+
+		members := make([]string, 6)
+		for i := range members {
+			members[i] = fmt.Sprintf(`{"jsonrpc": "2.0", "id": %d, "method": "tools/call", "params": {"name": "slow"}}`, i+1)
+		}
+		response := server.HandleMessage(newContext(server), []byte("["+strings.Join(members, ",")+"]"))
+		responses, ok := response.([]mcp.JSONRPCMessage)
+		require.True(t, ok)
+		assert.Len(t, responses, 6)
+		assert.Equal(t, int32(2), maxRunning.Load())
+	})
+}
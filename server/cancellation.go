@@ -0,0 +1,102 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// inflightRequest tracks a request that is currently being handled.
+type inflightRequest struct {
+	cancel context.CancelCauseFunc
+}
+
+// trackRequest derives a cancellable context for a request and records it
+// under the current session, so that a notifications/cancelled for the same
+// request ID can stop the handler. The returned function must be called once
+// the request has been handled; it reports whether the client cancelled the
+// request, in which case no response should be sent.
+//
+// Requests without a session ID (e.g. stateless streamable HTTP) are not
+// tracked, since their IDs are not unique across clients. The initialize
+// request cannot be cancelled.
+func (s *MCPServer) trackRequest(
+	ctx context.Context,
+	id any,
+	method mcp.MCPMethod,
+) (context.Context, func() bool) {
+	session := ClientSessionFromContext(ctx)
+	if session == nil || session.SessionID() == "" || method == mcp.MethodInitialize {
+		return ctx, func() bool { return false }
+	}
+
+	sessionID := session.SessionID()
+	requestID := mcp.NewRequestId(id).String()
+	ctx, cancel := context.WithCancelCause(ctx)
+	request := &inflightRequest{cancel: cancel}
+
+	s.inflightMu.Lock()
+	requests, ok := s.inflightRequests[sessionID]
+	if !ok {
+		requests = make(map[string]*inflightRequest)
+		s.inflightRequests[sessionID] = requests
+	}
+	requests[requestID] = request
+	s.inflightMu.Unlock()
+
+	return ctx, func() bool {
+		s.inflightMu.Lock()
+		if requests, ok := s.inflightRequests[sessionID]; ok && requests[requestID] == request {
+			delete(requests, requestID)
+			if len(requests) == 0 {
+				delete(s.inflightRequests, sessionID)
+			}
+		}
+		s.inflightMu.Unlock()
+
+		cancelled := errors.Is(context.Cause(ctx), ErrRequestCancelled)
+		cancel(nil)
+		return cancelled
+	}
+}
+
+// cancelRequest cancels the context of an in-flight request of the given
+// session. It returns false if no such request is in flight.
+func (s *MCPServer) cancelRequest(sessionID string, id mcp.RequestId, reason string) bool {
+	s.inflightMu.Lock()
+	request, ok := s.inflightRequests[sessionID][id.String()]
+	s.inflightMu.Unlock()
+	if !ok {
+		return false
+	}
+
+	cause := ErrRequestCancelled
+	if reason != "" {
+		cause = fmt.Errorf("%w: %s", ErrRequestCancelled, reason)
+	}
+	request.cancel(cause)
+	return true
+}
+
+// handleCancelledNotification cancels the in-flight request referenced by a
+// notifications/cancelled message. Notifications for unknown or already
+// completed requests are ignored, as the spec allows.
+func (s *MCPServer) handleCancelledNotification(
+	ctx context.Context,
+	notification mcp.JSONRPCNotification,
+) {
+	session := ClientSessionFromContext(ctx)
+	if session == nil {
+		return
+	}
+
+	requestID, ok := notification.Params.AdditionalFields["requestId"]
+	if !ok || requestID == nil {
+		return
+	}
+	reason, _ := notification.Params.AdditionalFields["reason"].(string)
+
+	s.cancelRequest(session.SessionID(), mcp.NewRequestId(requestID), reason)
+}
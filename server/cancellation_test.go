@@ -0,0 +1,110 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCPServer_CancelledNotification(t *testing.T) {
+	newServer := func(started chan<- struct{}, cause chan<- error) *MCPServer {
+		server := NewMCPServer("test-server", "1.0.0")
+		server.AddTool(mcp.NewTool("slow"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			close(started)
+			select {
+			case <-ctx.Done():
+				cause <- context.Cause(ctx)
+				return nil, ctx.Err()
+			case <-time.After(5 * time.Second):
+				return mcp.NewToolResultText("done"), nil
+			}
+		})
+		return server
+	}
+
+	callTool := []byte(`{"jsonrpc": "2.0", "id": 7, "method": "tools/call", "params": {"name": "slow"}}`)
+
+	t.Run("cancels in-flight request and suppresses response", func(t *testing.T) {
+		started := make(chan struct{})
+		cause := make(chan error, 1)
+		server := newServer(started, cause)
+		ctx := server.WithContext(context.Background(), fakeSession{
+			sessionID:           "session-1",
+			notificationChannel: make(chan mcp.JSONRPCNotification, 10),
+			initialized:         true,
+		})
+
+		responses := make(chan mcp.JSONRPCMessage, 1)
+		go func() {
+			responses <- server.HandleMessage(ctx, callTool)
+		}()
+		<-started
+
+		response := server.HandleMessage(ctx, []byte(`{
+			"jsonrpc": "2.0",
+			"method": "notifications/cancelled",
+			"params": {"requestId": 7, "reason": "user aborted"}
+		}`))
+		assert.Nil(t, response)
+
+		select {
+		case err := <-cause:
+			assert.True(t, errors.Is(err, ErrRequestCancelled))
+			assert.Contains(t, err.Error(), "user aborted")
+		case <-time.After(time.Second):
+			t.Fatal("handler context was not cancelled")
+		}
+		select {
+		case response := <-responses:
+			assert.Nil(t, response)
+		case <-time.After(time.Second):
+			t.Fatal("request did not finish")
+		}
+
+		server.inflightMu.Lock()
+		assert.Empty(t, server.inflightRequests)
+		server.inflightMu.Unlock()
+	})
+
+	t.Run("ignores cancellation from another session", func(t *testing.T) {
+		started := make(chan struct{})
+		cause := make(chan error, 1)
+		server := newServer(started, cause)
+		ctx := server.WithContext(context.Background(), fakeSession{
+			sessionID:           "session-1",
+			notificationChannel: make(chan mcp.JSONRPCNotification, 10),
+			initialized:         true,
+		})
+		otherCtx := server.WithContext(context.Background(), fakeSession{
+			sessionID:           "session-2",
+			notificationChannel: make(chan mcp.JSONRPCNotification, 10),
+			initialized:         true,
+		})
+
+		go server.HandleMessage(ctx, callTool)
+		<-started
+
+		server.HandleMessage(otherCtx, []byte(`{
+			"jsonrpc": "2.0",
+			"method": "notifications/cancelled",
+			"params": {"requestId": 7}
+		}`))
+
+		server.inflightMu.Lock()
+		request, ok := server.inflightRequests["session-1"][mcp.NewRequestId(int64(7)).String()]
+		server.inflightMu.Unlock()
+		require.True(t, ok)
+
+		select {
+		case <-cause:
+			t.Fatal("request of another session was cancelled")
+		case <-time.After(50 * time.Millisecond):
+		}
+		request.cancel(context.Canceled)
+	})
+}
@@ -0,0 +1,157 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/tracing"
+)
+
+// clientResponse is the client's answer to a request sent by the server.
+type clientResponse struct {
+	result json.RawMessage
+	err    error
+}
+
+// clientRequests tracks the requests the server has sent to a client (e.g.
+// sampling or roots requests) that are still waiting for a response.
+// Responses are routed back to the waiting caller by request ID.
+type clientRequests struct {
+	mu      sync.Mutex
+	pending map[int64]chan *clientResponse
+	closed  error // set once the session has ended
+}
+
+// send writes a JSON-RPC request with the given ID using write and waits for
+// the client's response or for ctx to be done.
+func (c *clientRequests) send(
+	ctx context.Context,
+	id int64,
+	method mcp.MCPMethod,
+	params any,
+	write func(request mcp.JSONRPCRequest) error,
+) (json.RawMessage, error) {
+	responseChan := make(chan *clientResponse, 1)
+	c.mu.Lock()
+	if c.closed != nil {
+		c.mu.Unlock()
+		return nil, fmt.Errorf("failed to send %s request: %w", method, c.closed)
+	}
+	if c.pending == nil {
+		c.pending = make(map[int64]chan *clientResponse)
+	}
+	c.pending[id] = responseChan
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	request := mcp.JSONRPCRequest{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      mcp.NewRequestId(id),
+		Params:  params,
+		Request: mcp.Request{
+			Method: string(method),
+		},
+	}
+	if err := write(request); err != nil {
+		return nil, fmt.Errorf("failed to send %s request: %w", method, err)
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case response := <-responseChan:
+		if response.err != nil {
+			return nil, fmt.Errorf("%s request failed: %w", method, response.err)
+		}
+		return response.result, nil
+	}
+}
+
+// deliver routes a JSON-RPC response from the client to the pending request
+// with the same ID. It reports whether the message was such a response.
+func (c *clientRequests) deliver(message json.RawMessage) bool {
+	var response struct {
+		ID     json.Number     `json:"id"`
+		Result json.RawMessage `json:"result,omitempty"`
+		Error  *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error,omitempty"`
+	}
+	if err := json.Unmarshal(message, &response); err != nil {
+		return false
+	}
+	id, err := response.ID.Int64()
+	if err != nil || (response.Result == nil && response.Error == nil) {
+		return false
+	}
+
+	c.mu.Lock()
+	responseChan, ok := c.pending[id]
+	c.mu.Unlock()
+	if !ok {
+		return false
+	}
+
+	result := &clientResponse{result: response.Result}
+	if response.Error != nil {
+		result.err = errors.New(response.Error.Message)
+	}
+
+	// Send the response (non-blocking)
+	select {
+	case responseChan <- result:
+	default:
+		// A response for this request was already delivered, ignore
+	}
+	return true
+}
+
+// close fails the pending requests, and the ones sent later, with err.
+func (c *clientRequests) close(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = err
+	for id, responseChan := range c.pending {
+		select {
+		case responseChan <- &clientResponse{err: err}:
+		default:
+		}
+		delete(c.pending, id)
+	}
+}
+
+// requestClient sends a request to the client using send and decodes the
+// client's result. The current span context is propagated in the _meta field
+// of the request.
+func requestClient[T any](
+	ctx context.Context,
+	send func(ctx context.Context, method mcp.MCPMethod, params any) (json.RawMessage, error),
+	method mcp.MCPMethod,
+	params any,
+) (*T, error) {
+	params, err := tracing.InjectMeta(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s request: %w", method, err)
+	}
+
+	response, err := send(ctx, method, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var result T
+	if err := json.Unmarshal(response, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s response: %w", method, err)
+	}
+	return &result, nil
+}
@@ -0,0 +1,162 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// maxCompletionValues is the maximum number of values a completion result may carry.
+const maxCompletionValues = 100
+
+// CompletionHandlerFunc returns completion values for a prompt argument or a
+// resource template variable. The argument being completed and its current
+// value are available in request.Params.Argument.
+type CompletionHandlerFunc func(ctx context.Context, request mcp.CompleteRequest) ([]string, error)
+
+// completionReference is the decoded form of CompleteParams.Ref, which can
+// hold either a PromptReference or a ResourceReference.
+type completionReference struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+	URI  string `json:"uri"`
+}
+
+// hasEnumArguments reports whether any argument of the prompt is enum-constrained.
+func hasEnumArguments(prompt mcp.Prompt) bool {
+	for _, arg := range prompt.Arguments {
+		if len(arg.Enum) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// enumCompletion returns a CompletionHandlerFunc that offers the enum values
+// starting with the current argument value.
+func enumCompletion(values []string) CompletionHandlerFunc {
+	return func(ctx context.Context, request mcp.CompleteRequest) ([]string, error) {
+		prefix := strings.ToLower(request.Params.Argument.Value)
+		matches := make([]string, 0, len(values))
+		for _, value := range values {
+			if strings.HasPrefix(strings.ToLower(value), prefix) {
+				matches = append(matches, value)
+			}
+		}
+		return matches, nil
+	}
+}
+
+func (s *MCPServer) handleComplete(
+	ctx context.Context,
+	id any,
+	request mcp.CompleteRequest,
+) (*mcp.CompleteResult, *requestError) {
+	var ref completionReference
+	refBytes, err := json.Marshal(request.Params.Ref)
+	if err == nil {
+		err = json.Unmarshal(refBytes, &ref)
+	}
+	if err != nil {
+		return nil, &requestError{
+			id:   id,
+			code: mcp.INVALID_PARAMS,
+			err:  fmt.Errorf("invalid completion reference: %w", err),
+		}
+	}
+
+	var handler CompletionHandlerFunc
+	switch ref.Type {
+	case "ref/prompt":
+		// Session-specific prompts take precedence over global ones
+		sessionPrompt, ok := sessionPromptsFromContext(ctx)[ref.Name]
+		prompt := sessionPrompt.Prompt
+		handler = sessionPrompt.Completions[request.Params.Argument.Name]
+		if !ok {
+			s.promptsMu.RLock()
+			prompt, ok = s.prompts[ref.Name]
+			handler = s.promptCompletions[ref.Name][request.Params.Argument.Name]
+			s.promptsMu.RUnlock()
+		}
+		// Prompts hidden by enforced filters are reported as unknown
+		if !ok || !s.promptVisible(ctx, prompt) {
+			return nil, &requestError{
+				id:   id,
+				code: mcp.INVALID_PARAMS,
+				err:  fmt.Errorf("prompt '%s' not found: %w", ref.Name, ErrPromptNotFound),
+			}
+		}
+		if handler == nil {
+			for _, arg := range prompt.Arguments {
+				if arg.Name == request.Params.Argument.Name && len(arg.Enum) > 0 {
+					handler = enumCompletion(arg.Enum)
+					break
+				}
+			}
+		}
+	case "ref/resource":
+		// Resources and templates hidden by enforced filters are reported as
+		// unknown
+		sessionResources, sessionTemplates := sessionResourcesFromContext(ctx)
+		var visible bool
+		if entry, ok := sessionTemplates[ref.URI]; ok {
+			handler = entry.Completions[request.Params.Argument.Name]
+			visible = s.resourceTemplateVisible(ctx, entry.Template)
+		} else if entry, ok := sessionResources[ref.URI]; ok {
+			visible = s.resourceVisible(ctx, entry.Resource)
+		} else {
+			s.resourcesMu.RLock()
+			templateEntry, isTemplate := s.resourceTemplates[ref.URI]
+			resourceEntry, isResource := s.resources[ref.URI]
+			s.resourcesMu.RUnlock()
+			switch {
+			case isTemplate:
+				handler = templateEntry.completions[request.Params.Argument.Name]
+				visible = s.resourceTemplateVisible(ctx, templateEntry.template)
+			case isResource:
+				visible = s.resourceVisible(ctx, resourceEntry.resource)
+			}
+		}
+		if !visible {
+			return nil, &requestError{
+				id:   id,
+				code: mcp.RESOURCE_NOT_FOUND,
+				err:  fmt.Errorf("resource '%s' not found: %w", ref.URI, ErrResourceNotFound),
+			}
+		}
+	default:
+		return nil, &requestError{
+			id:   id,
+			code: mcp.INVALID_PARAMS,
+			err:  fmt.Errorf("unknown completion reference type '%s'", ref.Type),
+		}
+	}
+
+	result := &mcp.CompleteResult{}
+	result.Completion.Values = []string{}
+	if handler == nil {
+		return result, nil
+	}
+
+	values, err := handler(ctx, request)
+	if err != nil {
+		return nil, &requestError{
+			id:   id,
+			code: mcp.INTERNAL_ERROR,
+			err:  err,
+		}
+	}
+
+	if len(values) > maxCompletionValues {
+		result.Completion.Total = len(values)
+		result.Completion.HasMore = true
+		values = values[:maxCompletionValues]
+	}
+	if values != nil {
+		result.Completion.Values = values
+	}
+	return result, nil
+}
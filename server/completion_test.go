@@ -0,0 +1,196 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCPServer_Completion(t *testing.T) {
+	completeMessage := func(ref string, argName, argValue string) []byte {
+		return []byte(fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"method": "completion/complete",
+			"params": {
+				"ref": %s,
+				"argument": {"name": %q, "value": %q}
+			}
+		}`, ref, argName, argValue))
+	}
+
+	tests := []struct {
+		name     string
+		setup    func(s *MCPServer)
+		message  []byte
+		validate func(t *testing.T, response mcp.JSONRPCMessage)
+	}{
+		{
+			name: "prompt argument provider",
+			setup: func(s *MCPServer) {
+				s.AddPrompts(ServerPrompt{
+					Prompt:  mcp.NewPrompt("greet", mcp.WithArgument("language")),
+					Handler: func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) { return nil, nil },
+					Completions: map[string]CompletionHandlerFunc{
+						"language": func(ctx context.Context, request mcp.CompleteRequest) ([]string, error) {
+							return []string{request.Params.Argument.Value + "thon"}, nil
+						},
+					},
+				})
+			},
+			message: completeMessage(`{"type": "ref/prompt", "name": "greet"}`, "language", "py"),
+			validate: func(t *testing.T, response mcp.JSONRPCMessage) {
+				result := response.(mcp.JSONRPCResponse).Result.(mcp.CompleteResult)
+				assert.Equal(t, []string{"python"}, result.Completion.Values)
+			},
+		},
+		{
+			name: "enum argument completes for free",
+			setup: func(s *MCPServer) {
+				s.AddPrompt(
+					mcp.NewPrompt("greet", mcp.WithArgument("tone", mcp.ArgumentEnum("formal", "friendly", "funny"))),
+					func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) { return nil, nil },
+				)
+			},
+			message: completeMessage(`{"type": "ref/prompt", "name": "greet"}`, "tone", "F"),
+			validate: func(t *testing.T, response mcp.JSONRPCMessage) {
+				result := response.(mcp.JSONRPCResponse).Result.(mcp.CompleteResult)
+				assert.Equal(t, []string{"formal", "friendly", "funny"}, result.Completion.Values)
+			},
+		},
+		{
+			name: "resource template variable provider",
+			setup: func(s *MCPServer) {
+				s.AddResourceTemplates(ServerResourceTemplate{
+					Template: mcp.NewResourceTemplate("test://users/{id}", "User"),
+					Handler: func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+						return nil, nil
+					},
+					Completions: map[string]CompletionHandlerFunc{
+						"id": func(ctx context.Context, request mcp.CompleteRequest) ([]string, error) {
+							values := make([]string, 150)
+							for i := range values {
+								values[i] = fmt.Sprintf("%d", i)
+							}
+							return values, nil
+						},
+					},
+				})
+			},
+			message: completeMessage(`{"type": "ref/resource", "uri": "test://users/{id}"}`, "id", ""),
+			validate: func(t *testing.T, response mcp.JSONRPCMessage) {
+				result := response.(mcp.JSONRPCResponse).Result.(mcp.CompleteResult)
+				assert.Len(t, result.Completion.Values, 100)
+				assert.Equal(t, 150, result.Completion.Total)
+				assert.True(t, result.Completion.HasMore)
+			},
+		},
+		{
+			name: "argument without provider returns no values",
+			setup: func(s *MCPServer) {
+				s.AddPrompts(ServerPrompt{
+					Prompt:  mcp.NewPrompt("greet", mcp.WithArgument("name"), mcp.WithArgument("language")),
+					Handler: func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) { return nil, nil },
+					Completions: map[string]CompletionHandlerFunc{
+						"language": func(ctx context.Context, request mcp.CompleteRequest) ([]string, error) {
+							return []string{"go"}, nil
+						},
+					},
+				})
+			},
+			message: completeMessage(`{"type": "ref/prompt", "name": "greet"}`, "name", "a"),
+			validate: func(t *testing.T, response mcp.JSONRPCMessage) {
+				result := response.(mcp.JSONRPCResponse).Result.(mcp.CompleteResult)
+				assert.Empty(t, result.Completion.Values)
+			},
+		},
+		{
+			name: "unknown prompt",
+			setup: func(s *MCPServer) {
+				s.AddPrompt(
+					mcp.NewPrompt("greet", mcp.WithArgument("tone", mcp.ArgumentEnum("formal"))),
+					func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) { return nil, nil },
+				)
+			},
+			message: completeMessage(`{"type": "ref/prompt", "name": "missing"}`, "tone", ""),
+			validate: func(t *testing.T, response mcp.JSONRPCMessage) {
+				errResp, ok := response.(mcp.JSONRPCError)
+				require.True(t, ok)
+				assert.Equal(t, mcp.INVALID_PARAMS, errResp.Error.Code)
+			},
+		},
+		{
+			name: "provider error",
+			setup: func(s *MCPServer) {
+				s.AddPrompts(ServerPrompt{
+					Prompt:  mcp.NewPrompt("greet", mcp.WithArgument("language")),
+					Handler: func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) { return nil, nil },
+					Completions: map[string]CompletionHandlerFunc{
+						"language": func(ctx context.Context, request mcp.CompleteRequest) ([]string, error) {
+							return nil, errors.New("backend unavailable")
+						},
+					},
+				})
+			},
+			message: completeMessage(`{"type": "ref/prompt", "name": "greet"}`, "language", ""),
+			validate: func(t *testing.T, response mcp.JSONRPCMessage) {
+				errResp, ok := response.(mcp.JSONRPCError)
+				require.True(t, ok)
+				assert.Equal(t, mcp.INTERNAL_ERROR, errResp.Error.Code)
+				assert.Equal(t, "backend unavailable", errResp.Error.Message)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewMCPServer("test-server", "1.0.0")
+			tt.setup(server)
+			tt.validate(t, server.HandleMessage(context.Background(), tt.message))
+		})
+	}
+}
+
+func TestMCPServer_CompletionCapability(t *testing.T) {
+	initialize := func(s *MCPServer) mcp.InitializeResult {
+		response := s.HandleMessage(context.Background(), []byte(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"method": "initialize"
+		}`))
+		return response.(mcp.JSONRPCResponse).Result.(mcp.InitializeResult)
+	}
+
+	t.Run("not declared without providers", func(t *testing.T) {
+		server := NewMCPServer("test-server", "1.0.0")
+		server.AddPrompt(
+			mcp.NewPrompt("greet", mcp.WithArgument("name")),
+			func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) { return nil, nil },
+		)
+		assert.Nil(t, initialize(server).Capabilities.Completions)
+
+		response := server.HandleMessage(context.Background(), []byte(`{
+			"jsonrpc": "2.0",
+			"id": 2,
+			"method": "completion/complete",
+			"params": {"ref": {"type": "ref/prompt", "name": "greet"}, "argument": {"name": "name", "value": ""}}
+		}`))
+		errResp, ok := response.(mcp.JSONRPCError)
+		require.True(t, ok)
+		assert.Equal(t, mcp.METHOD_NOT_FOUND, errResp.Error.Code)
+	})
+
+	t.Run("declared when an enum argument is registered", func(t *testing.T) {
+		server := NewMCPServer("test-server", "1.0.0")
+		server.AddPrompt(
+			mcp.NewPrompt("greet", mcp.WithArgument("tone", mcp.ArgumentEnum("formal"))),
+			func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) { return nil, nil },
+		)
+		assert.NotNil(t, initialize(server).Capabilities.Completions)
+	})
+}
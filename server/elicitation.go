@@ -0,0 +1,46 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// SessionWithElicitation extends ClientSession to support elicitation requests.
+type SessionWithElicitation interface {
+	ClientSession
+	RequestElicitation(ctx context.Context, request mcp.ElicitRequest) (*mcp.ElicitResult, error)
+}
+
+// RequestElicitation asks the user, via the client, for the information
+// described by the request's schema. The client must have declared the
+// elicitation capability during initialization. The result's Action tells
+// whether the user accepted, declined or cancelled the request; Content is
+// only set when the user accepted.
+func (s *MCPServer) RequestElicitation(ctx context.Context, request mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+	session := ClientSessionFromContext(ctx)
+	if session == nil {
+		return nil, fmt.Errorf("no active session")
+	}
+	elicitationSession, ok := session.(SessionWithElicitation)
+	if !ok {
+		return nil, ErrSessionDoesNotSupportElicitation
+	}
+	if err := request.Params.RequestedSchema.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid elicitation schema: %w", err)
+	}
+
+	request.Method = string(mcp.MethodElicitationCreate)
+	result, err := elicitationSession.RequestElicitation(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	switch result.Action {
+	case mcp.ElicitationActionAccept, mcp.ElicitationActionDecline, mcp.ElicitationActionCancel:
+		return result, nil
+	default:
+		return nil, fmt.Errorf("invalid elicitation action %q", result.Action)
+	}
+}
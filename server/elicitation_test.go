@@ -0,0 +1,86 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockElicitationSession implements SessionWithElicitation for testing
+type mockElicitationSession struct {
+	mockSession
+	result *mcp.ElicitResult
+}
+
+func (m *mockElicitationSession) RequestElicitation(ctx context.Context, request mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+	return m.result, nil
+}
+
+func TestMCPServer_RequestElicitation(t *testing.T) {
+	request := mcp.ElicitRequest{
+		Params: mcp.ElicitParams{
+			Message: "Which environment?",
+			RequestedSchema: mcp.ElicitationSchema{
+				Type: "object",
+				Properties: map[string]mcp.PrimitiveSchemaDefinition{
+					"environment": {Type: "string", Enum: []string{"staging", "production"}},
+				},
+				Required: []string{"environment"},
+			},
+		},
+	}
+
+	t.Run("no session", func(t *testing.T) {
+		server := NewMCPServer("test", "1.0.0")
+		_, err := server.RequestElicitation(context.Background(), request)
+		assert.EqualError(t, err, "no active session")
+	})
+
+	t.Run("session without elicitation support", func(t *testing.T) {
+		server := NewMCPServer("test", "1.0.0")
+		ctx := server.WithContext(context.Background(), &mockSession{sessionID: "test-session"})
+		_, err := server.RequestElicitation(ctx, request)
+		assert.ErrorIs(t, err, ErrSessionDoesNotSupportElicitation)
+	})
+
+	t.Run("accepted", func(t *testing.T) {
+		server := NewMCPServer("test", "1.0.0")
+		ctx := server.WithContext(context.Background(), &mockElicitationSession{
+			mockSession: mockSession{sessionID: "test-session"},
+			result: &mcp.ElicitResult{
+				Action:  mcp.ElicitationActionAccept,
+				Content: map[string]any{"environment": "staging"},
+			},
+		})
+		result, err := server.RequestElicitation(ctx, request)
+		require.NoError(t, err)
+		assert.Equal(t, mcp.ElicitationActionAccept, result.Action)
+		assert.Equal(t, "staging", result.Content["environment"])
+	})
+
+	t.Run("invalid action", func(t *testing.T) {
+		server := NewMCPServer("test", "1.0.0")
+		ctx := server.WithContext(context.Background(), &mockElicitationSession{
+			mockSession: mockSession{sessionID: "test-session"},
+			result:      &mcp.ElicitResult{Action: "maybe"},
+		})
+		_, err := server.RequestElicitation(ctx, request)
+		assert.Error(t, err)
+	})
+
+	t.Run("nested schema is rejected", func(t *testing.T) {
+		server := NewMCPServer("test", "1.0.0")
+		ctx := server.WithContext(context.Background(), &mockElicitationSession{
+			mockSession: mockSession{sessionID: "test-session"},
+		})
+		invalid := request
+		invalid.Params.RequestedSchema.Properties = map[string]mcp.PrimitiveSchemaDefinition{
+			"address": {Type: "object"},
+		}
+		_, err := server.RequestElicitation(ctx, invalid)
+		assert.ErrorContains(t, err, "invalid elicitation schema")
+	})
+}
@@ -3,6 +3,7 @@ package server
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 var (
@@ -12,18 +13,57 @@ var (
 	ErrPromptNotFound   = errors.New("prompt not found")
 	ErrToolNotFound     = errors.New("tool not found")
 
+	// ErrToolConcurrencyLimit is returned when a tool call is rejected because the tool's concurrency limit is reached
+	ErrToolConcurrencyLimit = errors.New("tool concurrency limit reached")
+
+	// ErrInvalidToolOutput is returned when a tool result does not match the tool's output schema
+	ErrInvalidToolOutput = errors.New("invalid tool output")
+
+	// ErrInvalidToolArguments is returned when the arguments of a tool call do not match the tool's input schema
+	ErrInvalidToolArguments = errors.New("invalid tool arguments")
+
+	// ErrRateLimited is returned when a request is rejected by the server's rate limiter
+	ErrRateLimited = errors.New("rate limit exceeded")
+
+	// ErrInvalidToken is returned by token verifiers for access tokens that are not valid
+	ErrInvalidToken = errors.New("invalid token")
+
+	// ErrRequestCancelled is the context cause of a request cancelled by the client
+	ErrRequestCancelled = errors.New("request cancelled by client")
+
 	// Session-related errors
-	ErrSessionNotFound              = errors.New("session not found")
-	ErrSessionExists                = errors.New("session already exists")
-	ErrSessionNotInitialized        = errors.New("session not properly initialized")
-	ErrSessionDoesNotSupportTools   = errors.New("session does not support per-session tools")
-	ErrSessionDoesNotSupportLogging = errors.New("session does not support setting logging level")
+	ErrSessionNotFound                  = errors.New("session not found")
+	ErrSessionExists                    = errors.New("session already exists")
+	ErrSessionNotInitialized            = errors.New("session not properly initialized")
+	ErrSessionTerminated                = errors.New("session terminated")
+	ErrSessionIDRequired                = errors.New("session ID required")
+	ErrSessionDoesNotSupportTools       = errors.New("session does not support per-session tools")
+	ErrSessionDoesNotSupportResources   = errors.New("session does not support per-session resources")
+	ErrSessionDoesNotSupportPrompts     = errors.New("session does not support per-session prompts")
+	ErrSessionDoesNotSupportLogging     = errors.New("session does not support setting logging level")
+	ErrSessionDoesNotSupportRoots       = errors.New("session does not support roots requests")
+	ErrSessionDoesNotSupportElicitation = errors.New("session does not support elicitation requests")
 
 	// Notification-related errors
 	ErrNotificationNotInitialized = errors.New("notification channel not initialized")
 	ErrNotificationChannelBlocked = errors.New("notification channel full or blocked")
 )
 
+// ToolArgumentsError is returned when the arguments of a tool call do not
+// match the tool's input schema. It wraps ErrInvalidToolArguments.
+type ToolArgumentsError struct {
+	Tool       string
+	Violations []SchemaViolation
+}
+
+func (e *ToolArgumentsError) Error() string {
+	return fmt.Sprintf("invalid arguments for tool '%s': %s", e.Tool, strings.Join(violationStrings(e.Violations), "; "))
+}
+
+func (e *ToolArgumentsError) Unwrap() error {
+	return ErrInvalidToolArguments
+}
+
 // ErrDynamicPathConfig is returned when attempting to use static path methods with dynamic path configuration
 type ErrDynamicPathConfig struct {
 	Method string
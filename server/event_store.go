@@ -0,0 +1,119 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// DefaultMaxStoredEvents is the number of events kept by an
+// InMemoryEventStore created with a non-positive limit.
+const DefaultMaxStoredEvents = 1000
+
+// ErrEventNotFound is returned when replaying after an event that is not (or
+// no longer) stored.
+var ErrEventNotFound = errors.New("event not found")
+
+// EventStore stores the events sent on the SSE streams of a streamable HTTP
+// server, so that a client reconnecting with the Last-Event-ID header can
+// receive the events it missed.
+type EventStore interface {
+	// StoreEvent stores a message sent on the given stream and returns the
+	// ID of the event, which must be unique across all streams. Event IDs
+	// let clients replay the events of their stream, so they must not be
+	// guessable.
+	StoreEvent(ctx context.Context, streamID string, message json.RawMessage) (eventID string, err error)
+	// ReplayEventsAfter calls send, in order, for every event stored after
+	// lastEventID on the same stream, and returns the ID of that stream.
+	ReplayEventsAfter(
+		ctx context.Context,
+		lastEventID string,
+		send func(eventID string, message json.RawMessage) error,
+	) (streamID string, err error)
+}
+
+type storedEvent struct {
+	id       string
+	streamID string
+	message  json.RawMessage
+}
+
+// InMemoryEventStore is an EventStore keeping the most recent events of all
+// streams in memory. Once the limit is reached, the oldest events are
+// dropped and can no longer be replayed. Event IDs are random.
+type InMemoryEventStore struct {
+	mu        sync.Mutex
+	maxEvents int
+	events    []storedEvent     // ring buffer, the event numbered n is at n % maxEvents
+	count     uint64            // number of events stored so far
+	numbers   map[string]uint64 // event ID -> number, for the events in the buffer
+}
+
+// NewInMemoryEventStore creates an InMemoryEventStore keeping at most
+// maxEvents events. A non-positive value means DefaultMaxStoredEvents.
+func NewInMemoryEventStore(maxEvents int) *InMemoryEventStore {
+	if maxEvents <= 0 {
+		maxEvents = DefaultMaxStoredEvents
+	}
+	return &InMemoryEventStore{maxEvents: maxEvents, numbers: make(map[string]uint64)}
+}
+
+// StoreEvent implements EventStore.
+func (s *InMemoryEventStore) StoreEvent(_ context.Context, streamID string, message json.RawMessage) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event := storedEvent{
+		id:       uuid.NewString(),
+		streamID: streamID,
+		message:  message,
+	}
+	if len(s.events) < s.maxEvents {
+		s.events = append(s.events, event)
+	} else {
+		// Overwrite the oldest event
+		i := s.count % uint64(s.maxEvents)
+		delete(s.numbers, s.events[i].id)
+		s.events[i] = event
+	}
+	s.numbers[event.id] = s.count
+	s.count++
+	return event.id, nil
+}
+
+// ReplayEventsAfter implements EventStore.
+func (s *InMemoryEventStore) ReplayEventsAfter(
+	ctx context.Context,
+	lastEventID string,
+	send func(eventID string, message json.RawMessage) error,
+) (string, error) {
+	s.mu.Lock()
+	last, ok := s.numbers[lastEventID]
+	if !ok {
+		s.mu.Unlock()
+		return "", ErrEventNotFound
+	}
+	streamID := s.events[last%uint64(s.maxEvents)].streamID
+	var missed []storedEvent
+	for n := last + 1; n < s.count; n++ {
+		if event := s.events[n%uint64(s.maxEvents)]; event.streamID == streamID {
+			missed = append(missed, event)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, event := range missed {
+		if err := ctx.Err(); err != nil {
+			return streamID, err
+		}
+		if err := send(event.id, event.message); err != nil {
+			return streamID, err
+		}
+	}
+	return streamID, nil
+}
+
+var _ EventStore = (*InMemoryEventStore)(nil)
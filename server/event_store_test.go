@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryEventStore(t *testing.T) {
+	ctx := context.Background()
+
+	replay := func(t *testing.T, store EventStore, lastEventID string) (string, []string, error) {
+		t.Helper()
+		var messages []string
+		streamID, err := store.ReplayEventsAfter(ctx, lastEventID, func(eventID string, message json.RawMessage) error {
+			messages = append(messages, string(message))
+			return nil
+		})
+		return streamID, messages, err
+	}
+
+	t.Run("replays the later events of the same stream", func(t *testing.T) {
+		store := NewInMemoryEventStore(0)
+		first, err := store.StoreEvent(ctx, "a", json.RawMessage(`1`))
+		require.NoError(t, err)
+		_, err = store.StoreEvent(ctx, "b", json.RawMessage(`2`))
+		require.NoError(t, err)
+		_, err = store.StoreEvent(ctx, "a", json.RawMessage(`3`))
+		require.NoError(t, err)
+
+		streamID, messages, err := replay(t, store, first)
+		require.NoError(t, err)
+		assert.Equal(t, "a", streamID)
+		assert.Equal(t, []string{"3"}, messages)
+	})
+
+	t.Run("event IDs are unique", func(t *testing.T) {
+		store := NewInMemoryEventStore(0)
+		first, err := store.StoreEvent(ctx, "a", json.RawMessage(`1`))
+		require.NoError(t, err)
+		second, err := store.StoreEvent(ctx, "a", json.RawMessage(`1`))
+		require.NoError(t, err)
+		assert.NotEqual(t, first, second)
+	})
+
+	t.Run("oldest events are evicted", func(t *testing.T) {
+		store := NewInMemoryEventStore(2)
+		first, err := store.StoreEvent(ctx, "a", json.RawMessage(`1`))
+		require.NoError(t, err)
+		second, err := store.StoreEvent(ctx, "a", json.RawMessage(`2`))
+		require.NoError(t, err)
+		_, err = store.StoreEvent(ctx, "a", json.RawMessage(`3`))
+		require.NoError(t, err)
+
+		_, _, err = replay(t, store, first)
+		assert.ErrorIs(t, err, ErrEventNotFound)
+
+		_, messages, err := replay(t, store, second)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"3"}, messages)
+	})
+
+	t.Run("replays across the wrap of the buffer", func(t *testing.T) {
+		store := NewInMemoryEventStore(3)
+		var ids []string
+		for i := range 5 {
+			id, err := store.StoreEvent(ctx, "a", json.RawMessage(fmt.Sprint(i)))
+			require.NoError(t, err)
+			ids = append(ids, id)
+		}
+
+		_, _, err := replay(t, store, ids[1])
+		assert.ErrorIs(t, err, ErrEventNotFound)
+
+		_, messages, err := replay(t, store, ids[2])
+		require.NoError(t, err)
+		assert.Equal(t, []string{"3", "4"}, messages)
+	})
+
+	t.Run("unknown event", func(t *testing.T) {
+		store := NewInMemoryEventStore(0)
+		_, _, err := replay(t, store, "unknown")
+		assert.ErrorIs(t, err, ErrEventNotFound)
+	})
+}
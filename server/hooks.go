@@ -4,6 +4,7 @@ package server
 
 import (
 	"context"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
@@ -14,6 +15,15 @@ type OnRegisterSessionHookFunc func(ctx context.Context, session ClientSession)
 // OnUnregisterSessionHookFunc is a hook that will be called when a session is being unregistered.
 type OnUnregisterSessionHookFunc func(ctx context.Context, session ClientSession)
 
+// OnRootsListChangedHookFunc is a hook that will be called when a client reports
+// that its list of roots has changed. Use MCPServer.RequestRoots with ctx to
+// fetch the new roots.
+type OnRootsListChangedHookFunc func(ctx context.Context, session ClientSession)
+
+// OnToolCallTimeoutHookFunc is a hook that will be called when a tool call
+// exceeds its timeout and its handler context is cancelled.
+type OnToolCallTimeoutHookFunc func(ctx context.Context, id any, message *mcp.CallToolRequest, timeout time.Duration)
+
 // BeforeAnyHookFunc is a function that is called after the request is
 // parsed but before the method is called.
 type BeforeAnyHookFunc func(ctx context.Context, id any, method mcp.MCPMethod, message any)
@@ -79,6 +89,12 @@ type OnAfterListResourceTemplatesFunc func(ctx context.Context, id any, message
 type OnBeforeReadResourceFunc func(ctx context.Context, id any, message *mcp.ReadResourceRequest)
 type OnAfterReadResourceFunc func(ctx context.Context, id any, message *mcp.ReadResourceRequest, result *mcp.ReadResourceResult)
 
+type OnBeforeSubscribeFunc func(ctx context.Context, id any, message *mcp.SubscribeRequest)
+type OnAfterSubscribeFunc func(ctx context.Context, id any, message *mcp.SubscribeRequest, result *mcp.EmptyResult)
+
+type OnBeforeUnsubscribeFunc func(ctx context.Context, id any, message *mcp.UnsubscribeRequest)
+type OnAfterUnsubscribeFunc func(ctx context.Context, id any, message *mcp.UnsubscribeRequest, result *mcp.EmptyResult)
+
 type OnBeforeListPromptsFunc func(ctx context.Context, id any, message *mcp.ListPromptsRequest)
 type OnAfterListPromptsFunc func(ctx context.Context, id any, message *mcp.ListPromptsRequest, result *mcp.ListPromptsResult)
 
@@ -91,9 +107,14 @@ type OnAfterListToolsFunc func(ctx context.Context, id any, message *mcp.ListToo
 type OnBeforeCallToolFunc func(ctx context.Context, id any, message *mcp.CallToolRequest)
 type OnAfterCallToolFunc func(ctx context.Context, id any, message *mcp.CallToolRequest, result *mcp.CallToolResult)
 
+type OnBeforeCompleteFunc func(ctx context.Context, id any, message *mcp.CompleteRequest)
+type OnAfterCompleteFunc func(ctx context.Context, id any, message *mcp.CompleteRequest, result *mcp.CompleteResult)
+
 type Hooks struct {
 	OnRegisterSession             []OnRegisterSessionHookFunc
 	OnUnregisterSession           []OnUnregisterSessionHookFunc
+	OnRootsListChanged            []OnRootsListChangedHookFunc
+	OnToolCallTimeout             []OnToolCallTimeoutHookFunc
 	OnBeforeAny                   []BeforeAnyHookFunc
 	OnSuccess                     []OnSuccessHookFunc
 	OnError                       []OnErrorHookFunc
@@ -110,6 +131,10 @@ type Hooks struct {
 	OnAfterListResourceTemplates  []OnAfterListResourceTemplatesFunc
 	OnBeforeReadResource          []OnBeforeReadResourceFunc
 	OnAfterReadResource           []OnAfterReadResourceFunc
+	OnBeforeSubscribe             []OnBeforeSubscribeFunc
+	OnAfterSubscribe              []OnAfterSubscribeFunc
+	OnBeforeUnsubscribe           []OnBeforeUnsubscribeFunc
+	OnAfterUnsubscribe            []OnAfterUnsubscribeFunc
 	OnBeforeListPrompts           []OnBeforeListPromptsFunc
 	OnAfterListPrompts            []OnAfterListPromptsFunc
 	OnBeforeGetPrompt             []OnBeforeGetPromptFunc
@@ -118,6 +143,8 @@ type Hooks struct {
 	OnAfterListTools              []OnAfterListToolsFunc
 	OnBeforeCallTool              []OnBeforeCallToolFunc
 	OnAfterCallTool               []OnAfterCallToolFunc
+	OnBeforeComplete              []OnBeforeCompleteFunc
+	OnAfterComplete               []OnAfterCompleteFunc
 }
 
 func (c *Hooks) AddBeforeAny(hook BeforeAnyHookFunc) {
@@ -209,6 +236,7 @@ func (c *Hooks) onSuccess(ctx context.Context, id any, method mcp.MCPMethod, mes
 // - ErrResourceNotFound: When a resource is not found
 // - ErrPromptNotFound: When a prompt is not found
 // - ErrToolNotFound: When a tool is not found
+// - ErrToolConcurrencyLimit: When a tool call is rejected by the tool's concurrency limit
 func (c *Hooks) onError(ctx context.Context, id any, method mcp.MCPMethod, message any, err error) {
 	if c == nil {
 		return
@@ -244,6 +272,32 @@ func (c *Hooks) UnregisterSession(ctx context.Context, session ClientSession) {
 	}
 }
 
+func (c *Hooks) AddOnRootsListChanged(hook OnRootsListChangedHookFunc) {
+	c.OnRootsListChanged = append(c.OnRootsListChanged, hook)
+}
+
+func (c *Hooks) rootsListChanged(ctx context.Context, session ClientSession) {
+	if c == nil {
+		return
+	}
+	for _, hook := range c.OnRootsListChanged {
+		hook(ctx, session)
+	}
+}
+
+func (c *Hooks) AddOnToolCallTimeout(hook OnToolCallTimeoutHookFunc) {
+	c.OnToolCallTimeout = append(c.OnToolCallTimeout, hook)
+}
+
+func (c *Hooks) toolCallTimeout(ctx context.Context, id any, message *mcp.CallToolRequest, timeout time.Duration) {
+	if c == nil {
+		return
+	}
+	for _, hook := range c.OnToolCallTimeout {
+		hook(ctx, id, message, timeout)
+	}
+}
+
 func (c *Hooks) AddOnRequestInitialization(hook OnRequestInitializationFunc) {
 	c.OnRequestInitialization = append(c.OnRequestInitialization, hook)
 }
@@ -422,6 +476,60 @@ func (c *Hooks) afterReadResource(ctx context.Context, id any, message *mcp.Read
 		hook(ctx, id, message, result)
 	}
 }
+func (c *Hooks) AddBeforeSubscribe(hook OnBeforeSubscribeFunc) {
+	c.OnBeforeSubscribe = append(c.OnBeforeSubscribe, hook)
+}
+
+func (c *Hooks) AddAfterSubscribe(hook OnAfterSubscribeFunc) {
+	c.OnAfterSubscribe = append(c.OnAfterSubscribe, hook)
+}
+
+func (c *Hooks) beforeSubscribe(ctx context.Context, id any, message *mcp.SubscribeRequest) {
+	c.beforeAny(ctx, id, mcp.MethodResourcesSubscribe, message)
+	if c == nil {
+		return
+	}
+	for _, hook := range c.OnBeforeSubscribe {
+		hook(ctx, id, message)
+	}
+}
+
+func (c *Hooks) afterSubscribe(ctx context.Context, id any, message *mcp.SubscribeRequest, result *mcp.EmptyResult) {
+	c.onSuccess(ctx, id, mcp.MethodResourcesSubscribe, message, result)
+	if c == nil {
+		return
+	}
+	for _, hook := range c.OnAfterSubscribe {
+		hook(ctx, id, message, result)
+	}
+}
+func (c *Hooks) AddBeforeUnsubscribe(hook OnBeforeUnsubscribeFunc) {
+	c.OnBeforeUnsubscribe = append(c.OnBeforeUnsubscribe, hook)
+}
+
+func (c *Hooks) AddAfterUnsubscribe(hook OnAfterUnsubscribeFunc) {
+	c.OnAfterUnsubscribe = append(c.OnAfterUnsubscribe, hook)
+}
+
+func (c *Hooks) beforeUnsubscribe(ctx context.Context, id any, message *mcp.UnsubscribeRequest) {
+	c.beforeAny(ctx, id, mcp.MethodResourcesUnsubscribe, message)
+	if c == nil {
+		return
+	}
+	for _, hook := range c.OnBeforeUnsubscribe {
+		hook(ctx, id, message)
+	}
+}
+
+func (c *Hooks) afterUnsubscribe(ctx context.Context, id any, message *mcp.UnsubscribeRequest, result *mcp.EmptyResult) {
+	c.onSuccess(ctx, id, mcp.MethodResourcesUnsubscribe, message, result)
+	if c == nil {
+		return
+	}
+	for _, hook := range c.OnAfterUnsubscribe {
+		hook(ctx, id, message, result)
+	}
+}
 func (c *Hooks) AddBeforeListPrompts(hook OnBeforeListPromptsFunc) {
 	c.OnBeforeListPrompts = append(c.OnBeforeListPrompts, hook)
 }
@@ -530,3 +638,30 @@ func (c *Hooks) afterCallTool(ctx context.Context, id any, message *mcp.CallTool
 		hook(ctx, id, message, result)
 	}
 }
+func (c *Hooks) AddBeforeComplete(hook OnBeforeCompleteFunc) {
+	c.OnBeforeComplete = append(c.OnBeforeComplete, hook)
+}
+
+func (c *Hooks) AddAfterComplete(hook OnAfterCompleteFunc) {
+	c.OnAfterComplete = append(c.OnAfterComplete, hook)
+}
+
+func (c *Hooks) beforeComplete(ctx context.Context, id any, message *mcp.CompleteRequest) {
+	c.beforeAny(ctx, id, mcp.MethodCompletionComplete, message)
+	if c == nil {
+		return
+	}
+	for _, hook := range c.OnBeforeComplete {
+		hook(ctx, id, message)
+	}
+}
+
+func (c *Hooks) afterComplete(ctx context.Context, id any, message *mcp.CompleteRequest, result *mcp.CompleteResult) {
+	c.onSuccess(ctx, id, mcp.MethodCompletionComplete, message, result)
+	if c == nil {
+		return
+	}
+	for _, hook := range c.OnAfterComplete {
+		hook(ctx, id, message, result)
+	}
+}
@@ -15,14 +15,30 @@ type SamplingHandler interface {
 	CreateMessage(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error)
 }
 
+// ElicitationHandler defines the interface for handling elicitation requests from servers.
+type ElicitationHandler interface {
+	Elicit(ctx context.Context, request mcp.ElicitRequest) (*mcp.ElicitResult, error)
+}
+
+// RootsHandler defines the interface for handling roots requests from servers.
+type RootsHandler interface {
+	ListRoots(ctx context.Context, request mcp.ListRootsRequest) (*mcp.ListRootsResult, error)
+}
+
 type InProcessSession struct {
-	sessionID       string
-	notifications   chan mcp.JSONRPCNotification
-	initialized     atomic.Bool
-	loggingLevel    atomic.Value
-	clientInfo      atomic.Value
-	samplingHandler SamplingHandler
-	mu              sync.RWMutex
+	sessionID          string
+	notifications      chan mcp.JSONRPCNotification
+	initialized        atomic.Bool
+	loggingLevel       atomic.Value
+	clientInfo         atomic.Value
+	protocolVersion    atomic.Value
+	samplingHandler    SamplingHandler
+	rootsHandler       RootsHandler
+	elicitationHandler ElicitationHandler
+	mu                 sync.RWMutex
+	resources          sessionItems[ServerResource]
+	resourceTemplates  sessionItems[ServerResourceTemplate]
+	prompts            sessionItems[ServerPrompt]
 }
 
 func NewInProcessSession(sessionID string, samplingHandler SamplingHandler) *InProcessSession {
@@ -41,6 +57,12 @@ func (s *InProcessSession) NotificationChannel() chan<- mcp.JSONRPCNotification
 	return s.notifications
 }
 
+// Notifications returns the channel on which the server delivers
+// notifications to the in-process client.
+func (s *InProcessSession) Notifications() <-chan mcp.JSONRPCNotification {
+	return s.notifications
+}
+
 func (s *InProcessSession) Initialize() {
 	s.loggingLevel.Store(mcp.LoggingLevelError)
 	s.initialized.Store(true)
@@ -63,6 +85,17 @@ func (s *InProcessSession) SetClientInfo(clientInfo mcp.Implementation) {
 	s.clientInfo.Store(clientInfo)
 }
 
+func (s *InProcessSession) GetProtocolVersion() string {
+	if version, ok := s.protocolVersion.Load().(string); ok {
+		return version
+	}
+	return ""
+}
+
+func (s *InProcessSession) SetProtocolVersion(version string) {
+	s.protocolVersion.Store(version)
+}
+
 func (s *InProcessSession) SetLogLevel(level mcp.LoggingLevel) {
 	s.loggingLevel.Store(level)
 }
@@ -75,6 +108,30 @@ func (s *InProcessSession) GetLogLevel() mcp.LoggingLevel {
 	return level.(mcp.LoggingLevel)
 }
 
+func (s *InProcessSession) GetSessionResources() map[string]ServerResource {
+	return s.resources.get()
+}
+
+func (s *InProcessSession) SetSessionResources(resources map[string]ServerResource) {
+	s.resources.set(resources)
+}
+
+func (s *InProcessSession) GetSessionResourceTemplates() map[string]ServerResourceTemplate {
+	return s.resourceTemplates.get()
+}
+
+func (s *InProcessSession) SetSessionResourceTemplates(templates map[string]ServerResourceTemplate) {
+	s.resourceTemplates.set(templates)
+}
+
+func (s *InProcessSession) GetSessionPrompts() map[string]ServerPrompt {
+	return s.prompts.get()
+}
+
+func (s *InProcessSession) SetSessionPrompts(prompts map[string]ServerPrompt) {
+	s.prompts.set(prompts)
+}
+
 func (s *InProcessSession) RequestSampling(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
 	s.mu.RLock()
 	handler := s.samplingHandler
@@ -87,6 +144,44 @@ func (s *InProcessSession) RequestSampling(ctx context.Context, request mcp.Crea
 	return handler.CreateMessage(ctx, request)
 }
 
+// SetRootsHandler sets the handler that answers roots requests from the server.
+func (s *InProcessSession) SetRootsHandler(handler RootsHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rootsHandler = handler
+}
+
+func (s *InProcessSession) ListRoots(ctx context.Context, request mcp.ListRootsRequest) (*mcp.ListRootsResult, error) {
+	s.mu.RLock()
+	handler := s.rootsHandler
+	s.mu.RUnlock()
+
+	if handler == nil {
+		return nil, fmt.Errorf("no roots handler available")
+	}
+
+	return handler.ListRoots(ctx, request)
+}
+
+// SetElicitationHandler sets the handler that answers elicitation requests from the server.
+func (s *InProcessSession) SetElicitationHandler(handler ElicitationHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.elicitationHandler = handler
+}
+
+func (s *InProcessSession) RequestElicitation(ctx context.Context, request mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+	s.mu.RLock()
+	handler := s.elicitationHandler
+	s.mu.RUnlock()
+
+	if handler == nil {
+		return nil, fmt.Errorf("no elicitation handler available")
+	}
+
+	return handler.Elicit(ctx, request)
+}
+
 // GenerateInProcessSessionID generates a unique session ID for inprocess clients
 func GenerateInProcessSessionID() string {
 	return fmt.Sprintf("inprocess-%d", time.Now().UnixNano())
@@ -94,8 +189,13 @@ func GenerateInProcessSessionID() string {
 
 // Ensure interface compliance
 var (
-	_ ClientSession         = (*InProcessSession)(nil)
-	_ SessionWithLogging    = (*InProcessSession)(nil)
-	_ SessionWithClientInfo = (*InProcessSession)(nil)
-	_ SessionWithSampling   = (*InProcessSession)(nil)
+	_ ClientSession              = (*InProcessSession)(nil)
+	_ SessionWithLogging         = (*InProcessSession)(nil)
+	_ SessionWithResources       = (*InProcessSession)(nil)
+	_ SessionWithPrompts         = (*InProcessSession)(nil)
+	_ SessionWithClientInfo      = (*InProcessSession)(nil)
+	_ SessionWithProtocolVersion = (*InProcessSession)(nil)
+	_ SessionWithSampling        = (*InProcessSession)(nil)
+	_ SessionWithRoots           = (*InProcessSession)(nil)
+	_ SessionWithElicitation     = (*InProcessSession)(nil)
 )
@@ -0,0 +1,257 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Levels of the MCP logging levels that have no slog counterpart. Records
+// are mapped onto MCP levels as follows:
+//
+//	slog.LevelDebug -> debug
+//	slog.LevelInfo  -> info
+//	LevelNotice     -> notice
+//	slog.LevelWarn  -> warning
+//	slog.LevelError -> error
+//	LevelCritical   -> critical
+//	LevelAlert      -> alert
+//	LevelEmergency  -> emergency
+//
+// Levels in between are rounded down.
+const (
+	LevelNotice    = slog.LevelInfo + 2
+	LevelCritical  = slog.LevelError + 4
+	LevelAlert     = slog.LevelError + 8
+	LevelEmergency = slog.LevelError + 12
+)
+
+// SlogLevelToLoggingLevel maps a slog level onto an MCP logging level.
+func SlogLevelToLoggingLevel(level slog.Level) mcp.LoggingLevel {
+	switch {
+	case level < slog.LevelInfo:
+		return mcp.LoggingLevelDebug
+	case level < LevelNotice:
+		return mcp.LoggingLevelInfo
+	case level < slog.LevelWarn:
+		return mcp.LoggingLevelNotice
+	case level < slog.LevelError:
+		return mcp.LoggingLevelWarning
+	case level < LevelCritical:
+		return mcp.LoggingLevelError
+	case level < LevelAlert:
+		return mcp.LoggingLevelCritical
+	case level < LevelEmergency:
+		return mcp.LoggingLevelAlert
+	default:
+		return mcp.LoggingLevelEmergency
+	}
+}
+
+// LogHandlerOptions configures a LogHandler.
+type LogHandlerOptions struct {
+	// Logger is the name of the logger sent to the client. It is left out
+	// when empty.
+	Logger string
+	// Next, if set, also handles every record, for instance to keep logging
+	// locally. Its own level applies.
+	Next slog.Handler
+}
+
+// LogHandler is a slog.Handler sending records to the client session found
+// in the context of each record, as notifications/message. Records below the
+// logging level set by the client with logging/setLevel are dropped.
+//
+// The data of the notification is an object holding the record message under
+// "message" and its attributes, groups being nested objects.
+//
+// Use the context-aware slog methods, e.g. InfoContext, with the context of
+// the request, or LoggerFromContext.
+type LogHandler struct {
+	opts LogHandlerOptions
+	// ctx, if set, is used instead of the context of the records
+	ctx  context.Context
+	goas []groupOrAttrs
+}
+
+// groupOrAttrs is a group or attributes added with WithGroup or WithAttrs.
+type groupOrAttrs struct {
+	group string
+	attrs []slog.Attr
+}
+
+// NewLogHandler creates a LogHandler. opts may be nil.
+func NewLogHandler(opts *LogHandlerOptions) *LogHandler {
+	h := &LogHandler{}
+	if opts != nil {
+		h.opts = *opts
+	}
+	return h
+}
+
+// LoggerFromContext returns a logger sending records to the client session
+// of ctx, whatever the context the records are logged with. It is meant for
+// tool, resource and prompt handlers:
+//
+//	server.LoggerFromContext(ctx).Info("fetching", "url", url)
+func LoggerFromContext(ctx context.Context) *slog.Logger {
+	return slog.New(&LogHandler{ctx: ctx})
+}
+
+// Enabled reports whether the client session of ctx accepts records of the
+// given level, or the next handler is enabled.
+func (h *LogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if h.opts.Next != nil && h.opts.Next.Enabled(ctx, level) {
+		return true
+	}
+	return h.sessionEnabled(h.context(ctx), level)
+}
+
+// Handle sends the record to the client session of ctx, and hands it to the
+// next handler.
+func (h *LogHandler) Handle(ctx context.Context, record slog.Record) error {
+	var nextErr error
+	if h.opts.Next != nil && h.opts.Next.Enabled(ctx, record.Level) {
+		nextErr = h.opts.Next.Handle(ctx, record)
+	}
+
+	ctx = h.context(ctx)
+	if !h.sessionEnabled(ctx, record.Level) {
+		return nextErr
+	}
+	server := ServerFromContext(ctx)
+	if server == nil {
+		return nextErr
+	}
+
+	data := map[string]any{"message": record.Message}
+	var groups []string
+	for _, goa := range h.goas {
+		if goa.group != "" {
+			groups = append(groups, goa.group)
+			continue
+		}
+		for _, attr := range goa.attrs {
+			addLogAttr(data, groups, attr)
+		}
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		addLogAttr(data, groups, attr)
+		return true
+	})
+
+	notification := mcp.NewLoggingMessageNotification(SlogLevelToLoggingLevel(record.Level), h.opts.Logger, data)
+	if err := server.SendLogMessageToClient(ctx, notification); err != nil {
+		return err
+	}
+	return nextErr
+}
+
+// WithAttrs returns a handler adding attrs to every record.
+func (h *LogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.with(groupOrAttrs{attrs: attrs}, func(next slog.Handler) slog.Handler {
+		return next.WithAttrs(attrs)
+	})
+}
+
+// WithGroup returns a handler nesting the attributes of every record in the
+// given group.
+func (h *LogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.with(groupOrAttrs{group: name}, func(next slog.Handler) slog.Handler {
+		return next.WithGroup(name)
+	})
+}
+
+func (h *LogHandler) with(goa groupOrAttrs, next func(slog.Handler) slog.Handler) *LogHandler {
+	h2 := *h
+	h2.goas = append(slices.Clip(h.goas), goa)
+	if h.opts.Next != nil {
+		h2.opts.Next = next(h.opts.Next)
+	}
+	return &h2
+}
+
+func (h *LogHandler) context(ctx context.Context) context.Context {
+	if h.ctx != nil {
+		return h.ctx
+	}
+	if ctx == nil {
+		return context.Background()
+	}
+	return ctx
+}
+
+func (h *LogHandler) sessionEnabled(ctx context.Context, level slog.Level) bool {
+	session, ok := ClientSessionFromContext(ctx).(SessionWithLogging)
+	if !ok || !session.Initialized() {
+		return false
+	}
+	return SlogLevelToLoggingLevel(level).ShouldSendTo(session.GetLogLevel())
+}
+
+// addLogAttr adds attr to data, within the given groups.
+func addLogAttr(data map[string]any, groups []string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+	if attr.Value.Kind() == slog.KindGroup {
+		children := attr.Value.Group()
+		if len(children) == 0 {
+			return
+		}
+		if attr.Key != "" {
+			groups = append(slices.Clip(groups), attr.Key)
+		}
+		for _, child := range children {
+			addLogAttr(data, groups, child)
+		}
+		return
+	}
+
+	// Groups are created on demand, so that empty groups are left out
+	for _, group := range groups {
+		nested, ok := data[group].(map[string]any)
+		if !ok {
+			nested = map[string]any{}
+			data[group] = nested
+		}
+		data = nested
+	}
+	data[attr.Key] = logValue(attr.Value)
+}
+
+// logValue converts a slog value into a JSON-serializable value.
+func logValue(v slog.Value) any {
+	switch v.Kind() {
+	case slog.KindDuration:
+		return v.Duration().String()
+	case slog.KindTime:
+		return v.Time().Format(time.RFC3339Nano)
+	case slog.KindAny:
+		switch a := v.Any().(type) {
+		case error:
+			return a.Error()
+		case json.Marshaler:
+			return a
+		default:
+			if _, err := json.Marshal(a); err != nil {
+				return fmt.Sprint(a)
+			}
+			return a
+		}
+	default:
+		return v.Any()
+	}
+}
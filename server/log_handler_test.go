@@ -0,0 +1,152 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestSlogLevelToLoggingLevel(t *testing.T) {
+	tests := []struct {
+		level slog.Level
+		want  mcp.LoggingLevel
+	}{
+		{slog.LevelDebug - 4, mcp.LoggingLevelDebug},
+		{slog.LevelDebug, mcp.LoggingLevelDebug},
+		{slog.LevelInfo, mcp.LoggingLevelInfo},
+		{slog.LevelInfo + 1, mcp.LoggingLevelInfo},
+		{LevelNotice, mcp.LoggingLevelNotice},
+		{slog.LevelWarn, mcp.LoggingLevelWarning},
+		{slog.LevelError, mcp.LoggingLevelError},
+		{LevelCritical, mcp.LoggingLevelCritical},
+		{LevelAlert, mcp.LoggingLevelAlert},
+		{LevelEmergency, mcp.LoggingLevelEmergency},
+		{LevelEmergency + 10, mcp.LoggingLevelEmergency},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, SlogLevelToLoggingLevel(tt.level), tt.level.String())
+	}
+}
+
+func TestLogHandler(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0", WithLogging())
+	session := NewInProcessSession("log-session", nil)
+	session.Initialize()
+	session.SetLogLevel(mcp.LoggingLevelInfo)
+	ctx := server.WithContext(context.WithValue(context.Background(), serverKey{}, server), session)
+
+	receive := func(t *testing.T) mcp.JSONRPCNotification {
+		t.Helper()
+		select {
+		case notification := <-session.Notifications():
+			return notification
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for notification")
+			return mcp.JSONRPCNotification{}
+		}
+	}
+	assertNoNotification := func(t *testing.T) {
+		t.Helper()
+		select {
+		case notification := <-session.Notifications():
+			t.Fatalf("unexpected notification: %+v", notification)
+		default:
+		}
+	}
+
+	t.Run("sends records to the session", func(t *testing.T) {
+		logger := slog.New(NewLogHandler(&LogHandlerOptions{Logger: "tools"}))
+		logger = logger.With("tool", "fetch").WithGroup("request")
+		logger.WarnContext(ctx, "slow response",
+			"url", "https://example.com",
+			"duration", 2*time.Second,
+			"error", errors.New("timeout"),
+			slog.Group("retry", "attempt", 2),
+			slog.Group("empty"),
+		)
+
+		notification := receive(t)
+		assert.Equal(t, "notifications/message", notification.Method)
+		params := notification.Params.AdditionalFields
+		assert.Equal(t, mcp.LoggingLevelWarning, params["level"])
+		assert.Equal(t, "tools", params["logger"])
+		assert.Equal(t, map[string]any{
+			"message": "slow response",
+			"tool":    "fetch",
+			"request": map[string]any{
+				"url":      "https://example.com",
+				"duration": "2s",
+				"error":    "timeout",
+				"retry":    map[string]any{"attempt": int64(2)},
+			},
+		}, params["data"])
+	})
+
+	t.Run("honors the session log level", func(t *testing.T) {
+		logger := slog.New(NewLogHandler(nil))
+		assert.False(t, logger.Enabled(ctx, slog.LevelDebug))
+		assert.True(t, logger.Enabled(ctx, slog.LevelInfo))
+
+		logger.DebugContext(ctx, "hidden")
+		assertNoNotification(t)
+
+		session.SetLogLevel(mcp.LoggingLevelCritical)
+		defer session.SetLogLevel(mcp.LoggingLevelInfo)
+		logger.ErrorContext(ctx, "hidden")
+		assertNoNotification(t)
+		logger.Log(ctx, LevelAlert, "shown")
+		assert.Equal(t, mcp.LoggingLevelAlert, receive(t).Params.AdditionalFields["level"])
+	})
+
+	t.Run("ignores records without session", func(t *testing.T) {
+		logger := slog.New(NewLogHandler(nil))
+		assert.False(t, logger.Enabled(context.Background(), slog.LevelError))
+		logger.Error("no session")
+		assertNoNotification(t)
+	})
+
+	t.Run("logger from context", func(t *testing.T) {
+		LoggerFromContext(ctx).Info("bound", "count", 3)
+
+		data := receive(t).Params.AdditionalFields["data"]
+		assert.Equal(t, map[string]any{"message": "bound", "count": int64(3)}, data)
+	})
+
+	t.Run("tool handler", func(t *testing.T) {
+		server.AddTool(mcp.NewTool("fetch"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			LoggerFromContext(ctx).Info("fetching")
+			return mcp.NewToolResultText("ok"), nil
+		})
+		server.HandleMessage(server.WithContext(context.Background(), session),
+			[]byte(`{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": {"name": "fetch"}}`))
+
+		for {
+			notification := receive(t)
+			if notification.Method == "notifications/message" {
+				assert.Equal(t, map[string]any{"message": "fetching"}, notification.Params.AdditionalFields["data"])
+				break
+			}
+		}
+	})
+
+	t.Run("next handler", func(t *testing.T) {
+		var buf bytes.Buffer
+		next := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+		logger := slog.New(NewLogHandler(&LogHandlerOptions{Next: next})).With("tool", "fetch")
+
+		logger.DebugContext(ctx, "local only")
+		assertNoNotification(t)
+		assert.Contains(t, buf.String(), `msg="local only" tool=fetch`)
+
+		logger.InfoContext(ctx, "both")
+		receive(t)
+		assert.Contains(t, buf.String(), `msg=both tool=fetch`)
+	})
+}
@@ -0,0 +1,65 @@
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"log/slog"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/util"
+)
+
+// Keys of the attributes identifying sessions and messages in the log
+// records of the transports.
+const (
+	logKeySessionID = "sessionID"
+	logKeyMethod    = "method"
+	logKeyRequestID = "requestID"
+	logKeyTool      = "tool"
+	logKeyStreamID  = "streamID"
+	logKeyEventID   = "eventID"
+	logKeyError     = "error"
+)
+
+// slogFromLogLogger returns a slog.Logger writing records through a
+// log.Logger, which keeps its prefix and flags.
+func slogFromLogLogger(logger *log.Logger) *slog.Logger {
+	return util.ToSlog(util.FromLogLogger(logger))
+}
+
+// messageLogAttrs returns the attributes identifying a JSON-RPC message in
+// log records: its method, request ID and, for tool calls, tool name.
+func messageLogAttrs(message any) []any {
+	var attrs []any
+	switch m := message.(type) {
+	case json.RawMessage:
+		var base struct {
+			Method string `json:"method"`
+			ID     any    `json:"id"`
+			Params struct {
+				Name string `json:"name"`
+			} `json:"params"`
+		}
+		if err := json.Unmarshal(m, &base); err != nil {
+			return nil
+		}
+		if base.Method != "" {
+			attrs = append(attrs, logKeyMethod, base.Method)
+		}
+		if base.ID != nil {
+			attrs = append(attrs, logKeyRequestID, base.ID)
+		}
+		if base.Method == string(mcp.MethodToolsCall) && base.Params.Name != "" {
+			attrs = append(attrs, logKeyTool, base.Params.Name)
+		}
+	case mcp.JSONRPCRequest:
+		attrs = append(attrs, logKeyMethod, m.Method, logKeyRequestID, m.ID.Value())
+	case mcp.JSONRPCNotification:
+		attrs = append(attrs, logKeyMethod, m.Method)
+	case mcp.JSONRPCResponse:
+		attrs = append(attrs, logKeyRequestID, m.ID.Value())
+	case mcp.JSONRPCError:
+		attrs = append(attrs, logKeyRequestID, m.ID.Value())
+	}
+	return attrs
+}
@@ -0,0 +1,110 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/metrics"
+)
+
+// WithMetrics records the metrics of the server in m: the count and latency
+// of requests by method and tool, the JSON-RPC errors by code, the active
+// sessions by transport, the dropped notifications and the round-trip time
+// of sampling requests. Serve them with m.Handler().
+func WithMetrics(m *metrics.Metrics) ServerOption {
+	return func(s *MCPServer) {
+		s.metrics = m
+	}
+}
+
+// metricsOtherLabel replaces the method and tool labels chosen by clients
+// that the server doesn't know, so that made-up names don't each create a
+// series.
+const metricsOtherLabel = "other"
+
+// metricsMethods are the methods recorded under their own label.
+var metricsMethods = map[mcp.MCPMethod]bool{
+	mcp.MethodInitialize:             true,
+	mcp.MethodPing:                   true,
+	mcp.MethodResourcesList:          true,
+	mcp.MethodResourcesTemplatesList: true,
+	mcp.MethodResourcesRead:          true,
+	mcp.MethodResourcesSubscribe:     true,
+	mcp.MethodResourcesUnsubscribe:   true,
+	mcp.MethodPromptsList:            true,
+	mcp.MethodPromptsGet:             true,
+	mcp.MethodToolsList:              true,
+	mcp.MethodToolsCall:              true,
+	mcp.MethodSetLogLevel:            true,
+	mcp.MethodCompletionComplete:     true,
+}
+
+// requestMetricsKey is the context key for the requestMetrics of a
+// tools/call request.
+type requestMetricsKey struct{}
+
+// requestMetrics holds the labels of a request only known once it is
+// dispatched.
+type requestMetrics struct {
+	tool string
+}
+
+// startRequestMetrics starts timing a request and returns a function
+// recording it with its response. For tools/call requests, the returned
+// context lets handleToolCall set the tool label with setMetricsTool.
+func (s *MCPServer) startRequestMetrics(ctx context.Context, method mcp.MCPMethod) (context.Context, func(response mcp.JSONRPCMessage)) {
+	if s.metrics == nil {
+		return ctx, func(mcp.JSONRPCMessage) {}
+	}
+
+	methodLabel := string(method)
+	if !metricsMethods[method] {
+		methodLabel = metricsOtherLabel
+	}
+
+	var labels *requestMetrics
+	if method == mcp.MethodToolsCall {
+		// Unknown tools keep the other label
+		labels = &requestMetrics{tool: metricsOtherLabel}
+		ctx = context.WithValue(ctx, requestMetricsKey{}, labels)
+	}
+
+	start := time.Now()
+	return ctx, func(response mcp.JSONRPCMessage) {
+		duration := time.Since(start)
+		var toolLabel string
+		if labels != nil {
+			toolLabel = labels.tool
+		}
+		s.metrics.ObserveRequest(methodLabel, toolLabel, duration)
+		if errResp, ok := response.(mcp.JSONRPCError); ok {
+			s.metrics.ObserveError(methodLabel, errResp.Error.Code)
+		}
+	}
+}
+
+// setMetricsTool sets the tool label of the tools/call request of ctx to the
+// name of the registered tool it calls.
+func setMetricsTool(ctx context.Context, name string) {
+	if labels, ok := ctx.Value(requestMetricsKey{}).(*requestMetrics); ok {
+		labels.tool = name
+	}
+}
+
+// sessionTransport returns the name of the transport of a session, used as
+// metrics label.
+func sessionTransport(session ClientSession) string {
+	switch session.(type) {
+	case *stdioSession:
+		return "stdio"
+	case *sseSession:
+		return "sse"
+	case *streamableHttpSession:
+		return "streamable_http"
+	case *InProcessSession:
+		return "inprocess"
+	default:
+		return "other"
+	}
+}
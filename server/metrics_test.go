@@ -0,0 +1,66 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/metrics"
+)
+
+func TestMCPServer_Metrics(t *testing.T) {
+	m := metrics.New()
+	server := NewMCPServer("test-server", "1.0.0",
+		WithToolCapabilities(false),
+		WithMetrics(m),
+	)
+	server.AddTool(mcp.NewTool("echo"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	session := NewInProcessSession("metrics-session", &tracingSamplingHandler{})
+	session.Initialize()
+	require.NoError(t, server.RegisterSession(context.Background(), session))
+	blocked := fakeSession{
+		sessionID:           "blocked-session",
+		notificationChannel: make(chan mcp.JSONRPCNotification),
+		initialized:         true,
+	}
+	require.NoError(t, server.RegisterSession(context.Background(), blocked))
+	ctx := server.WithContext(context.Background(), session)
+
+	server.HandleMessage(ctx, []byte(`{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": {"name": "echo"}}`))
+	server.HandleMessage(ctx, []byte(`{"jsonrpc": "2.0", "id": 2, "method": "tools/call", "params": {"name": "echo"}}`))
+	server.HandleMessage(ctx, []byte(`{"jsonrpc": "2.0", "id": 3, "method": "tools/call", "params": {"name": "missing"}}`))
+	server.HandleMessage(ctx, []byte(`{"jsonrpc": "2.0", "id": 4, "method": "ping"}`))
+	server.HandleMessage(ctx, []byte(`{"jsonrpc": "2.0", "id": 5, "method": "made/up"}`))
+
+	_, err := server.RequestSampling(ctx, mcp.CreateMessageRequest{})
+	require.NoError(t, err)
+
+	server.SendNotificationToAllClients("notifications/test", nil)
+	server.UnregisterSession(context.Background(), "blocked-session")
+
+	var out strings.Builder
+	_, err = m.WriteTo(&out)
+	require.NoError(t, err)
+	text := out.String()
+
+	assert.Contains(t, text, `mcp_requests_total{method="tools/call",tool="echo"} 2`)
+	assert.Contains(t, text, `mcp_requests_total{method="tools/call",tool="other"} 1`)
+	assert.Contains(t, text, `mcp_requests_total{method="other",tool=""} 1`)
+	assert.Contains(t, text, `mcp_request_errors_total{method="other",code="-32601"} 1`)
+	assert.NotContains(t, text, "missing")
+	assert.NotContains(t, text, "made/up")
+	assert.Contains(t, text, `mcp_requests_total{method="ping",tool=""} 1`)
+	assert.Contains(t, text, `mcp_request_duration_seconds_count{method="tools/call",tool="echo"} 2`)
+	assert.Contains(t, text, `mcp_request_errors_total{method="tools/call",code="-32602"} 1`)
+	assert.Contains(t, text, `mcp_active_sessions{transport="inprocess"} 1`)
+	assert.Contains(t, text, `mcp_active_sessions{transport="other"} 0`)
+	assert.Contains(t, text, `mcp_notifications_dropped_total{method="notifications/test"} 1`)
+	assert.Contains(t, text, `mcp_sampling_duration_seconds_count 1`)
+}
@@ -0,0 +1,159 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// DefaultProgressInterval is the default minimum interval between two progress
+// notifications sent for the same request.
+const DefaultProgressInterval = 100 * time.Millisecond
+
+// progressKey is the context key for the ProgressReporter of a request.
+type progressKey struct{}
+
+// ProgressReporter sends notifications/progress for the request being handled.
+// Handlers obtain it with ProgressFromContext. Reports are throttled: when
+// several reports arrive within the server's progress interval, only the
+// latest one is sent once the interval has elapsed.
+//
+// A nil *ProgressReporter is valid and discards all reports, so handlers can
+// report progress unconditionally.
+type ProgressReporter struct {
+	server   *MCPServer
+	ctx      context.Context
+	token    mcp.ProgressToken
+	interval time.Duration
+
+	mu       sync.Mutex
+	lastSent time.Time
+	pending  *progressUpdate
+	timer    *time.Timer
+	closed   bool
+}
+
+type progressUpdate struct {
+	progress float64
+	total    float64
+	message  string
+}
+
+// ProgressFromContext returns the ProgressReporter of the request being
+// handled. It returns nil, which is a no-op reporter, if the client did not
+// ask for progress notifications.
+func ProgressFromContext(ctx context.Context) *ProgressReporter {
+	reporter, _ := ctx.Value(progressKey{}).(*ProgressReporter)
+	return reporter
+}
+
+// Token returns the progress token sent by the client, or nil if there is none.
+func (p *ProgressReporter) Token() mcp.ProgressToken {
+	if p == nil {
+		return nil
+	}
+	return p.token
+}
+
+// Report reports the progress of the request. The progress value should
+// increase with every call. A total of zero means the total is unknown, and
+// an empty message is omitted.
+func (p *ProgressReporter) Report(progress, total float64, message string) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return
+	}
+
+	update := &progressUpdate{progress: progress, total: total, message: message}
+	if wait := p.interval - time.Since(p.lastSent); wait > 0 {
+		p.pending = update
+		if p.timer == nil {
+			p.timer = time.AfterFunc(wait, p.flush)
+		}
+		return
+	}
+	p.send(update)
+}
+
+// flush sends the pending update, if any. It is called when the throttling
+// interval has elapsed.
+func (p *ProgressReporter) flush() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.timer = nil
+	if p.closed || p.pending == nil {
+		return
+	}
+	p.send(p.pending)
+	p.pending = nil
+}
+
+// close sends the pending update unless the request was cancelled, and
+// discards all further reports. It is called before the response to the
+// request is returned, so the last progress update precedes the response.
+func (p *ProgressReporter) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.timer != nil {
+		p.timer.Stop()
+		p.timer = nil
+	}
+	if p.pending != nil && p.ctx.Err() == nil {
+		p.send(p.pending)
+	}
+	p.pending = nil
+	p.closed = true
+}
+
+// send sends a progress notification. The caller must hold p.mu.
+func (p *ProgressReporter) send(update *progressUpdate) {
+	p.lastSent = time.Now()
+
+	params := map[string]any{
+		"progressToken": p.token,
+		"progress":      update.progress,
+	}
+	if update.total != 0 {
+		params["total"] = update.total
+	}
+	if update.message != "" {
+		params["message"] = update.message
+	}
+	// Delivery failures are reported through the OnError hooks.
+	_ = p.server.SendNotificationToClient(p.ctx, mcp.MethodNotificationProgress, params)
+}
+
+// withProgressReporter attaches a ProgressReporter to the context if the
+// request carries a progress token. The returned function must be called
+// once the request has been handled.
+func (s *MCPServer) withProgressReporter(
+	ctx context.Context,
+	message json.RawMessage,
+) (context.Context, func()) {
+	var request struct {
+		Params struct {
+			Meta *mcp.Meta `json:"_meta,omitempty"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal(message, &request); err != nil ||
+		request.Params.Meta == nil || request.Params.Meta.ProgressToken == nil {
+		return ctx, func() {}
+	}
+
+	reporter := &ProgressReporter{
+		server:   s,
+		token:    request.Params.Meta.ProgressToken,
+		interval: s.progressInterval,
+	}
+	ctx = context.WithValue(ctx, progressKey{}, reporter)
+	reporter.ctx = ctx
+	return ctx, reporter.close
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCPServer_ProgressReporter(t *testing.T) {
+	newServer := func(opts ...ServerOption) *MCPServer {
+		server := NewMCPServer("test-server", "1.0.0", opts...)
+		server.AddTool(mcp.NewTool("work"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			progress := ProgressFromContext(ctx)
+			for i := 1; i <= 10; i++ {
+				progress.Report(float64(i), 10, "working")
+			}
+			return mcp.NewToolResultText("done"), nil
+		})
+		return server
+	}
+	newContext := func(server *MCPServer) (context.Context, chan mcp.JSONRPCNotification) {
+		notifications := make(chan mcp.JSONRPCNotification, 20)
+		return server.WithContext(context.Background(), fakeSession{
+			sessionID:           "session-1",
+			notificationChannel: notifications,
+			initialized:         true,
+		}), notifications
+	}
+	drain := func(notifications chan mcp.JSONRPCNotification) []mcp.JSONRPCNotification {
+		var received []mcp.JSONRPCNotification
+		for {
+			select {
+			case notification := <-notifications:
+				received = append(received, notification)
+			default:
+				return received
+			}
+		}
+	}
+
+	t.Run("no-op without progress token", func(t *testing.T) {
+		server := newServer()
+		ctx, notifications := newContext(server)
+
+		response := server.HandleMessage(ctx, []byte(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"method": "tools/call",
+			"params": {"name": "work"}
+		}`))
+		_, ok := response.(mcp.JSONRPCResponse)
+		require.True(t, ok)
+		assert.Empty(t, drain(notifications))
+		assert.Nil(t, ProgressFromContext(context.Background()).Token())
+	})
+
+	t.Run("coalesces bursts and flushes before the response", func(t *testing.T) {
+		server := newServer(WithProgressInterval(time.Hour))
+		ctx, notifications := newContext(server)
+
+		response := server.HandleMessage(ctx, []byte(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"method": "tools/call",
+			"params": {"name": "work", "_meta": {"progressToken": "abc"}}
+		}`))
+		_, ok := response.(mcp.JSONRPCResponse)
+		require.True(t, ok)
+
+		received := drain(notifications)
+		require.Len(t, received, 2)
+		for _, notification := range received {
+			assert.Equal(t, mcp.MethodNotificationProgress, notification.Method)
+			assert.Equal(t, "abc", notification.Params.AdditionalFields["progressToken"])
+			assert.Equal(t, float64(10), notification.Params.AdditionalFields["total"])
+			assert.Equal(t, "working", notification.Params.AdditionalFields["message"])
+		}
+		assert.Equal(t, float64(1), received[0].Params.AdditionalFields["progress"])
+		assert.Equal(t, float64(10), received[1].Params.AdditionalFields["progress"])
+	})
+
+	t.Run("sends every report without throttling", func(t *testing.T) {
+		server := newServer(WithProgressInterval(0))
+		ctx, notifications := newContext(server)
+
+		server.HandleMessage(ctx, []byte(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"method": "tools/call",
+			"params": {"name": "work", "_meta": {"progressToken": 42}}
+		}`))
+		assert.Len(t, drain(notifications), 10)
+	})
+}
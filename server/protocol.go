@@ -0,0 +1,14 @@
+package server
+
+import (
+	"context"
+)
+
+// sessionProtocolVersion returns the protocol version negotiated by the
+// session in ctx, or an empty string if it is not known.
+func sessionProtocolVersion(ctx context.Context) string {
+	if session, ok := ClientSessionFromContext(ctx).(SessionWithProtocolVersion); ok {
+		return session.GetProtocolVersion()
+	}
+	return ""
+}
@@ -0,0 +1,320 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// RateLimit configures a token bucket: a burst of Burst requests is allowed,
+// then Rate requests per second. A zero Rate makes the bucket a quota that
+// never refills. Burst must be at least 1.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimitScope identifies which limit of a RateLimitConfig rejected a request.
+type RateLimitScope string
+
+const (
+	RateLimitScopeSession RateLimitScope = "session"
+	RateLimitScopeClient  RateLimitScope = "client"
+	RateLimitScopeMethod  RateLimitScope = "method"
+	RateLimitScopeTool    RateLimitScope = "tool"
+)
+
+// RateLimitConfig configures the limits enforced by a RateLimiter. A request
+// must be allowed by every limit applying to it. Requests without a session
+// ID, e.g. those of stateless streamable HTTP servers, can't be told apart:
+// only the Client limit applies to them.
+type RateLimitConfig struct {
+	// Session limits the requests of each session.
+	Session *RateLimit
+	// Client limits the requests of all the sessions of a client, identified
+	// by the name it sent during initialization. It only applies to sessions
+	// implementing SessionWithClientInfo.
+	Client *RateLimit
+	// Methods limits the requests of each session by method.
+	Methods map[mcp.MCPMethod]RateLimit
+	// Tools limits the tools/call requests of each session by tool name.
+	Tools map[string]RateLimit
+}
+
+// RateLimitError is returned when a request is rejected by a RateLimiter.
+// It wraps ErrRateLimited.
+type RateLimitError struct {
+	Scope RateLimitScope
+	// Key is the session ID, client name, method or tool name whose limit
+	// was exceeded.
+	Key string
+	// RetryAfter is the time until the request would be allowed, or zero if
+	// the limit is a quota that never refills.
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter == 0 {
+		return fmt.Sprintf("%s quota exhausted for '%s'", e.Scope, e.Key)
+	}
+	return fmt.Sprintf("%s rate limit exceeded for '%s', retry after %s", e.Scope, e.Key, e.RetryAfter)
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return ErrRateLimited
+}
+
+// RateLimitStats holds the counters of a RateLimiter.
+type RateLimitStats struct {
+	Allowed  uint64
+	Rejected map[RateLimitScope]uint64
+}
+
+// RateLimiter throttles the requests handled by an MCPServer with token
+// buckets. Use it with WithRateLimiter.
+type RateLimiter struct {
+	config RateLimitConfig
+	now    func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[rateLimitKey]*tokenBucket
+	lastSweep time.Time
+
+	allowed  atomic.Uint64
+	rejected sync.Map // RateLimitScope -> *atomic.Uint64
+}
+
+type rateLimitKey struct {
+	scope     RateLimitScope
+	sessionID string
+	name      string
+}
+
+type tokenBucket struct {
+	limit  RateLimit
+	tokens float64
+	last   time.Time
+}
+
+// rateLimitSweepInterval is the minimum interval between two removals of the
+// buckets that have refilled, and so are equivalent to new buckets.
+const rateLimitSweepInterval = time.Minute
+
+// NewRateLimiter creates a RateLimiter enforcing the given limits. It panics
+// if a limit has a negative Rate or a Burst lower than 1, since such a bucket
+// would reject every request.
+func NewRateLimiter(config RateLimitConfig) *RateLimiter {
+	if config.Session != nil {
+		config.Session.mustBeValid(RateLimitScopeSession, "")
+	}
+	if config.Client != nil {
+		config.Client.mustBeValid(RateLimitScopeClient, "")
+	}
+	for method, limit := range config.Methods {
+		limit.mustBeValid(RateLimitScopeMethod, string(method))
+	}
+	for name, limit := range config.Tools {
+		limit.mustBeValid(RateLimitScopeTool, name)
+	}
+	return &RateLimiter{
+		config:  config,
+		now:     time.Now,
+		buckets: make(map[rateLimitKey]*tokenBucket),
+	}
+}
+
+func (r RateLimit) mustBeValid(scope RateLimitScope, key string) {
+	if key != "" {
+		key = fmt.Sprintf(" '%s'", key)
+	}
+	if r.Burst < 1 {
+		panic(fmt.Sprintf("server: %s rate limit%s has a burst of %d, must be at least 1", scope, key, r.Burst))
+	}
+	if !(r.Rate >= 0) {
+		panic(fmt.Sprintf("server: %s rate limit%s has a rate of %v, must not be negative", scope, key, r.Rate))
+	}
+}
+
+// WithRateLimiter rejects the requests exceeding the limits of the given
+// RateLimiter before they are dispatched, with a RATE_LIMITED error whose
+// data holds the scope of the limit and, if it refills, the delay after
+// which to retry in milliseconds. The OnError hooks receive a
+// *RateLimitError, wrapping ErrRateLimited.
+func WithRateLimiter(limiter *RateLimiter) ServerOption {
+	return func(s *MCPServer) {
+		s.rateLimiter = limiter
+	}
+}
+
+// Stats returns the number of allowed requests and of rejected requests by
+// scope.
+func (l *RateLimiter) Stats() RateLimitStats {
+	stats := RateLimitStats{
+		Allowed:  l.allowed.Load(),
+		Rejected: make(map[RateLimitScope]uint64),
+	}
+	l.rejected.Range(func(key, value any) bool {
+		stats.Rejected[key.(RateLimitScope)] = value.(*atomic.Uint64).Load()
+		return true
+	})
+	return stats
+}
+
+// Allow takes a token from every bucket applying to a request, or none if
+// any of them is empty, in which case the returned error describes the
+// first exceeded limit.
+func (l *RateLimiter) Allow(session ClientSession, method mcp.MCPMethod, toolName string) error {
+	var sessionID, clientName string
+	if session != nil {
+		sessionID = session.SessionID()
+		if withInfo, ok := session.(SessionWithClientInfo); ok {
+			clientName = withInfo.GetClientInfo().Name
+		}
+	}
+
+	type applicable struct {
+		key   rateLimitKey
+		limit RateLimit
+	}
+	var limits []applicable
+	if l.config.Client != nil && clientName != "" {
+		limits = append(limits, applicable{rateLimitKey{RateLimitScopeClient, "", clientName}, *l.config.Client})
+	}
+	// Otherwise a single client could use up the limits of all the requests
+	// without a session ID
+	if sessionID != "" {
+		if l.config.Session != nil {
+			limits = append(limits, applicable{rateLimitKey{RateLimitScopeSession, sessionID, sessionID}, *l.config.Session})
+		}
+		if limit, ok := l.config.Methods[method]; ok {
+			limits = append(limits, applicable{rateLimitKey{RateLimitScopeMethod, sessionID, string(method)}, limit})
+		}
+		if limit, ok := l.config.Tools[toolName]; ok && method == mcp.MethodToolsCall {
+			limits = append(limits, applicable{rateLimitKey{RateLimitScopeTool, sessionID, toolName}, limit})
+		}
+	}
+	if len(limits) == 0 {
+		l.allowed.Add(1)
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	buckets := make([]*tokenBucket, len(limits))
+	for i, applicable := range limits {
+		bucket, ok := l.buckets[applicable.key]
+		if !ok {
+			bucket = &tokenBucket{limit: applicable.limit, tokens: float64(applicable.limit.Burst), last: now}
+			l.buckets[applicable.key] = bucket
+		}
+		bucket.refill(now)
+		if bucket.tokens < 1 {
+			l.countRejected(applicable.key.scope)
+			return &RateLimitError{
+				Scope:      applicable.key.scope,
+				Key:        applicable.key.name,
+				RetryAfter: bucket.retryAfter(),
+			}
+		}
+		buckets[i] = bucket
+	}
+	for _, bucket := range buckets {
+		bucket.tokens--
+	}
+	l.allowed.Add(1)
+	return nil
+}
+
+func (l *RateLimiter) countRejected(scope RateLimitScope) {
+	counter, _ := l.rejected.LoadOrStore(scope, new(atomic.Uint64))
+	counter.(*atomic.Uint64).Add(1)
+}
+
+// removeSession drops the buckets of a session that ended, including the
+// quotas, which are never swept.
+func (l *RateLimiter) removeSession(sessionID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for key := range l.buckets {
+		if key.sessionID == sessionID && key.scope != RateLimitScopeClient {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// sweep drops the buckets that have refilled, so that the buckets of ended
+// sessions do not accumulate. l.mu must be held.
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < rateLimitSweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, bucket := range l.buckets {
+		bucket.refill(now)
+		if bucket.limit.Rate > 0 && bucket.tokens >= float64(bucket.limit.Burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(float64(b.limit.Burst), b.tokens+elapsed*b.limit.Rate)
+	}
+	b.last = now
+}
+
+// retryAfter returns the time until the bucket holds a token again, or zero
+// if it never refills.
+func (b *tokenBucket) retryAfter() time.Duration {
+	if b.limit.Rate <= 0 {
+		return 0
+	}
+	return time.Duration(math.Ceil((1 - b.tokens) / b.limit.Rate * float64(time.Second)))
+}
+
+// checkRateLimit applies the server's rate limiter, if any, to a request.
+func (s *MCPServer) checkRateLimit(ctx context.Context, id any, method mcp.MCPMethod, message json.RawMessage) *requestError {
+	if s.rateLimiter == nil {
+		return nil
+	}
+
+	var toolName string
+	if method == mcp.MethodToolsCall && len(s.rateLimiter.config.Tools) > 0 {
+		var request struct {
+			Params struct {
+				Name string `json:"name"`
+			} `json:"params"`
+		}
+		// Malformed requests are rejected when dispatched
+		_ = json.Unmarshal(message, &request)
+		toolName = request.Params.Name
+	}
+
+	err := s.rateLimiter.Allow(ClientSessionFromContext(ctx), method, toolName)
+	if err == nil {
+		return nil
+	}
+	limitErr := err.(*RateLimitError)
+	data := map[string]any{"scope": limitErr.Scope}
+	if limitErr.RetryAfter > 0 {
+		// Round up, so that retrying after the delay succeeds
+		data["retryAfterMs"] = (limitErr.RetryAfter + time.Millisecond - 1).Milliseconds()
+	}
+	return &requestError{
+		id:   id,
+		code: mcp.RATE_LIMITED,
+		err:  err,
+		data: data,
+	}
+}
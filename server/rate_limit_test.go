@@ -0,0 +1,186 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func newTestRateLimiter(config RateLimitConfig) (*RateLimiter, *time.Time) {
+	limiter := NewRateLimiter(config)
+	now := time.Unix(1700000000, 0)
+	limiter.now = func() time.Time { return now }
+	return limiter, &now
+}
+
+func TestRateLimiter_TokenBucket(t *testing.T) {
+	limiter, now := newTestRateLimiter(RateLimitConfig{
+		Session: &RateLimit{Rate: 2, Burst: 2},
+	})
+	session := NewInProcessSession("session-1", nil)
+
+	require.NoError(t, limiter.Allow(session, mcp.MethodPing, ""))
+	require.NoError(t, limiter.Allow(session, mcp.MethodPing, ""))
+
+	err := limiter.Allow(session, mcp.MethodPing, "")
+	require.ErrorIs(t, err, ErrRateLimited)
+	var limitErr *RateLimitError
+	require.True(t, errors.As(err, &limitErr))
+	assert.Equal(t, RateLimitScopeSession, limitErr.Scope)
+	assert.Equal(t, "session-1", limitErr.Key)
+	assert.Equal(t, 500*time.Millisecond, limitErr.RetryAfter)
+
+	// Other sessions have their own bucket
+	assert.NoError(t, limiter.Allow(NewInProcessSession("session-2", nil), mcp.MethodPing, ""))
+
+	*now = now.Add(500 * time.Millisecond)
+	assert.NoError(t, limiter.Allow(session, mcp.MethodPing, ""))
+	assert.Error(t, limiter.Allow(session, mcp.MethodPing, ""))
+
+	stats := limiter.Stats()
+	assert.Equal(t, uint64(4), stats.Allowed)
+	assert.Equal(t, map[RateLimitScope]uint64{RateLimitScopeSession: 2}, stats.Rejected)
+}
+
+func TestRateLimiter_Quota(t *testing.T) {
+	limiter, now := newTestRateLimiter(RateLimitConfig{
+		Tools: map[string]RateLimit{"expensive": {Burst: 1}},
+	})
+	session := NewInProcessSession("session-1", nil)
+
+	require.NoError(t, limiter.Allow(session, mcp.MethodToolsCall, "expensive"))
+	*now = now.Add(time.Hour)
+
+	var limitErr *RateLimitError
+	require.True(t, errors.As(limiter.Allow(session, mcp.MethodToolsCall, "expensive"), &limitErr))
+	assert.Equal(t, RateLimitScopeTool, limitErr.Scope)
+	assert.Equal(t, "expensive", limitErr.Key)
+	assert.Zero(t, limitErr.RetryAfter)
+	assert.Equal(t, "tool quota exhausted for 'expensive'", limitErr.Error())
+
+	// Other tools and methods are not limited
+	assert.NoError(t, limiter.Allow(session, mcp.MethodToolsCall, "cheap"))
+	assert.NoError(t, limiter.Allow(session, mcp.MethodToolsList, ""))
+}
+
+func TestNewRateLimiter_InvalidLimits(t *testing.T) {
+	assert.PanicsWithValue(t, "server: session rate limit has a burst of 0, must be at least 1", func() {
+		NewRateLimiter(RateLimitConfig{Session: &RateLimit{Rate: 1}})
+	})
+	assert.PanicsWithValue(t, "server: tool rate limit 'render' has a rate of -1, must not be negative", func() {
+		NewRateLimiter(RateLimitConfig{Tools: map[string]RateLimit{"render": {Rate: -1, Burst: 1}}})
+	})
+	assert.NotPanics(t, func() {
+		NewRateLimiter(RateLimitConfig{Methods: map[mcp.MCPMethod]RateLimit{mcp.MethodPing: {Burst: 1}}})
+	})
+}
+
+func TestRateLimiter_ScopesCombine(t *testing.T) {
+	limiter, _ := newTestRateLimiter(RateLimitConfig{
+		Session: &RateLimit{Rate: 1, Burst: 2},
+		Client:  &RateLimit{Rate: 1, Burst: 3},
+		Methods: map[mcp.MCPMethod]RateLimit{mcp.MethodResourcesRead: {Rate: 1, Burst: 1}},
+	})
+	first := NewInProcessSession("session-1", nil)
+	first.SetClientInfo(mcp.Implementation{Name: "agent"})
+	second := NewInProcessSession("session-2", nil)
+	second.SetClientInfo(mcp.Implementation{Name: "agent"})
+
+	require.NoError(t, limiter.Allow(first, mcp.MethodResourcesRead, ""))
+
+	// Rejected by the method limit, without using a token of the others
+	var limitErr *RateLimitError
+	require.True(t, errors.As(limiter.Allow(first, mcp.MethodResourcesRead, ""), &limitErr))
+	assert.Equal(t, RateLimitScopeMethod, limitErr.Scope)
+	assert.Equal(t, string(mcp.MethodResourcesRead), limitErr.Key)
+
+	require.NoError(t, limiter.Allow(first, mcp.MethodPing, ""))
+	require.NoError(t, limiter.Allow(second, mcp.MethodPing, ""))
+
+	// The client limit is shared by the sessions of the client
+	require.True(t, errors.As(limiter.Allow(second, mcp.MethodPing, ""), &limitErr))
+	assert.Equal(t, RateLimitScopeClient, limitErr.Scope)
+	assert.Equal(t, "agent", limitErr.Key)
+}
+
+func TestRateLimiter_WithoutSessionID(t *testing.T) {
+	limiter, _ := newTestRateLimiter(RateLimitConfig{
+		Session: &RateLimit{Burst: 1},
+		Client:  &RateLimit{Burst: 2},
+		Methods: map[mcp.MCPMethod]RateLimit{mcp.MethodPing: {Burst: 1}},
+	})
+
+	// Requests without a session ID are not limited by the session limits,
+	// which they would all share
+	for i := 0; i < 3; i++ {
+		require.NoError(t, limiter.Allow(NewInProcessSession("", nil), mcp.MethodPing, ""))
+	}
+	require.NoError(t, limiter.Allow(nil, mcp.MethodPing, ""))
+
+	// The client limit still applies
+	session := NewInProcessSession("", nil)
+	session.SetClientInfo(mcp.Implementation{Name: "agent"})
+	require.NoError(t, limiter.Allow(session, mcp.MethodPing, ""))
+	require.NoError(t, limiter.Allow(session, mcp.MethodPing, ""))
+	var limitErr *RateLimitError
+	require.True(t, errors.As(limiter.Allow(session, mcp.MethodPing, ""), &limitErr))
+	assert.Equal(t, RateLimitScopeClient, limitErr.Scope)
+}
+
+func TestMCPServer_RateLimiterDropsEndedSessions(t *testing.T) {
+	limiter, _ := newTestRateLimiter(RateLimitConfig{
+		Session: &RateLimit{Burst: 10},
+		Client:  &RateLimit{Burst: 10},
+		Tools:   map[string]RateLimit{"expensive": {Burst: 1}},
+	})
+	server := NewMCPServer("test-server", "1.0.0", WithRateLimiter(limiter))
+	session := NewInProcessSession("session-1", nil)
+	session.SetClientInfo(mcp.Implementation{Name: "agent"})
+	require.NoError(t, server.RegisterSession(context.Background(), session))
+
+	require.NoError(t, limiter.Allow(session, mcp.MethodToolsCall, "expensive"))
+	require.Len(t, limiter.buckets, 3)
+
+	// The quota of the session is dropped with it, the client limit is kept
+	server.UnregisterSession(context.Background(), "session-1")
+	assert.Len(t, limiter.buckets, 1)
+	assert.Contains(t, limiter.buckets, rateLimitKey{RateLimitScopeClient, "", "agent"})
+}
+
+func TestMCPServer_RateLimiter(t *testing.T) {
+	limiter, _ := newTestRateLimiter(RateLimitConfig{
+		Session: &RateLimit{Rate: 1, Burst: 1},
+	})
+	var hookErr error
+	hooks := &Hooks{}
+	hooks.AddOnError(func(ctx context.Context, id any, method mcp.MCPMethod, message any, err error) {
+		hookErr = err
+	})
+	server := NewMCPServer("test-server", "1.0.0", WithRateLimiter(limiter), WithHooks(hooks))
+	ctx := server.WithContext(context.Background(), NewInProcessSession("session-1", nil))
+
+	response := server.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+	_, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok)
+
+	response = server.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":2,"method":"ping"}`))
+	errResp, ok := response.(mcp.JSONRPCError)
+	require.True(t, ok, "unexpected response %#v", response)
+	assert.Equal(t, mcp.RATE_LIMITED, errResp.Error.Code)
+	assert.Equal(t, map[string]any{"scope": RateLimitScopeSession, "retryAfterMs": int64(1000)}, errResp.Error.Data)
+
+	require.ErrorIs(t, hookErr, ErrRateLimited)
+	var limitErr *RateLimitError
+	require.True(t, errors.As(hookErr, &limitErr))
+	assert.Equal(t, RateLimitScopeSession, limitErr.Scope)
+
+	// Notifications are not throttled
+	assert.Nil(t, server.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)))
+	assert.Equal(t, uint64(1), limiter.Stats().Rejected[RateLimitScopeSession])
+}
@@ -15,16 +15,22 @@ import (
 func (s *MCPServer) HandleMessage(
 	ctx context.Context,
 	message json.RawMessage,
-) mcp.JSONRPCMessage {
+) (response mcp.JSONRPCMessage) {
 	// Add server to context
 	ctx = context.WithValue(ctx, serverKey{}, s)
 	var err *requestError
 
+	// Batches are split into their members, each handled as a message
+	if isJSONRPCBatch(message) {
+		return s.handleBatch(ctx, message)
+	}
+
 	var baseMessage struct {
 		JSONRPC string        `json:"jsonrpc"`
 		Method  mcp.MCPMethod `json:"method"`
 		ID      any           `json:"id,omitempty"`
 		Result  any           `json:"result,omitempty"`
+		Error   any           `json:"error,omitempty"`
 	}
 
 	if err := json.Unmarshal(message, &baseMessage); err != nil {
@@ -57,12 +63,42 @@ func (s *MCPServer) HandleMessage(
 		return nil // Return nil for notifications
 	}
 
-	if baseMessage.Result != nil {
-		// this is a response to a request sent by the server (e.g. from a ping
-		// sent due to WithKeepAlive option)
+	if baseMessage.Result != nil || baseMessage.Error != nil {
+		// this is a response to a request sent by the server (e.g. a sampling
+		// request, or a ping sent due to WithKeepAlive option), routed to the
+		// caller waiting for it if any
+		if session, ok := ClientSessionFromContext(ctx).(sessionWithClientResponses); ok {
+			session.deliverResponse(message)
+		}
 		return nil
 	}
 
+	// Trace the request, until its response is complete
+	ctx, finishSpan := s.startRequestSpan(ctx, baseMessage.ID, baseMessage.Method, message)
+	defer func() {
+		finishSpan(response)
+	}()
+
+	// Record the request metrics, once its response is complete
+	ctx, finishMetrics := s.startRequestMetrics(ctx, baseMessage.Method)
+	defer func() {
+		finishMetrics(response)
+	}()
+
+	// Track the request so that a notifications/cancelled from the client can
+	// stop it. The response to a cancelled request is suppressed.
+	ctx, finishRequest := s.trackRequest(ctx, baseMessage.ID, baseMessage.Method)
+	defer func() {
+		if finishRequest() {
+			response = nil
+		}
+	}()
+
+	// Attach a progress reporter if the client asked for progress
+	// notifications. Pending progress is flushed before the response is sent.
+	ctx, finishProgress := s.withProgressReporter(ctx, message)
+	defer finishProgress()
+
 	handleErr := s.hooks.onRequestInitialization(ctx, baseMessage.ID, message)
 	if handleErr != nil {
 		return createErrorResponse(
@@ -72,6 +108,12 @@ func (s *MCPServer) HandleMessage(
 		)
 	}
 
+	// Throttle the request before dispatching it
+	if err := s.checkRateLimit(ctx, baseMessage.ID, baseMessage.Method, message); err != nil {
+		s.hooks.onError(ctx, baseMessage.ID, baseMessage.Method, message, err)
+		return err.ToJSONRPCError()
+	}
+
 	// Get request header from ctx
 	h := ctx.Value(requestHeader)
 	headers, ok := h.(http.Header)
@@ -225,6 +267,58 @@ func (s *MCPServer) HandleMessage(
 		}
 		s.hooks.afterReadResource(ctx, baseMessage.ID, &request, result)
 		return createResponse(baseMessage.ID, *result)
+	case mcp.MethodResourcesSubscribe:
+		var request mcp.SubscribeRequest
+		var result *mcp.EmptyResult
+		if s.capabilities.resources == nil {
+			err = &requestError{
+				id:   baseMessage.ID,
+				code: mcp.METHOD_NOT_FOUND,
+				err:  fmt.Errorf("resources %w", ErrUnsupported),
+			}
+		} else if unmarshalErr := json.Unmarshal(message, &request); unmarshalErr != nil {
+			err = &requestError{
+				id:   baseMessage.ID,
+				code: mcp.INVALID_REQUEST,
+				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: baseMessage.Method},
+			}
+		} else {
+			request.Header = headers
+			s.hooks.beforeSubscribe(ctx, baseMessage.ID, &request)
+			result, err = s.handleSubscribe(ctx, baseMessage.ID, request)
+		}
+		if err != nil {
+			s.hooks.onError(ctx, baseMessage.ID, baseMessage.Method, &request, err)
+			return err.ToJSONRPCError()
+		}
+		s.hooks.afterSubscribe(ctx, baseMessage.ID, &request, result)
+		return createResponse(baseMessage.ID, *result)
+	case mcp.MethodResourcesUnsubscribe:
+		var request mcp.UnsubscribeRequest
+		var result *mcp.EmptyResult
+		if s.capabilities.resources == nil {
+			err = &requestError{
+				id:   baseMessage.ID,
+				code: mcp.METHOD_NOT_FOUND,
+				err:  fmt.Errorf("resources %w", ErrUnsupported),
+			}
+		} else if unmarshalErr := json.Unmarshal(message, &request); unmarshalErr != nil {
+			err = &requestError{
+				id:   baseMessage.ID,
+				code: mcp.INVALID_REQUEST,
+				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: baseMessage.Method},
+			}
+		} else {
+			request.Header = headers
+			s.hooks.beforeUnsubscribe(ctx, baseMessage.ID, &request)
+			result, err = s.handleUnsubscribe(ctx, baseMessage.ID, request)
+		}
+		if err != nil {
+			s.hooks.onError(ctx, baseMessage.ID, baseMessage.Method, &request, err)
+			return err.ToJSONRPCError()
+		}
+		s.hooks.afterUnsubscribe(ctx, baseMessage.ID, &request, result)
+		return createResponse(baseMessage.ID, *result)
 	case mcp.MethodPromptsList:
 		var request mcp.ListPromptsRequest
 		var result *mcp.ListPromptsResult
@@ -329,6 +423,32 @@ func (s *MCPServer) HandleMessage(
 		}
 		s.hooks.afterCallTool(ctx, baseMessage.ID, &request, result)
 		return createResponse(baseMessage.ID, *result)
+	case mcp.MethodCompletionComplete:
+		var request mcp.CompleteRequest
+		var result *mcp.CompleteResult
+		if s.capabilities.completions == nil {
+			err = &requestError{
+				id:   baseMessage.ID,
+				code: mcp.METHOD_NOT_FOUND,
+				err:  fmt.Errorf("completions %w", ErrUnsupported),
+			}
+		} else if unmarshalErr := json.Unmarshal(message, &request); unmarshalErr != nil {
+			err = &requestError{
+				id:   baseMessage.ID,
+				code: mcp.INVALID_REQUEST,
+				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: baseMessage.Method},
+			}
+		} else {
+			request.Header = headers
+			s.hooks.beforeComplete(ctx, baseMessage.ID, &request)
+			result, err = s.handleComplete(ctx, baseMessage.ID, request)
+		}
+		if err != nil {
+			s.hooks.onError(ctx, baseMessage.ID, baseMessage.Method, &request, err)
+			return err.ToJSONRPCError()
+		}
+		s.hooks.afterComplete(ctx, baseMessage.ID, &request, result)
+		return createResponse(baseMessage.ID, *result)
 	default:
 		return createErrorResponse(
 			baseMessage.ID,
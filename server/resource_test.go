@@ -2,6 +2,9 @@ package server
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
 	"testing"
 	"time"
 
@@ -251,3 +254,250 @@ func TestMCPServer_RemoveResource(t *testing.T) {
 		})
 	}
 }
+
+func TestMCPServer_ResourceSubscriptions(t *testing.T) {
+	newServer := func(subscribe bool) *MCPServer {
+		server := NewMCPServer(
+			"test-server",
+			"1.0.0",
+			WithResourceCapabilities(subscribe, false),
+		)
+		server.AddResource(
+			mcp.NewResource("test://resource1", "Resource 1"),
+			func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+				return nil, nil
+			},
+		)
+		server.AddResourceTemplate(
+			mcp.NewResourceTemplate("test://users/{id}", "User"),
+			func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+				return nil, nil
+			},
+		)
+		return server
+	}
+
+	subscribe := func(server *MCPServer, ctx context.Context, method, uri string) mcp.JSONRPCMessage {
+		return server.HandleMessage(ctx, []byte(fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"method": %q,
+			"params": {"uri": %q}
+		}`, method, uri)))
+	}
+
+	t.Run("notifies only subscribed sessions", func(t *testing.T) {
+		server := newServer(true)
+		subscribedChan := make(chan mcp.JSONRPCNotification, 10)
+		otherChan := make(chan mcp.JSONRPCNotification, 10)
+		subscribed := &fakeSession{sessionID: "subscribed", notificationChannel: subscribedChan, initialized: true}
+		other := &fakeSession{sessionID: "other", notificationChannel: otherChan, initialized: true}
+		require.NoError(t, server.RegisterSession(context.Background(), subscribed))
+		require.NoError(t, server.RegisterSession(context.Background(), other))
+
+		ctx := server.WithContext(context.Background(), subscribed)
+		resp := subscribe(server, ctx, "resources/subscribe", "test://resource1")
+		require.IsType(t, mcp.JSONRPCResponse{}, resp)
+		resp = subscribe(server, ctx, "resources/subscribe", "test://users/42")
+		require.IsType(t, mcp.JSONRPCResponse{}, resp)
+
+		server.NotifyResourceUpdated("test://resource1")
+		server.NotifyResourceUpdated("test://users/42")
+		server.NotifyResourceUpdated("test://users/43")
+
+		for _, uri := range []string{"test://resource1", "test://users/42"} {
+			select {
+			case notification := <-subscribedChan:
+				assert.Equal(t, mcp.MethodNotificationResourceUpdated, notification.Method)
+				assert.Equal(t, uri, notification.Params.AdditionalFields["uri"])
+			case <-time.After(time.Second):
+				t.Fatalf("expected notification for %s", uri)
+			}
+		}
+		assert.Empty(t, subscribedChan)
+		assert.Empty(t, otherChan)
+	})
+
+	t.Run("unsubscribe stops notifications", func(t *testing.T) {
+		server := newServer(true)
+		notificationChannel := make(chan mcp.JSONRPCNotification, 10)
+		session := &fakeSession{sessionID: "test", notificationChannel: notificationChannel, initialized: true}
+		require.NoError(t, server.RegisterSession(context.Background(), session))
+
+		ctx := server.WithContext(context.Background(), session)
+		subscribe(server, ctx, "resources/subscribe", "test://resource1")
+		resp := subscribe(server, ctx, "resources/unsubscribe", "test://resource1")
+		require.IsType(t, mcp.JSONRPCResponse{}, resp)
+
+		server.NotifyResourceUpdated("test://resource1")
+		assert.Empty(t, notificationChannel)
+	})
+
+	t.Run("unregistering the session drops its subscriptions", func(t *testing.T) {
+		server := newServer(true)
+		notificationChannel := make(chan mcp.JSONRPCNotification, 10)
+		session := &fakeSession{sessionID: "test", notificationChannel: notificationChannel, initialized: true}
+		require.NoError(t, server.RegisterSession(context.Background(), session))
+
+		ctx := server.WithContext(context.Background(), session)
+		subscribe(server, ctx, "resources/subscribe", "test://resource1")
+		server.UnregisterSession(context.Background(), "test")
+
+		server.subscriptionsMu.RLock()
+		assert.Empty(t, server.resourceSubscriptions)
+		server.subscriptionsMu.RUnlock()
+	})
+
+	t.Run("sessions without an ID can't subscribe", func(t *testing.T) {
+		server := newServer(true)
+		session := &fakeSession{sessionID: "", notificationChannel: make(chan mcp.JSONRPCNotification, 10), initialized: true}
+		ctx := server.WithContext(context.Background(), session)
+
+		resp := subscribe(server, ctx, "resources/subscribe", "test://resource1")
+		errResp, ok := resp.(mcp.JSONRPCError)
+		require.True(t, ok)
+		assert.Equal(t, mcp.INVALID_REQUEST, errResp.Error.Code)
+
+		server.subscriptionsMu.RLock()
+		assert.Empty(t, server.resourceSubscriptions)
+		server.subscriptionsMu.RUnlock()
+	})
+
+	t.Run("unknown resource is rejected", func(t *testing.T) {
+		server := newServer(true)
+		session := &fakeSession{sessionID: "test", notificationChannel: make(chan mcp.JSONRPCNotification, 10), initialized: true}
+		ctx := server.WithContext(context.Background(), session)
+
+		resp := subscribe(server, ctx, "resources/subscribe", "test://missing")
+		errResp, ok := resp.(mcp.JSONRPCError)
+		require.True(t, ok)
+		assert.Equal(t, mcp.RESOURCE_NOT_FOUND, errResp.Error.Code)
+	})
+
+	t.Run("subscribe without capability is rejected", func(t *testing.T) {
+		server := newServer(false)
+		session := &fakeSession{sessionID: "test", notificationChannel: make(chan mcp.JSONRPCNotification, 10), initialized: true}
+		ctx := server.WithContext(context.Background(), session)
+
+		resp := subscribe(server, ctx, "resources/subscribe", "test://resource1")
+		errResp, ok := resp.(mcp.JSONRPCError)
+		require.True(t, ok)
+		assert.Equal(t, mcp.METHOD_NOT_FOUND, errResp.Error.Code)
+	})
+}
+
+func TestStreamableHTTP_ResourceSubscriptions(t *testing.T) {
+	mcpServer := NewMCPServer("test-server", "1.0.0", WithResourceCapabilities(true, false))
+	mcpServer.AddResource(
+		mcp.NewResource("test://resource1", "Resource 1"),
+		func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return nil, nil
+		},
+	)
+	server := NewTestStreamableHTTPServer(mcpServer)
+	defer server.Close()
+
+	resp, err := postJSON(server.URL, initRequest)
+	require.NoError(t, err)
+	resp.Body.Close()
+	sessionID := resp.Header.Get(headerKeySessionID)
+	require.NotEmpty(t, sessionID)
+
+	resp, err = postSessionJSON(server.URL, sessionID, map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "resources/subscribe",
+		"params":  map[string]any{"uri": "test://resource1"},
+	})
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	subscribed := func() bool {
+		mcpServer.subscriptionsMu.RLock()
+		defer mcpServer.subscriptionsMu.RUnlock()
+		_, ok := mcpServer.resourceSubscriptions[sessionID]
+		return ok
+	}
+	require.True(t, subscribed())
+
+	// Closing the GET stream, which the client may reopen, keeps the subscriptions
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set(headerKeySessionID, sessionID)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		_, ok := mcpServer.sessions.Load(sessionID)
+		return ok
+	}, time.Second, 10*time.Millisecond)
+	cancel()
+	resp.Body.Close()
+	require.Eventually(t, func() bool {
+		_, ok := mcpServer.sessions.Load(sessionID)
+		return !ok
+	}, time.Second, 10*time.Millisecond)
+	assert.True(t, subscribed())
+
+	// Deleting the session drops them
+	resp, err = deleteSession(server.URL, sessionID)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.False(t, subscribed())
+}
+
+func TestStreamableHTTP_ResourceSubscriptionsExpire(t *testing.T) {
+	mcpServer := NewMCPServer("test-server", "1.0.0", WithResourceCapabilities(true, false))
+	mcpServer.AddResource(
+		mcp.NewResource("test://resource1", "Resource 1"),
+		func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return nil, nil
+		},
+	)
+	server := NewTestStreamableHTTPServer(mcpServer, WithSessionIdleTimeout(100*time.Millisecond))
+	defer server.Close()
+
+	subscribe := func(sessionID string) map[string]any {
+		resp, err := postSessionJSON(server.URL, sessionID, map[string]any{
+			"jsonrpc": "2.0",
+			"id":      1,
+			"method":  "resources/subscribe",
+			"params":  map[string]any{"uri": "test://resource1"},
+		})
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var response map[string]any
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+		return response
+	}
+	subscribed := func(sessionID string) bool {
+		mcpServer.subscriptionsMu.RLock()
+		defer mcpServer.subscriptionsMu.RUnlock()
+		_, ok := mcpServer.resourceSubscriptions[sessionID]
+		return ok
+	}
+
+	t.Run("dropped when the session expires", func(t *testing.T) {
+		resp, err := postJSON(server.URL, initRequest)
+		require.NoError(t, err)
+		resp.Body.Close()
+		sessionID := resp.Header.Get(headerKeySessionID)
+		require.NotEmpty(t, sessionID)
+
+		response := subscribe(sessionID)
+		require.Nil(t, response["error"])
+		require.True(t, subscribed(sessionID))
+		assert.Eventually(t, func() bool {
+			return !subscribed(sessionID)
+		}, 2*time.Second, 20*time.Millisecond)
+	})
+
+	t.Run("rejected for sessions not initialized by the server", func(t *testing.T) {
+		sessionID := "mcp-session-2c44d701-fd50-44ce-92b8-dec46185a741"
+		response := subscribe(sessionID)
+		require.NotNil(t, response["error"])
+		assert.Equal(t, float64(mcp.INVALID_REQUEST), response["error"].(map[string]any)["code"])
+		assert.False(t, subscribed(sessionID))
+	})
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// SessionWithRoots extends ClientSession to support roots/list requests.
+type SessionWithRoots interface {
+	ClientSession
+	ListRoots(ctx context.Context, request mcp.ListRootsRequest) (*mcp.ListRootsResult, error)
+}
+
+// rootsCacheEntry holds the roots last reported by a client. The generation
+// is bumped whenever the client reports that its roots changed, so that a
+// roots/list response racing with the change is not cached.
+type rootsCacheEntry struct {
+	roots      []mcp.Root
+	valid      bool
+	generation uint64
+}
+
+// RequestRoots returns the roots of the client of the current session,
+// sending a roots/list request to the client if they are not cached yet.
+// The cache is invalidated when the client sends
+// notifications/roots/list_changed. The client must have declared the roots
+// capability during initialization.
+func (s *MCPServer) RequestRoots(ctx context.Context) (*mcp.ListRootsResult, error) {
+	session := ClientSessionFromContext(ctx)
+	if session == nil {
+		return nil, fmt.Errorf("no active session")
+	}
+	rootsSession, ok := session.(SessionWithRoots)
+	if !ok {
+		return nil, ErrSessionDoesNotSupportRoots
+	}
+
+	// Sessions without an ID (e.g. stateless streamable HTTP) are not cached,
+	// since they can't be told apart, nor are the sessions whose end the
+	// server is not told about
+	sessionID := session.SessionID()
+	cached := sessionID != "" && sessionKeepsState(session)
+	s.rootsMu.Lock()
+	entry := s.roots[sessionID]
+	s.rootsMu.Unlock()
+	if cached && entry.valid {
+		return &mcp.ListRootsResult{Roots: slices.Clone(entry.roots)}, nil
+	}
+
+	request := mcp.ListRootsRequest{
+		Request: mcp.Request{
+			Method: string(mcp.MethodListRoots),
+		},
+	}
+	result, err := rootsSession.ListRoots(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if cached {
+		s.rootsMu.Lock()
+		if s.roots[sessionID].generation == entry.generation {
+			s.roots[sessionID] = rootsCacheEntry{
+				roots:      slices.Clone(result.Roots),
+				valid:      true,
+				generation: entry.generation,
+			}
+		}
+		s.rootsMu.Unlock()
+	}
+	return result, nil
+}
+
+// handleRootsListChanged invalidates the cached roots of the current session
+// and notifies the OnRootsListChanged hooks.
+func (s *MCPServer) handleRootsListChanged(ctx context.Context) {
+	session := ClientSessionFromContext(ctx)
+	if session == nil {
+		return
+	}
+
+	if sessionKeepsState(session) {
+		s.rootsMu.Lock()
+		entry := s.roots[session.SessionID()]
+		s.roots[session.SessionID()] = rootsCacheEntry{generation: entry.generation + 1}
+		s.rootsMu.Unlock()
+	}
+
+	s.hooks.rootsListChanged(ctx, session)
+}
+
+// removeRoots drops the cached roots of a session.
+func (s *MCPServer) removeRoots(sessionID string) {
+	s.rootsMu.Lock()
+	defer s.rootsMu.Unlock()
+	delete(s.roots, sessionID)
+}
@@ -0,0 +1,381 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRootsSession implements SessionWithRoots for testing
+type mockRootsSession struct {
+	mockSession
+	roots []mcp.Root
+	calls atomic.Int32
+}
+
+func (m *mockRootsSession) ListRoots(ctx context.Context, request mcp.ListRootsRequest) (*mcp.ListRootsResult, error) {
+	m.calls.Add(1)
+	return &mcp.ListRootsResult{Roots: m.roots}, nil
+}
+
+func TestMCPServer_RequestRoots(t *testing.T) {
+	t.Run("session without roots support", func(t *testing.T) {
+		server := NewMCPServer("test", "1.0.0")
+		ctx := server.WithContext(context.Background(), &mockSession{sessionID: "test-session"})
+
+		_, err := server.RequestRoots(ctx)
+		assert.ErrorIs(t, err, ErrSessionDoesNotSupportRoots)
+	})
+
+	t.Run("caches roots until the list changes", func(t *testing.T) {
+		var changed atomic.Int32
+		hooks := &Hooks{}
+		hooks.AddOnRootsListChanged(func(ctx context.Context, session ClientSession) {
+			assert.Equal(t, "test-session", session.SessionID())
+			changed.Add(1)
+		})
+		server := NewMCPServer("test", "1.0.0", WithHooks(hooks))
+		session := &mockRootsSession{
+			mockSession: mockSession{sessionID: "test-session"},
+			roots:       []mcp.Root{{URI: "file:///workspace", Name: "workspace"}},
+		}
+		ctx := server.WithContext(context.Background(), session)
+
+		for i := 0; i < 2; i++ {
+			result, err := server.RequestRoots(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, session.roots, result.Roots)
+		}
+		assert.Equal(t, int32(1), session.calls.Load())
+
+		session.roots = []mcp.Root{{URI: "file:///other"}}
+		server.HandleMessage(ctx, []byte(`{"jsonrpc": "2.0", "method": "notifications/roots/list_changed"}`))
+		assert.Equal(t, int32(1), changed.Load())
+
+		result, err := server.RequestRoots(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, session.roots, result.Roots)
+		assert.Equal(t, int32(2), session.calls.Load())
+	})
+}
+
+// newRootsTestServer creates a server with a tool that returns the URI of the
+// client's first root.
+func newRootsTestServer() *MCPServer {
+	mcpServer := NewMCPServer("test-mcp-server", "1.0")
+	mcpServer.AddTool(mcp.NewTool("rootsTool"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := ServerFromContext(ctx).RequestRoots(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return mcp.NewToolResultText(result.Roots[0].URI), nil
+	})
+	return mcpServer
+}
+
+// sseData streams the data fields of the SSE events read from body.
+func sseData(body io.Reader) <-chan string {
+	events := make(chan string, 10)
+	go func() {
+		scanner := bufio.NewScanner(body)
+		for scanner.Scan() {
+			if data, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+				events <- data
+			}
+		}
+	}()
+	return events
+}
+
+func TestSSEServer_RequestRoots(t *testing.T) {
+	testServer := NewTestServer(newRootsTestServer())
+	defer testServer.Close()
+
+	sseResp, err := http.Get(testServer.URL + "/sse")
+	require.NoError(t, err)
+	defer sseResp.Body.Close()
+	events := sseData(sseResp.Body)
+
+	var messageURL string
+	select {
+	case messageURL = <-events:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for endpoint event")
+	}
+
+	post := func(body any) {
+		jsonBody, _ := json.Marshal(body)
+		resp, err := http.Post(messageURL, "application/json", bytes.NewBuffer(jsonBody))
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+	}
+
+	post(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      2,
+		"method":  "tools/call",
+		"params":  map[string]any{"name": "rootsTool"},
+	})
+
+	var request mcp.JSONRPCRequest
+	select {
+	case data := <-events:
+		require.NoError(t, json.Unmarshal([]byte(data), &request))
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for roots/list request")
+	}
+	assert.Equal(t, string(mcp.MethodListRoots), request.Method)
+
+	post(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      request.ID,
+		"result":  map[string]any{"roots": []map[string]any{{"uri": "file:///workspace"}}},
+	})
+
+	select {
+	case data := <-events:
+		assert.Contains(t, data, `"id":2`)
+		assert.Contains(t, data, "file:///workspace")
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for tool result")
+	}
+}
+
+func TestStreamableHTTP_RequestRoots(t *testing.T) {
+	server := NewTestStreamableHTTPServer(newRootsTestServer())
+	defer server.Close()
+
+	resp, err := postJSON(server.URL, initRequest)
+	require.NoError(t, err)
+	resp.Body.Close()
+	sessionID := resp.Header.Get(headerKeySessionID)
+	require.NotEmpty(t, sessionID)
+
+	post := func(body any) *http.Response {
+		jsonBody, _ := json.Marshal(body)
+		req, _ := http.NewRequest(http.MethodPost, server.URL, bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(headerKeySessionID, sessionID)
+		resp, err := server.Client().Do(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp = post(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      2,
+		"method":  "tools/call",
+		"params":  map[string]any{"name": "rootsTool"},
+	})
+	defer resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("content-type"))
+
+	// The roots/list request arrives on the SSE stream of the tool call
+	events := sseData(resp.Body)
+
+	var request mcp.JSONRPCRequest
+	select {
+	case data := <-events:
+		require.NoError(t, json.Unmarshal([]byte(data), &request))
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for roots/list request")
+	}
+	assert.Equal(t, string(mcp.MethodListRoots), request.Method)
+
+	ack := post(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      request.ID,
+		"result":  map[string]any{"roots": []map[string]any{{"uri": "file:///workspace"}}},
+	})
+	ack.Body.Close()
+	assert.Equal(t, http.StatusAccepted, ack.StatusCode)
+
+	select {
+	case data := <-events:
+		var response jsonRPCResponse
+		require.NoError(t, json.Unmarshal([]byte(data), &response))
+		assert.Equal(t, 2, response.ID)
+		assert.Contains(t, data, "file:///workspace")
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for tool result")
+	}
+}
+
+func TestSSEServer_RequestRootsFailsWhenStreamCloses(t *testing.T) {
+	errs := make(chan error, 1)
+	mcpServer := NewMCPServer("test-mcp-server", "1.0")
+	mcpServer.AddTool(mcp.NewTool("rootsTool"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		_, err := ServerFromContext(ctx).RequestRoots(ctx)
+		errs <- err
+		return nil, err
+	})
+	testServer := NewTestServer(mcpServer)
+	defer testServer.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, testServer.URL+"/sse", nil)
+	require.NoError(t, err)
+	sseResp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer sseResp.Body.Close()
+	events := sseData(sseResp.Body)
+
+	var messageURL string
+	select {
+	case messageURL = <-events:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for endpoint event")
+	}
+
+	jsonBody, _ := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      2,
+		"method":  "tools/call",
+		"params":  map[string]any{"name": "rootsTool"},
+	})
+	resp, err := http.Post(messageURL, "application/json", bytes.NewBuffer(jsonBody))
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	select {
+	case <-events:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for roots/list request")
+	}
+
+	// The client goes away without answering
+	cancel()
+
+	select {
+	case err := <-errs:
+		assert.ErrorIs(t, err, ErrSessionTerminated)
+	case <-time.After(2 * time.Second):
+		t.Fatal("RequestRoots did not return when the stream closed")
+	}
+}
+
+func TestStreamableHTTP_RootsCacheLifetime(t *testing.T) {
+	mcpServer := newRootsTestServer()
+	server := NewTestStreamableHTTPServer(mcpServer)
+	defer server.Close()
+
+	resp, err := postJSON(server.URL, initRequest)
+	require.NoError(t, err)
+	resp.Body.Close()
+	sessionID := resp.Header.Get(headerKeySessionID)
+	require.NotEmpty(t, sessionID)
+
+	mcpServer.rootsMu.Lock()
+	mcpServer.roots[sessionID] = rootsCacheEntry{roots: []mcp.Root{{URI: "file:///workspace"}}, valid: true}
+	mcpServer.rootsMu.Unlock()
+	cached := func() bool {
+		mcpServer.rootsMu.Lock()
+		defer mcpServer.rootsMu.Unlock()
+		_, ok := mcpServer.roots[sessionID]
+		return ok
+	}
+
+	// Closing the GET stream, which the client may reopen, keeps the cache
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set(headerKeySessionID, sessionID)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		_, ok := mcpServer.sessions.Load(sessionID)
+		return ok
+	}, time.Second, 10*time.Millisecond)
+	cancel()
+	resp.Body.Close()
+	require.Eventually(t, func() bool {
+		_, ok := mcpServer.sessions.Load(sessionID)
+		return !ok
+	}, time.Second, 10*time.Millisecond)
+	assert.True(t, cached())
+
+	// Deleting the session drops it
+	resp, err = deleteSession(server.URL, sessionID)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.False(t, cached())
+}
+
+func TestStreamableHTTP_SessionStateLifetime(t *testing.T) {
+	mcpServer := newRootsTestServer()
+	streamableServer := NewStreamableHTTPServer(mcpServer, WithSessionIdleTimeout(100*time.Millisecond))
+	server := httptest.NewServer(streamableServer)
+	defer server.Close()
+
+	tracked := func(sessionID string) bool {
+		_, ok := streamableServer.sessionStates.Load(sessionID)
+		return ok
+	}
+	ping := map[string]any{"jsonrpc": "2.0", "id": 1, "method": "ping"}
+
+	t.Run("not kept for unknown sessions", func(t *testing.T) {
+		sessionID := "mcp-session-2c44d701-fd50-44ce-92b8-dec46185a741"
+		resp, err := postSessionJSON(server.URL, sessionID, ping)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.False(t, tracked(sessionID))
+
+		resp, err = postSessionJSON(server.URL, sessionID, map[string]any{
+			"jsonrpc": "2.0",
+			"method":  "notifications/roots/list_changed",
+		})
+		require.NoError(t, err)
+		resp.Body.Close()
+		mcpServer.rootsMu.Lock()
+		_, cached := mcpServer.roots[sessionID]
+		mcpServer.rootsMu.Unlock()
+		assert.False(t, cached)
+	})
+
+	t.Run("released when the session expires", func(t *testing.T) {
+		resp, err := postJSON(server.URL, initRequest)
+		require.NoError(t, err)
+		resp.Body.Close()
+		sessionID := resp.Header.Get(headerKeySessionID)
+		require.NotEmpty(t, sessionID)
+		require.True(t, tracked(sessionID))
+
+		mcpServer.rootsMu.Lock()
+		mcpServer.roots[sessionID] = rootsCacheEntry{roots: []mcp.Root{{URI: "file:///workspace"}}, valid: true}
+		mcpServer.rootsMu.Unlock()
+
+		// A listening client keeps the session alive
+		ctx, cancel := context.WithCancel(context.Background())
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+		req.Header.Set(headerKeySessionID, sessionID)
+		resp, err = http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		time.Sleep(300 * time.Millisecond)
+		assert.True(t, tracked(sessionID))
+		cancel()
+		resp.Body.Close()
+
+		assert.Eventually(t, func() bool {
+			mcpServer.rootsMu.Lock()
+			_, cached := mcpServer.roots[sessionID]
+			mcpServer.rootsMu.Unlock()
+			return !tracked(sessionID) && !cached
+		}, 2*time.Second, 20*time.Millisecond)
+	})
+}
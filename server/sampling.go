@@ -3,8 +3,10 @@ package server
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/tracing"
 )
 
 // EnableSampling enables sampling capabilities for the server.
@@ -17,6 +19,22 @@ func (s *MCPServer) EnableSampling() {
 // RequestSampling sends a sampling request to the client.
 // The client must have declared sampling capability during initialization.
 func (s *MCPServer) RequestSampling(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	ctx, span := tracing.Start(ctx, s.tracer, string(mcp.MethodSamplingCreateMessage))
+	defer span.End()
+
+	start := time.Now()
+	defer func() {
+		s.metrics.ObserveSampling(time.Since(start))
+	}()
+
+	result, err := s.requestSampling(ctx, request)
+	if err != nil {
+		span.RecordError(err)
+	}
+	return result, err
+}
+
+func (s *MCPServer) requestSampling(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
 	session := ClientSessionFromContext(ctx)
 	if session == nil {
 		return nil, fmt.Errorf("no active session")
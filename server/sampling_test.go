@@ -1,10 +1,16 @@
 package server
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMCPServer_RequestSampling_NoSession(t *testing.T) {
@@ -113,3 +119,303 @@ func TestMCPServer_RequestSampling_Success(t *testing.T) {
 		t.Errorf("expected model %q, got %q", "test-model", result.Model)
 	}
 }
+
+func newSamplingTestServer(background chan<- string) *MCPServer {
+	mcpServer := NewMCPServer("test", "1.0.0")
+	mcpServer.EnableSampling()
+	sample := func(ctx context.Context) (string, error) {
+		result, err := mcpServer.RequestSampling(ctx, mcp.CreateMessageRequest{
+			CreateMessageParams: mcp.CreateMessageParams{
+				Messages: []mcp.SamplingMessage{
+					{Role: mcp.RoleUser, Content: mcp.NewTextContent("Hello")},
+				},
+				MaxTokens: 100,
+			},
+		})
+		if err != nil {
+			return "", err
+		}
+		// The content of a result decoded from JSON is a generic map
+		content, ok := result.Content.(map[string]any)
+		if !ok {
+			return "", nil
+		}
+		parsed, err := mcp.ParseContent(content)
+		if err != nil {
+			return "", err
+		}
+		text, _ := mcp.AsTextContent(parsed)
+		if text == nil {
+			return "", nil
+		}
+		return text.Text, nil
+	}
+	mcpServer.AddTool(mcp.NewTool("sample"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		text, err := sample(ctx)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		return mcp.NewToolResultText("sampled: " + text), nil
+	})
+	mcpServer.AddTool(mcp.NewTool("sampleLater"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Sample once the tool call is complete
+		ctx = context.WithoutCancel(ctx)
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			text, err := sample(ctx)
+			if err != nil {
+				text = err.Error()
+			}
+			background <- text
+		}()
+		return mcp.NewToolResultText("started"), nil
+	})
+	return mcpServer
+}
+
+func TestStreamableHTTP_RequestSampling(t *testing.T) {
+	background := make(chan string, 1)
+	server := NewTestStreamableHTTPServer(newSamplingTestServer(background), WithClientRequestTimeout(time.Second))
+	defer server.Close()
+
+	resp, err := postJSON(server.URL, initRequest)
+	require.NoError(t, err)
+	resp.Body.Close()
+	sessionID := resp.Header.Get(headerKeySessionID)
+	require.NotEmpty(t, sessionID)
+
+	send := func(method string, body any) *http.Response {
+		var jsonBody []byte
+		if body != nil {
+			jsonBody, _ = json.Marshal(body)
+		}
+		req, _ := http.NewRequest(method, server.URL, bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(headerKeySessionID, sessionID)
+		resp, err := server.Client().Do(req)
+		require.NoError(t, err)
+		return resp
+	}
+	callTool := func(id int, name string) *http.Response {
+		return send(http.MethodPost, map[string]any{
+			"jsonrpc": "2.0",
+			"id":      id,
+			"method":  "tools/call",
+			"params":  map[string]any{"name": name},
+		})
+	}
+	nextRequest := func(events <-chan string) mcp.JSONRPCRequest {
+		var request mcp.JSONRPCRequest
+		select {
+		case data := <-events:
+			require.NoError(t, json.Unmarshal([]byte(data), &request))
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for sampling request")
+		}
+		assert.Equal(t, string(mcp.MethodSamplingCreateMessage), request.Method)
+		return request
+	}
+	respond := func(request mcp.JSONRPCRequest) {
+		ack := send(http.MethodPost, map[string]any{
+			"jsonrpc": "2.0",
+			"id":      request.ID,
+			"result": map[string]any{
+				"role":    "assistant",
+				"content": map[string]any{"type": "text", "text": "Hi there"},
+				"model":   "test-model",
+			},
+		})
+		ack.Body.Close()
+		assert.Equal(t, http.StatusAccepted, ack.StatusCode)
+	}
+
+	t.Run("request is sent on the stream of the tool call", func(t *testing.T) {
+		resp := callTool(2, "sample")
+		defer resp.Body.Close()
+		assert.Equal(t, "text/event-stream", resp.Header.Get("content-type"))
+		events := sseData(resp.Body)
+
+		respond(nextRequest(events))
+
+		select {
+		case data := <-events:
+			var response jsonRPCResponse
+			require.NoError(t, json.Unmarshal([]byte(data), &response))
+			assert.Equal(t, 2, response.ID)
+			assert.Contains(t, data, "sampled: Hi there")
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for tool result")
+		}
+	})
+
+	t.Run("request is sent on the listening stream once the tool call is complete", func(t *testing.T) {
+		listen := send(http.MethodGet, nil)
+		defer listen.Body.Close()
+		require.Equal(t, http.StatusOK, listen.StatusCode)
+		listenEvents := sseData(listen.Body)
+
+		resp := callTool(3, "sampleLater")
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		respond(nextRequest(listenEvents))
+
+		select {
+		case text := <-background:
+			assert.Equal(t, "Hi there", text)
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for sampling result")
+		}
+	})
+
+	t.Run("request times out without a response", func(t *testing.T) {
+		resp := callTool(4, "sample")
+		defer resp.Body.Close()
+		events := sseData(resp.Body)
+		nextRequest(events)
+
+		select {
+		case data := <-events:
+			assert.Contains(t, data, "timed out")
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for tool result")
+		}
+	})
+
+	t.Run("pending requests fail when the session is terminated", func(t *testing.T) {
+		resp := callTool(5, "sample")
+		defer resp.Body.Close()
+		events := sseData(resp.Body)
+		nextRequest(events)
+
+		deleted := send(http.MethodDelete, nil)
+		deleted.Body.Close()
+		require.Equal(t, http.StatusOK, deleted.StatusCode)
+
+		select {
+		case data := <-events:
+			assert.Contains(t, data, ErrSessionTerminated.Error())
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for tool result")
+		}
+	})
+}
+
+func TestSSEServer_RequestSampling(t *testing.T) {
+	testServer := NewTestServer(newSamplingTestServer(nil))
+	defer testServer.Close()
+
+	sseResp, err := http.Get(testServer.URL + "/sse")
+	require.NoError(t, err)
+	defer sseResp.Body.Close()
+	events := sseData(sseResp.Body)
+
+	var messageURL string
+	select {
+	case messageURL = <-events:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for endpoint event")
+	}
+
+	post := func(body any) {
+		jsonBody, _ := json.Marshal(body)
+		resp, err := http.Post(messageURL, "application/json", bytes.NewBuffer(jsonBody))
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+	}
+	callTool := func(id int) mcp.JSONRPCRequest {
+		post(map[string]any{
+			"jsonrpc": "2.0",
+			"id":      id,
+			"method":  "tools/call",
+			"params":  map[string]any{"name": "sample"},
+		})
+
+		var request mcp.JSONRPCRequest
+		select {
+		case data := <-events:
+			require.NoError(t, json.Unmarshal([]byte(data), &request))
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for sampling request")
+		}
+		assert.Equal(t, string(mcp.MethodSamplingCreateMessage), request.Method)
+		return request
+	}
+	toolResult := func() string {
+		select {
+		case data := <-events:
+			return data
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for tool result")
+			return ""
+		}
+	}
+
+	t.Run("response is routed to the waiting caller", func(t *testing.T) {
+		request := callTool(2)
+		post(map[string]any{
+			"jsonrpc": "2.0",
+			"id":      request.ID,
+			"result": map[string]any{
+				"role":    "assistant",
+				"content": map[string]any{"type": "text", "text": "Hi there"},
+				"model":   "test-model",
+			},
+		})
+
+		data := toolResult()
+		assert.Contains(t, data, `"id":2`)
+		assert.Contains(t, data, "sampled: Hi there")
+	})
+
+	t.Run("error response is routed to the waiting caller", func(t *testing.T) {
+		request := callTool(3)
+		post(map[string]any{
+			"jsonrpc": "2.0",
+			"id":      request.ID,
+			"error":   map[string]any{"code": -1, "message": "user rejected sampling"},
+		})
+
+		data := toolResult()
+		assert.Contains(t, data, `"id":3`)
+		assert.Contains(t, data, "user rejected sampling")
+	})
+}
+
+func TestMCPServer_HandleMessageDeliversClientResponses(t *testing.T) {
+	server := NewMCPServer("test", "1.0.0")
+	session := &sseSession{
+		done:       make(chan struct{}),
+		eventQueue: make(chan string, 1),
+		sessionID:  "session-1",
+	}
+	ctx := server.WithContext(context.Background(), session)
+
+	type sampled struct {
+		result *mcp.CreateMessageResult
+		err    error
+	}
+	results := make(chan sampled, 1)
+	go func() {
+		result, err := session.RequestSampling(ctx, mcp.CreateMessageRequest{})
+		results <- sampled{result, err}
+	}()
+
+	select {
+	case <-session.eventQueue:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for sampling request")
+	}
+
+	response := server.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":1,"result":{"role":"assistant","content":{"type":"text","text":"Hi"},"model":"test-model"}}`))
+	assert.Nil(t, response)
+
+	select {
+	case r := <-results:
+		require.NoError(t, r.err)
+		assert.Equal(t, "test-model", r.result.Model)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for sampling result")
+	}
+}
@@ -0,0 +1,440 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// SchemaViolation describes a value that does not match a JSON Schema.
+type SchemaViolation struct {
+	// Path is the JSON path of the offending value, e.g. "$.items[2].name".
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (v SchemaViolation) String() string {
+	return v.Path + ": " + v.Message
+}
+
+func violationStrings(violations []SchemaViolation) []string {
+	if violations == nil {
+		return nil
+	}
+	out := make([]string, len(violations))
+	for i, v := range violations {
+		out[i] = v.String()
+	}
+	return out
+}
+
+// schemaFromJSON decodes a JSON Schema document into its generic form.
+func schemaFromJSON(v any) (map[string]any, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if raw, ok := v.(json.RawMessage); ok {
+		data = raw
+	} else if data, err = json.Marshal(v); err != nil {
+		return nil, err
+	}
+
+	var schema map[string]any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	return schema, nil
+}
+
+// toolSchemas caches the schemas of a registered tool, decoded on first use,
+// so that they are not decoded again on every call. A nil *toolSchemas, for
+// tools that were not registered through the server, decodes them each time.
+type toolSchemas struct {
+	inputOnce sync.Once
+	input     *compiledSchema
+	inputErr  error
+
+	outputOnce sync.Once
+	output     *compiledSchema
+	outputErr  error
+}
+
+// inputSchema returns the decoded input schema of tool.
+func (c *toolSchemas) inputSchema(tool mcp.Tool) (*compiledSchema, error) {
+	if c == nil {
+		return decodeInputSchema(tool)
+	}
+	c.inputOnce.Do(func() {
+		c.input, c.inputErr = decodeInputSchema(tool)
+	})
+	return c.input, c.inputErr
+}
+
+// outputSchema returns the decoded output schema of tool.
+func (c *toolSchemas) outputSchema(tool mcp.Tool) (*compiledSchema, error) {
+	if c == nil {
+		return decodeOutputSchema(tool)
+	}
+	c.outputOnce.Do(func() {
+		c.output, c.outputErr = decodeOutputSchema(tool)
+	})
+	return c.output, c.outputErr
+}
+
+func decodeInputSchema(tool mcp.Tool) (*compiledSchema, error) {
+	var source any = tool.InputSchema
+	if tool.RawInputSchema != nil {
+		source = tool.RawInputSchema
+	}
+	schema, err := schemaFromJSON(source)
+	if err != nil {
+		return nil, err
+	}
+	return compileSchema(schema), nil
+}
+
+func decodeOutputSchema(tool mcp.Tool) (*compiledSchema, error) {
+	var source any = tool.OutputSchema
+	if tool.RawOutputSchema != nil {
+		source = tool.RawOutputSchema
+	}
+	schema, err := schemaFromJSON(source)
+	if err != nil {
+		return nil, err
+	}
+	return compileSchema(schema), nil
+}
+
+// compiledSchema is a decoded JSON Schema along with its compiled patterns,
+// so that they are compiled once rather than on every validation.
+type compiledSchema struct {
+	schema   map[string]any
+	patterns map[string]*regexp.Regexp // pattern -> compiled pattern, nil if invalid
+}
+
+// compileSchema compiles the patterns found anywhere in schema.
+func compileSchema(schema map[string]any) *compiledSchema {
+	c := &compiledSchema{schema: schema, patterns: make(map[string]*regexp.Regexp)}
+	c.compilePatterns(schema)
+	return c
+}
+
+func (c *compiledSchema) compilePatterns(value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		if pattern, ok := v["pattern"].(string); ok {
+			if _, compiled := c.patterns[pattern]; !compiled {
+				// Invalid patterns are ignored, like unknown keywords
+				re, _ := regexp.Compile(pattern)
+				c.patterns[pattern] = re
+			}
+		}
+		for _, child := range v {
+			c.compilePatterns(child)
+		}
+	case []any:
+		for _, child := range v {
+			c.compilePatterns(child)
+		}
+	}
+}
+
+// validate checks value against the schema, see validateSchema.
+func (c *compiledSchema) validate(value any) []SchemaViolation {
+	return c.validateSchema(c.schema, value, "$")
+}
+
+// normalizeJSON converts an arbitrary Go value into the generic form
+// produced by encoding/json, so that it can be checked against a schema.
+func normalizeJSON(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// validateSchema checks value against the given JSON Schema and returns one
+// violation per mismatch, along with the JSON path of the offending value.
+// Only the commonly used subset of JSON Schema keywords is supported; unknown
+// keywords are ignored.
+func (c *compiledSchema) validateSchema(schema map[string]any, value any, path string) []SchemaViolation {
+	if schema == nil {
+		return nil
+	}
+
+	var violations []SchemaViolation
+	violate := func(format string, args ...any) {
+		violations = append(violations, SchemaViolation{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if t, ok := schema["type"]; ok {
+		if !matchesType(t, value) {
+			violate("expected %s, got %s", describeType(t), jsonTypeOf(value))
+			return violations
+		}
+	}
+
+	if enum, ok := schema["enum"].([]any); ok {
+		found := false
+		for _, candidate := range enum {
+			if reflect.DeepEqual(candidate, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			violate("value %v is not one of %v", value, enum)
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		violations = append(violations, c.validateObject(schema, v, path)...)
+	case []any:
+		if minItems, ok := schemaNumber(schema, "minItems"); ok && float64(len(v)) < minItems {
+			violate("expected at least %v items, got %d", minItems, len(v))
+		}
+		if maxItems, ok := schemaNumber(schema, "maxItems"); ok && float64(len(v)) > maxItems {
+			violate("expected at most %v items, got %d", maxItems, len(v))
+		}
+		if unique, _ := schema["uniqueItems"].(bool); unique {
+		duplicates:
+			for i := range v {
+				for j := 0; j < i; j++ {
+					if reflect.DeepEqual(v[i], v[j]) {
+						violate("items %d and %d are equal", j, i)
+						break duplicates
+					}
+				}
+			}
+		}
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range v {
+				violations = append(violations, c.validateSchema(items, item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	case string:
+		length := float64(len([]rune(v)))
+		if minLength, ok := schemaNumber(schema, "minLength"); ok && length < minLength {
+			violate("expected at least %v characters, got %v", minLength, length)
+		}
+		if maxLength, ok := schemaNumber(schema, "maxLength"); ok && length > maxLength {
+			violate("expected at most %v characters, got %v", maxLength, length)
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			if re := c.patterns[pattern]; re != nil && !re.MatchString(v) {
+				violate("value %q does not match pattern %q", v, pattern)
+			}
+		}
+	case float64:
+		if minimum, ok := schemaNumber(schema, "minimum"); ok && v < minimum {
+			violate("expected a value >= %v, got %v", minimum, v)
+		}
+		if maximum, ok := schemaNumber(schema, "maximum"); ok && v > maximum {
+			violate("expected a value <= %v, got %v", maximum, v)
+		}
+		if multipleOf, ok := schemaNumber(schema, "multipleOf"); ok && multipleOf > 0 {
+			if q := v / multipleOf; math.Abs(q-math.Round(q)) > 1e-9 {
+				violate("expected a multiple of %v, got %v", multipleOf, v)
+			}
+		}
+	}
+
+	return violations
+}
+
+func (c *compiledSchema) validateObject(schema map[string]any, obj map[string]any, path string) []SchemaViolation {
+	var violations []SchemaViolation
+
+	if required, ok := schema["required"].([]any); ok {
+		for _, name := range required {
+			key, ok := name.(string)
+			if !ok {
+				continue
+			}
+			if _, present := obj[key]; !present {
+				violations = append(violations, SchemaViolation{Path: path, Message: fmt.Sprintf("missing required property %q", key)})
+			}
+		}
+	}
+	if minProperties, ok := schemaNumber(schema, "minProperties"); ok && float64(len(obj)) < minProperties {
+		violations = append(violations, SchemaViolation{Path: path, Message: fmt.Sprintf("expected at least %v properties, got %d", minProperties, len(obj))})
+	}
+	if maxProperties, ok := schemaNumber(schema, "maxProperties"); ok && float64(len(obj)) > maxProperties {
+		violations = append(violations, SchemaViolation{Path: path, Message: fmt.Sprintf("expected at most %v properties, got %d", maxProperties, len(obj))})
+	}
+
+	properties, _ := schema["properties"].(map[string]any)
+
+	// Iterate in a stable order so that violations are reported deterministically
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	propertyNames, _ := schema["propertyNames"].(map[string]any)
+
+	for _, key := range keys {
+		childPath := path + "." + key
+		if propertyNames != nil {
+			for _, v := range c.validateSchema(propertyNames, key, childPath) {
+				v.Message = "invalid property name: " + v.Message
+				violations = append(violations, v)
+			}
+		}
+		if propSchema, ok := properties[key].(map[string]any); ok {
+			violations = append(violations, c.validateSchema(propSchema, obj[key], childPath)...)
+			continue
+		}
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				violations = append(violations, SchemaViolation{Path: childPath, Message: "unexpected property"})
+			}
+		case map[string]any:
+			violations = append(violations, c.validateSchema(additional, obj[key], childPath)...)
+		}
+	}
+
+	return violations
+}
+
+// applySchemaDefaults sets the declared default of the properties missing
+// from the objects within value, recursively. Defaults are copied, so that
+// handlers modifying their arguments don't modify the schema.
+func applySchemaDefaults(schema map[string]any, value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		properties, _ := schema["properties"].(map[string]any)
+		for key, prop := range properties {
+			propSchema, ok := prop.(map[string]any)
+			if !ok {
+				continue
+			}
+			if child, present := v[key]; present {
+				applySchemaDefaults(propSchema, child)
+			} else if def, ok := propSchema["default"]; ok {
+				v[key] = copyJSONValue(def)
+			}
+		}
+	case []any:
+		if items, ok := schema["items"].(map[string]any); ok {
+			for _, item := range v {
+				applySchemaDefaults(items, item)
+			}
+		}
+	}
+}
+
+// copyJSONValue returns a deep copy of a value in the generic form produced
+// by encoding/json.
+func copyJSONValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, child := range v {
+			out[key] = copyJSONValue(child)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, child := range v {
+			out[i] = copyJSONValue(child)
+		}
+		return out
+	default:
+		return value
+	}
+}
+
+func matchesType(t any, value any) bool {
+	switch t := t.(type) {
+	case string:
+		return matchesSingleType(t, value)
+	case []any:
+		for _, candidate := range t {
+			if name, ok := candidate.(string); ok && matchesSingleType(name, value) {
+				return true
+			}
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+func matchesSingleType(t string, value any) bool {
+	switch t {
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	default:
+		return true
+	}
+}
+
+func describeType(t any) string {
+	if types, ok := t.([]any); ok {
+		names := make([]string, 0, len(types))
+		for _, name := range types {
+			names = append(names, fmt.Sprint(name))
+		}
+		return strings.Join(names, " or ")
+	}
+	return fmt.Sprint(t)
+}
+
+func jsonTypeOf(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func schemaNumber(schema map[string]any, key string) (float64, bool) {
+	n, ok := schema[key].(float64)
+	return n, ok
+}
@@ -0,0 +1,153 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSchema(t *testing.T) {
+	schema, err := schemaFromJSON(json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string", "minLength": 2},
+			"count": {"type": "integer", "minimum": 0},
+			"mode": {"type": "string", "enum": ["fast", "slow"]},
+			"tags": {"type": "array", "items": {"type": "string"}, "uniqueItems": true},
+			"code": {"type": "string", "pattern": "^[A-Z]{3}$"},
+			"step": {"type": "number", "multipleOf": 0.5},
+			"nested": {
+				"type": "object",
+				"properties": {"flag": {"type": "boolean"}},
+				"additionalProperties": false
+			}
+		},
+		"required": ["name", "count"]
+	}`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		value      string
+		violations []string
+	}{
+		{
+			name:  "valid",
+			value: `{"name": "ok", "count": 3, "mode": "fast", "tags": ["a"], "nested": {"flag": true}}`,
+		},
+		{
+			name:       "wrong root type",
+			value:      `[1, 2]`,
+			violations: []string{"$: expected object, got array"},
+		},
+		{
+			name:       "missing required",
+			value:      `{"name": "ok"}`,
+			violations: []string{`$: missing required property "count"`},
+		},
+		{
+			name:  "nested violations",
+			value: `{"name": "x", "count": 1.5, "mode": "medium", "tags": ["a", 2], "nested": {"flag": "yes", "extra": 1}}`,
+			violations: []string{
+				"$.count: expected integer, got number",
+				"$.mode: value medium is not one of [fast slow]",
+				"$.name: expected at least 2 characters, got 1",
+				"$.nested.extra: unexpected property",
+				"$.nested.flag: expected boolean, got string",
+				"$.tags[1]: expected string, got number",
+			},
+		},
+		{
+			name:       "below minimum",
+			value:      `{"name": "ok", "count": -1}`,
+			violations: []string{"$.count: expected a value >= 0, got -1"},
+		},
+		{
+			name:  "pattern, multipleOf and uniqueItems",
+			value: `{"name": "ok", "count": 1, "code": "abc", "step": 0.7, "tags": ["a", "a"]}`,
+			violations: []string{
+				`$.code: value "abc" does not match pattern "^[A-Z]{3}$"`,
+				"$.step: expected a multiple of 0.5, got 0.7",
+				"$.tags: items 0 and 1 are equal",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var value any
+			require.NoError(t, json.Unmarshal([]byte(tt.value), &value))
+			assert.Equal(t, tt.violations, violationStrings(compileSchema(schema).validate(value)))
+		})
+	}
+}
+
+func TestCompileSchema_Patterns(t *testing.T) {
+	schema, err := schemaFromJSON(json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"code": {"type": "string", "pattern": "^[A-Z]{3}$"},
+			"tags": {"type": "array", "items": {"type": "string", "pattern": "("}}
+		}
+	}`))
+	require.NoError(t, err)
+
+	compiled := compileSchema(schema)
+	require.Len(t, compiled.patterns, 2)
+	assert.NotNil(t, compiled.patterns["^[A-Z]{3}$"])
+	assert.Nil(t, compiled.patterns["("], "invalid patterns are not compiled")
+
+	var value any
+	require.NoError(t, json.Unmarshal([]byte(`{"code": "abc", "tags": ["x"]}`), &value))
+	assert.Equal(t, []string{`$.code: value "abc" does not match pattern "^[A-Z]{3}$"`}, violationStrings(compiled.validate(value)))
+}
+
+func TestApplySchemaDefaults(t *testing.T) {
+	schema, err := schemaFromJSON(json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"limit": {"type": "number", "default": 10},
+			"options": {
+				"type": "object",
+				"properties": {"verbose": {"type": "boolean", "default": false}}
+			},
+			"items": {
+				"type": "array",
+				"items": {"type": "object", "properties": {"weight": {"type": "number", "default": 1}}}
+			}
+		}
+	}`))
+	require.NoError(t, err)
+
+	var value any
+	require.NoError(t, json.Unmarshal([]byte(`{"limit": 5, "options": {}, "items": [{}, {"weight": 2}]}`), &value))
+	applySchemaDefaults(schema, value)
+
+	assert.Equal(t, map[string]any{
+		"limit":   float64(5),
+		"options": map[string]any{"verbose": false},
+		"items":   []any{map[string]any{"weight": float64(1)}, map[string]any{"weight": float64(2)}},
+	}, value)
+}
+
+func TestApplySchemaDefaults_CopiesDefaults(t *testing.T) {
+	schema, err := schemaFromJSON(json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"filter": {"type": "object", "default": {"tags": ["go"]}}
+		}
+	}`))
+	require.NoError(t, err)
+
+	first := map[string]any{}
+	applySchemaDefaults(schema, first)
+	filter := first["filter"].(map[string]any)
+	filter["tags"].([]any)[0] = "rust"
+	filter["limit"] = float64(1)
+
+	second := map[string]any{}
+	applySchemaDefaults(schema, second)
+	assert.Equal(t, map[string]any{"filter": map[string]any{"tags": []any{"go"}}}, second)
+}
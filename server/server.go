@@ -5,12 +5,17 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"slices"
 	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/metrics"
+	"github.com/mark3labs/mcp-go/tracing"
 )
 
 // resourceEntry holds both a resource and its handler
@@ -21,8 +26,9 @@ type resourceEntry struct {
 
 // resourceTemplateEntry holds both a template and its handler
 type resourceTemplateEntry struct {
-	template mcp.ResourceTemplate
-	handler  ResourceTemplateHandlerFunc
+	template    mcp.ResourceTemplate
+	handler     ResourceTemplateHandlerFunc
+	completions map[string]CompletionHandlerFunc
 }
 
 // ServerOption is a function that configures an MCPServer.
@@ -43,19 +49,51 @@ type ToolHandlerFunc func(ctx context.Context, request mcp.CallToolRequest) (*mc
 // ToolHandlerMiddleware is a middleware function that wraps a ToolHandlerFunc.
 type ToolHandlerMiddleware func(ToolHandlerFunc) ToolHandlerFunc
 
+// ResourceHandlerMiddleware is a middleware function that wraps a ResourceHandlerFunc.
+// It applies to the reads of both resources and resource templates.
+type ResourceHandlerMiddleware func(ResourceHandlerFunc) ResourceHandlerFunc
+
+// PromptHandlerMiddleware is a middleware function that wraps a PromptHandlerFunc.
+type PromptHandlerMiddleware func(PromptHandlerFunc) PromptHandlerFunc
+
 // ToolFilterFunc is a function that filters tools based on context, typically using session information.
 type ToolFilterFunc func(ctx context.Context, tools []mcp.Tool) []mcp.Tool
 
+// ResourceFilterFunc is a function that filters resources based on context, typically using session information.
+type ResourceFilterFunc func(ctx context.Context, resources []mcp.Resource) []mcp.Resource
+
+// ResourceTemplateFilterFunc is a function that filters resource templates based on context.
+type ResourceTemplateFilterFunc func(ctx context.Context, templates []mcp.ResourceTemplate) []mcp.ResourceTemplate
+
+// PromptFilterFunc is a function that filters prompts based on context, typically using session information.
+type PromptFilterFunc func(ctx context.Context, prompts []mcp.Prompt) []mcp.Prompt
+
 // ServerTool combines a Tool with its ToolHandlerFunc.
 type ServerTool struct {
 	Tool    mcp.Tool
 	Handler ToolHandlerFunc
+	// Timeout bounds the duration of a call. Zero means the server default
+	// set with WithToolTimeout, and a negative value means no timeout.
+	Timeout time.Duration
+	// MaxConcurrency bounds the number of calls running at once across all
+	// sessions. Zero means the server default set with
+	// WithToolMaxConcurrency, and a negative value means no limit.
+	MaxConcurrency int
+
+	// schemas caches the decoded schemas of the tool once registered
+	schemas *toolSchemas
+	// limiter bounds the concurrent calls of the tool once registered. Each
+	// registration has its own, so that a session tool is not limited by the
+	// calls of the global tool of the same name.
+	limiter *toolLimiter
 }
 
 // ServerPrompt combines a Prompt with its handler function.
 type ServerPrompt struct {
 	Prompt  mcp.Prompt
 	Handler PromptHandlerFunc
+	// Completions maps argument names to their completion providers.
+	Completions map[string]CompletionHandlerFunc
 }
 
 // ServerResource combines a Resource with its handler function.
@@ -64,6 +102,14 @@ type ServerResource struct {
 	Handler  ResourceHandlerFunc
 }
 
+// ServerResourceTemplate combines a ResourceTemplate with its handler function.
+type ServerResourceTemplate struct {
+	Template mcp.ResourceTemplate
+	Handler  ResourceTemplateHandlerFunc
+	// Completions maps URI template variable names to their completion providers.
+	Completions map[string]CompletionHandlerFunc
+}
+
 // serverKey is the context key for storing the server instance
 type serverKey struct{}
 
@@ -105,6 +151,7 @@ type requestError struct {
 	id   any
 	code int
 	err  error
+	data any // optional additional information about the error
 }
 
 func (e *requestError) Error() string {
@@ -122,6 +169,7 @@ func (e *requestError) ToJSONRPCError() mcp.JSONRPCError {
 		}{
 			Code:    e.code,
 			Message: e.err.Error(),
+			Data:    e.data,
 		},
 	}
 }
@@ -144,20 +192,46 @@ type MCPServer struct {
 	notificationHandlersMu sync.RWMutex
 	capabilitiesMu         sync.RWMutex
 	toolFiltersMu          sync.RWMutex
+	listFiltersMu          sync.RWMutex
+	subscriptionsMu        sync.RWMutex
+	inflightMu             sync.Mutex
+	rootsMu                sync.Mutex
 
 	name                   string
 	version                string
 	instructions           string
 	resources              map[string]resourceEntry
 	resourceTemplates      map[string]resourceTemplateEntry
+	resourceSubscriptions  map[string]map[string]struct{} // sessionID -> subscribed resource URIs
 	prompts                map[string]mcp.Prompt
 	promptHandlers         map[string]PromptHandlerFunc
+	promptCompletions      map[string]map[string]CompletionHandlerFunc
+	inflightRequests       map[string]map[string]*inflightRequest // sessionID -> request ID -> in-flight request
+	roots                  map[string]rootsCacheEntry             // sessionID -> cached roots of the client
 	tools                  map[string]ServerTool
 	toolHandlerMiddlewares []ToolHandlerMiddleware
+	resourceMiddlewares    []ResourceHandlerMiddleware
+	promptMiddlewares      []PromptHandlerMiddleware
 	toolFilters            []ToolFilterFunc
+	resourceFilters        []ResourceFilterFunc
+	templateFilters        []ResourceTemplateFilterFunc
+	promptFilters          []PromptFilterFunc
+	enforceFilters         bool
 	notificationHandlers   map[string]NotificationHandlerFunc
 	capabilities           serverCapabilities
 	paginationLimit        *int
+	progressInterval       time.Duration
+	validateToolArguments  bool
+	toolTimeout            time.Duration
+	toolMaxConcurrency     int
+	toolQueueTimeout       time.Duration
+	toolLimitersMu         sync.Mutex
+	toolLimiters           map[string]*toolLimiter
+	maxBatchSize           int
+	batchConcurrency       int
+	rateLimiter            *RateLimiter
+	tracer                 tracing.Tracer
+	metrics                *metrics.Metrics
 	sessions               sync.Map
 	hooks                  *Hooks
 }
@@ -169,12 +243,23 @@ func WithPaginationLimit(limit int) ServerOption {
 	}
 }
 
+// WithProgressInterval sets the minimum interval between two progress
+// notifications sent for the same request. Reports made in between are
+// coalesced and only the latest one is sent. A zero interval disables
+// throttling.
+func WithProgressInterval(interval time.Duration) ServerOption {
+	return func(s *MCPServer) {
+		s.progressInterval = interval
+	}
+}
+
 // serverCapabilities defines the supported features of the MCP server
 type serverCapabilities struct {
-	tools     *toolCapabilities
-	resources *resourceCapabilities
-	prompts   *promptCapabilities
-	logging   *bool
+	tools       *toolCapabilities
+	resources   *resourceCapabilities
+	prompts     *promptCapabilities
+	logging     *bool
+	completions *bool
 }
 
 // resourceCapabilities defines the supported resource-related features
@@ -216,6 +301,31 @@ func WithToolHandlerMiddleware(
 	}
 }
 
+// WithResourceHandlerMiddleware allows adding a middleware for the
+// resource handler call chain, which serves both resources and resource
+// templates.
+func WithResourceHandlerMiddleware(
+	resourceHandlerMiddleware ResourceHandlerMiddleware,
+) ServerOption {
+	return func(s *MCPServer) {
+		s.middlewareMu.Lock()
+		s.resourceMiddlewares = append(s.resourceMiddlewares, resourceHandlerMiddleware)
+		s.middlewareMu.Unlock()
+	}
+}
+
+// WithPromptHandlerMiddleware allows adding a middleware for the
+// prompt handler call chain.
+func WithPromptHandlerMiddleware(
+	promptHandlerMiddleware PromptHandlerMiddleware,
+) ServerOption {
+	return func(s *MCPServer) {
+		s.middlewareMu.Lock()
+		s.promptMiddlewares = append(s.promptMiddlewares, promptHandlerMiddleware)
+		s.middlewareMu.Unlock()
+	}
+}
+
 // WithToolFilter adds a filter function that will be applied to tools before they are returned in list_tools
 func WithToolFilter(
 	toolFilter ToolFilterFunc,
@@ -227,22 +337,108 @@ func WithToolFilter(
 	}
 }
 
-// WithRecovery adds a middleware that recovers from panics in tool handlers.
+// WithResourceFilter adds a filter function that will be applied to resources before they are returned in resources/list
+func WithResourceFilter(
+	resourceFilter ResourceFilterFunc,
+) ServerOption {
+	return func(s *MCPServer) {
+		s.listFiltersMu.Lock()
+		s.resourceFilters = append(s.resourceFilters, resourceFilter)
+		s.listFiltersMu.Unlock()
+	}
+}
+
+// WithResourceTemplateFilter adds a filter function that will be applied to resource templates before they are
+// returned in resources/templates/list
+func WithResourceTemplateFilter(
+	templateFilter ResourceTemplateFilterFunc,
+) ServerOption {
+	return func(s *MCPServer) {
+		s.listFiltersMu.Lock()
+		s.templateFilters = append(s.templateFilters, templateFilter)
+		s.listFiltersMu.Unlock()
+	}
+}
+
+// WithPromptFilter adds a filter function that will be applied to prompts before they are returned in prompts/list
+func WithPromptFilter(
+	promptFilter PromptFilterFunc,
+) ServerOption {
+	return func(s *MCPServer) {
+		s.listFiltersMu.Lock()
+		s.promptFilters = append(s.promptFilters, promptFilter)
+		s.listFiltersMu.Unlock()
+	}
+}
+
+// WithFilterEnforcement applies the resource, resource template and prompt
+// filters to resources/read, resources/subscribe, prompts/get and
+// completion/complete as well, so that using a resource, template or prompt
+// hidden from the request's context fails as if it did not exist. Without it,
+// filters only affect the lists.
+func WithFilterEnforcement() ServerOption {
+	return func(s *MCPServer) {
+		s.enforceFilters = true
+	}
+}
+
+// WithToolArgumentValidation checks the arguments of tool calls against the
+// tool's input schema before invoking its handler. Missing properties with a
+// declared default are set to it, and calls with invalid arguments are
+// rejected with an INVALID_PARAMS error listing each violation.
+func WithToolArgumentValidation() ServerOption {
+	return func(s *MCPServer) {
+		s.validateToolArguments = true
+	}
+}
+
+// WithRecovery adds middlewares that recover from panics in tool, resource
+// and prompt handlers, turning them into errors.
 func WithRecovery() ServerOption {
-	return WithToolHandlerMiddleware(func(next ToolHandlerFunc) ToolHandlerFunc {
-		return func(ctx context.Context, request mcp.CallToolRequest) (result *mcp.CallToolResult, err error) {
-			defer func() {
-				if r := recover(); r != nil {
-					err = fmt.Errorf(
-						"panic recovered in %s tool handler: %v",
-						request.Params.Name,
-						r,
-					)
-				}
-			}()
-			return next(ctx, request)
-		}
-	})
+	return func(s *MCPServer) {
+		WithToolHandlerMiddleware(func(next ToolHandlerFunc) ToolHandlerFunc {
+			return func(ctx context.Context, request mcp.CallToolRequest) (result *mcp.CallToolResult, err error) {
+				defer func() {
+					if r := recover(); r != nil {
+						err = fmt.Errorf(
+							"panic recovered in %s tool handler: %v",
+							request.Params.Name,
+							r,
+						)
+					}
+				}()
+				return next(ctx, request)
+			}
+		})(s)
+		WithResourceHandlerMiddleware(func(next ResourceHandlerFunc) ResourceHandlerFunc {
+			return func(ctx context.Context, request mcp.ReadResourceRequest) (contents []mcp.ResourceContents, err error) {
+				defer func() {
+					if r := recover(); r != nil {
+						err = fmt.Errorf(
+							"panic recovered in %s resource handler: %v",
+							request.Params.URI,
+							r,
+						)
+					}
+				}()
+				return next(ctx, request)
+			}
+		})(s)
+		WithPromptHandlerMiddleware(func(next PromptHandlerFunc) PromptHandlerFunc {
+			return func(ctx context.Context, request mcp.GetPromptRequest) (result *mcp.GetPromptResult, err error) {
+				defer func() {
+					if r := recover(); r != nil {
+						err = fmt.Errorf(
+							"panic recovered in %s prompt handler: %v",
+							request.Params.Name,
+							r,
+						)
+					}
+				}()
+				return next(ctx, request)
+			}
+		})(s)
+	}
 }
 
 // WithHooks allows adding hooks that will be called before or after
@@ -294,14 +490,20 @@ func NewMCPServer(
 	opts ...ServerOption,
 ) *MCPServer {
 	s := &MCPServer{
-		resources:            make(map[string]resourceEntry),
-		resourceTemplates:    make(map[string]resourceTemplateEntry),
-		prompts:              make(map[string]mcp.Prompt),
-		promptHandlers:       make(map[string]PromptHandlerFunc),
-		tools:                make(map[string]ServerTool),
-		name:                 name,
-		version:              version,
-		notificationHandlers: make(map[string]NotificationHandlerFunc),
+		resources:             make(map[string]resourceEntry),
+		resourceTemplates:     make(map[string]resourceTemplateEntry),
+		resourceSubscriptions: make(map[string]map[string]struct{}),
+		prompts:               make(map[string]mcp.Prompt),
+		promptHandlers:        make(map[string]PromptHandlerFunc),
+		promptCompletions:     make(map[string]map[string]CompletionHandlerFunc),
+		inflightRequests:      make(map[string]map[string]*inflightRequest),
+		roots:                 make(map[string]rootsCacheEntry),
+		tools:                 make(map[string]ServerTool),
+		toolLimiters:          make(map[string]*toolLimiter),
+		name:                  name,
+		version:               version,
+		notificationHandlers:  make(map[string]NotificationHandlerFunc),
+		progressInterval:      DefaultProgressInterval,
 		capabilities: serverCapabilities{
 			tools:     nil,
 			resources: nil,
@@ -365,17 +567,58 @@ func (s *MCPServer) RemoveResource(uri string) {
 	}
 }
 
+// NotifyResourceUpdated sends a notifications/resources/updated notification
+// for the given URI to every session that subscribed to it through
+// resources/subscribe. Sessions that did not subscribe are not notified.
+func (s *MCPServer) NotifyResourceUpdated(uri string) {
+	s.subscriptionsMu.RLock()
+	var sessionIDs []string
+	for sessionID, uris := range s.resourceSubscriptions {
+		if _, ok := uris[uri]; ok {
+			sessionIDs = append(sessionIDs, sessionID)
+		}
+	}
+	s.subscriptionsMu.RUnlock()
+
+	for _, sessionID := range sessionIDs {
+		// Delivery failures for blocked channels are reported through the OnError hooks
+		_ = s.SendNotificationToSpecificClient(
+			sessionID,
+			mcp.MethodNotificationResourceUpdated,
+			map[string]any{"uri": uri},
+		)
+	}
+}
+
+// removeResourceSubscriptions drops all resource subscriptions held by a session
+func (s *MCPServer) removeResourceSubscriptions(sessionID string) {
+	s.subscriptionsMu.Lock()
+	delete(s.resourceSubscriptions, sessionID)
+	s.subscriptionsMu.Unlock()
+}
+
 // AddResourceTemplate registers a new resource template and its handler
 func (s *MCPServer) AddResourceTemplate(
 	template mcp.ResourceTemplate,
 	handler ResourceTemplateHandlerFunc,
 ) {
+	s.AddResourceTemplates(ServerResourceTemplate{Template: template, Handler: handler})
+}
+
+// AddResourceTemplates registers multiple resource templates at once
+func (s *MCPServer) AddResourceTemplates(templates ...ServerResourceTemplate) {
 	s.implicitlyRegisterResourceCapabilities()
 
 	s.resourcesMu.Lock()
-	s.resourceTemplates[template.URITemplate.Raw()] = resourceTemplateEntry{
-		template: template,
-		handler:  handler,
+	for _, entry := range templates {
+		if len(entry.Completions) > 0 {
+			s.implicitlyRegisterCompletionCapabilities()
+		}
+		s.resourceTemplates[entry.Template.URITemplate.Raw()] = resourceTemplateEntry{
+			template:    entry.Template,
+			handler:     entry.Handler,
+			completions: entry.Completions,
+		}
 	}
 	s.resourcesMu.Unlock()
 
@@ -394,6 +637,14 @@ func (s *MCPServer) AddPrompts(prompts ...ServerPrompt) {
 	for _, entry := range prompts {
 		s.prompts[entry.Prompt.Name] = entry.Prompt
 		s.promptHandlers[entry.Prompt.Name] = entry.Handler
+		if len(entry.Completions) > 0 {
+			s.promptCompletions[entry.Prompt.Name] = entry.Completions
+		} else {
+			delete(s.promptCompletions, entry.Prompt.Name)
+		}
+		if len(entry.Completions) > 0 || hasEnumArguments(entry.Prompt) {
+			s.implicitlyRegisterCompletionCapabilities()
+		}
 	}
 	s.promptsMu.Unlock()
 
@@ -417,6 +668,7 @@ func (s *MCPServer) DeletePrompts(names ...string) {
 		if _, ok := s.prompts[name]; ok {
 			delete(s.prompts, name)
 			delete(s.promptHandlers, name)
+			delete(s.promptCompletions, name)
 			exists = true
 		}
 	}
@@ -434,6 +686,15 @@ func (s *MCPServer) AddTool(tool mcp.Tool, handler ToolHandlerFunc) {
 	s.AddTools(ServerTool{Tool: tool, Handler: handler})
 }
 
+// AddTypedTool registers a tool whose handler receives the call's arguments
+// bound to T. Paired with a tool created by mcp.NewTypedTool[T], the struct
+// type is the single definition of both the input schema and the arguments:
+//
+//	server.AddTypedTool(s, mcp.NewTypedTool[SearchArgs]("search"), handleSearch)
+func AddTypedTool[T any](s *MCPServer, tool mcp.Tool, handler mcp.TypedToolHandlerFunc[T]) {
+	s.AddTool(tool, mcp.NewTypedToolHandler(handler))
+}
+
 // Register tool capabilities due to a tool being added.  Default to
 // listChanged: true, but don't change the value if we've already explicitly
 // registered tools.listChanged false.
@@ -458,6 +719,13 @@ func (s *MCPServer) implicitlyRegisterPromptCapabilities() {
 	)
 }
 
+func (s *MCPServer) implicitlyRegisterCompletionCapabilities() {
+	s.implicitlyRegisterCapabilities(
+		func() bool { return s.capabilities.completions != nil },
+		func() { s.capabilities.completions = mcp.ToBoolPtr(true) },
+	)
+}
+
 func (s *MCPServer) implicitlyRegisterCapabilities(check func() bool, register func()) {
 	s.capabilitiesMu.RLock()
 	if check() {
@@ -479,6 +747,8 @@ func (s *MCPServer) AddTools(tools ...ServerTool) {
 
 	s.toolsMu.Lock()
 	for _, entry := range tools {
+		entry.schemas = &toolSchemas{}
+		entry.limiter = newToolLimiter(s.toolMaxConcurrencyOf(entry))
 		s.tools[entry.Tool.Name] = entry
 	}
 	s.toolsMu.Unlock()
@@ -567,6 +837,10 @@ func (s *MCPServer) handleInitialize(
 		capabilities.Logging = &struct{}{}
 	}
 
+	if s.capabilities.completions != nil && *s.capabilities.completions {
+		capabilities.Completions = &struct{}{}
+	}
+
 	result := mcp.InitializeResult{
 		ProtocolVersion: s.protocolVersion(request.Params.ProtocolVersion),
 		ServerInfo: mcp.Implementation{
@@ -584,6 +858,11 @@ func (s *MCPServer) handleInitialize(
 		if sessionWithClientInfo, ok := session.(SessionWithClientInfo); ok {
 			sessionWithClientInfo.SetClientInfo(request.Params.ClientInfo)
 		}
+
+		// Store the negotiated protocol version if the session supports it
+		if sessionWithProtocolVersion, ok := session.(SessionWithProtocolVersion); ok {
+			sessionWithProtocolVersion.SetProtocolVersion(result.ProtocolVersion)
+		}
 	}
 	return &result, nil
 }
@@ -688,12 +967,24 @@ func (s *MCPServer) handleListResources(
 	id any,
 	request mcp.ListResourcesRequest,
 ) (*mcp.ListResourcesResult, *requestError) {
+	sessionResources, _ := sessionResourcesFromContext(ctx)
+
 	s.resourcesMu.RLock()
-	resources := make([]mcp.Resource, 0, len(s.resources))
-	for _, entry := range s.resources {
-		resources = append(resources, entry.resource)
+	resources := make([]mcp.Resource, 0, len(s.resources)+len(sessionResources))
+	for uri, entry := range s.resources {
+		// Session-specific resources override global ones
+		if _, ok := sessionResources[uri]; !ok {
+			resources = append(resources, entry.resource)
+		}
 	}
 	s.resourcesMu.RUnlock()
+	for _, entry := range sessionResources {
+		resources = append(resources, entry.Resource)
+	}
+
+	s.listFiltersMu.RLock()
+	resources = applyListFilters(ctx, s.resourceFilters, resources)
+	s.listFiltersMu.RUnlock()
 
 	// Sort the resources by name
 	sort.Slice(resources, func(i, j int) bool {
@@ -726,12 +1017,24 @@ func (s *MCPServer) handleListResourceTemplates(
 	id any,
 	request mcp.ListResourceTemplatesRequest,
 ) (*mcp.ListResourceTemplatesResult, *requestError) {
+	_, sessionTemplates := sessionResourcesFromContext(ctx)
+
 	s.resourcesMu.RLock()
-	templates := make([]mcp.ResourceTemplate, 0, len(s.resourceTemplates))
-	for _, entry := range s.resourceTemplates {
-		templates = append(templates, entry.template)
+	templates := make([]mcp.ResourceTemplate, 0, len(s.resourceTemplates)+len(sessionTemplates))
+	for uriTemplate, entry := range s.resourceTemplates {
+		// Session-specific templates override global ones
+		if _, ok := sessionTemplates[uriTemplate]; !ok {
+			templates = append(templates, entry.template)
+		}
 	}
 	s.resourcesMu.RUnlock()
+	for _, entry := range sessionTemplates {
+		templates = append(templates, entry.Template)
+	}
+
+	s.listFiltersMu.RLock()
+	templates = applyListFilters(ctx, s.templateFilters, templates)
+	s.listFiltersMu.RUnlock()
 	sort.Slice(templates, func(i, j int) bool {
 		return templates[i].Name < templates[j].Name
 	})
@@ -762,12 +1065,25 @@ func (s *MCPServer) handleReadResource(
 	id any,
 	request mcp.ReadResourceRequest,
 ) (*mcp.ReadResourceResult, *requestError) {
-	s.resourcesMu.RLock()
-	// First try direct resource handlers
-	if entry, ok := s.resources[request.Params.URI]; ok {
-		handler := entry.handler
+	sessionResources, sessionTemplates := sessionResourcesFromContext(ctx)
+
+	// First try direct resource handlers, session-specific ones first
+	var resource mcp.Resource
+	var handler ResourceHandlerFunc
+	if entry, ok := sessionResources[request.Params.URI]; ok {
+		resource, handler = entry.Resource, entry.Handler
+	} else {
+		s.resourcesMu.RLock()
+		if entry, ok := s.resources[request.Params.URI]; ok {
+			resource, handler = entry.resource, entry.handler
+		}
 		s.resourcesMu.RUnlock()
-		contents, err := handler(ctx, request)
+	}
+	if handler != nil {
+		if !s.resourceVisible(ctx, resource) {
+			return nil, resourceNotFoundError(id, request.Params.URI)
+		}
+		contents, err := s.wrapResourceHandler(handler)(ctx, request)
 		if err != nil {
 			return nil, &requestError{
 				id:   id,
@@ -779,26 +1095,41 @@ func (s *MCPServer) handleReadResource(
 	}
 
 	// If no direct handler found, try matching against templates
+	var matchedTemplate mcp.ResourceTemplate
 	var matchedHandler ResourceTemplateHandlerFunc
 	var matched bool
-	for _, entry := range s.resourceTemplates {
-		template := entry.template
-		if matchesTemplate(request.Params.URI, template.URITemplate) {
-			matchedHandler = entry.handler
-			matched = true
-			matchedVars := template.URITemplate.Match(request.Params.URI)
-			// Convert matched variables to a map
-			request.Params.Arguments = make(map[string]any, len(matchedVars))
-			for name, value := range matchedVars {
-				request.Params.Arguments[name] = value.V
-			}
+	matchTemplate := func(template mcp.ResourceTemplate, handler ResourceTemplateHandlerFunc) bool {
+		if !matchesTemplate(request.Params.URI, template.URITemplate) {
+			return false
+		}
+		matchedTemplate = template
+		matchedHandler = handler
+		matched = true
+		matchedVars := template.URITemplate.Match(request.Params.URI)
+		// Convert matched variables to a map
+		request.Params.Arguments = make(map[string]any, len(matchedVars))
+		for name, value := range matchedVars {
+			request.Params.Arguments[name] = value.V
+		}
+		return true
+	}
+	for _, entry := range sessionTemplates {
+		if matchTemplate(entry.Template, entry.Handler) {
 			break
 		}
 	}
-	s.resourcesMu.RUnlock()
+	if !matched {
+		s.resourcesMu.RLock()
+		for _, entry := range s.resourceTemplates {
+			if matchTemplate(entry.template, entry.handler) {
+				break
+			}
+		}
+		s.resourcesMu.RUnlock()
+	}
 
-	if matched {
-		contents, err := matchedHandler(ctx, request)
+	if matched && s.resourceTemplateVisible(ctx, matchedTemplate) {
+		contents, err := s.wrapResourceHandler(ResourceHandlerFunc(matchedHandler))(ctx, request)
 		if err != nil {
 			return nil, &requestError{
 				id:   id,
@@ -809,17 +1140,214 @@ func (s *MCPServer) handleReadResource(
 		return &mcp.ReadResourceResult{Contents: contents}, nil
 	}
 
-	return nil, &requestError{
+	return nil, resourceNotFoundError(id, request.Params.URI)
+}
+
+func resourceNotFoundError(id any, uri string) *requestError {
+	return &requestError{
 		id:   id,
 		code: mcp.RESOURCE_NOT_FOUND,
 		err: fmt.Errorf(
 			"handler not found for resource URI '%s': %w",
-			request.Params.URI,
+			uri,
 			ErrResourceNotFound,
 		),
 	}
 }
 
+// resourceVisible reports whether the resource filters keep the resource in
+// ctx. It always does unless WithFilterEnforcement is set.
+func (s *MCPServer) resourceVisible(ctx context.Context, resource mcp.Resource) bool {
+	if !s.enforceFilters {
+		return true
+	}
+	s.listFiltersMu.RLock()
+	defer s.listFiltersMu.RUnlock()
+	return slices.ContainsFunc(applyListFilters(ctx, s.resourceFilters, []mcp.Resource{resource}), func(r mcp.Resource) bool {
+		return r.URI == resource.URI
+	})
+}
+
+// resourceTemplateVisible reports whether the resource template filters keep
+// the template in ctx. It always does unless WithFilterEnforcement is set.
+func (s *MCPServer) resourceTemplateVisible(ctx context.Context, template mcp.ResourceTemplate) bool {
+	if !s.enforceFilters {
+		return true
+	}
+	s.listFiltersMu.RLock()
+	defer s.listFiltersMu.RUnlock()
+	return slices.ContainsFunc(applyListFilters(ctx, s.templateFilters, []mcp.ResourceTemplate{template}), func(t mcp.ResourceTemplate) bool {
+		return t.URITemplate.Raw() == template.URITemplate.Raw()
+	})
+}
+
+// promptVisible reports whether the prompt filters keep the prompt in ctx.
+// It always does unless WithFilterEnforcement is set.
+func (s *MCPServer) promptVisible(ctx context.Context, prompt mcp.Prompt) bool {
+	if !s.enforceFilters {
+		return true
+	}
+	s.listFiltersMu.RLock()
+	defer s.listFiltersMu.RUnlock()
+	return slices.ContainsFunc(applyListFilters(ctx, s.promptFilters, []mcp.Prompt{prompt}), func(p mcp.Prompt) bool {
+		return p.Name == prompt.Name
+	})
+}
+
+// applyListFilters applies the filters, in order, to the items of a list.
+func applyListFilters[T any, F ~func(context.Context, []T) []T](ctx context.Context, filters []F, items []T) []T {
+	for _, filter := range filters {
+		items = filter(ctx, items)
+	}
+	return items
+}
+
+// wrapResourceHandler applies the resource handler middlewares to handler.
+func (s *MCPServer) wrapResourceHandler(handler ResourceHandlerFunc) ResourceHandlerFunc {
+	s.middlewareMu.RLock()
+	mw := s.resourceMiddlewares
+	s.middlewareMu.RUnlock()
+
+	// Apply middlewares in reverse order
+	for i := len(mw) - 1; i >= 0; i-- {
+		handler = mw[i](handler)
+	}
+	return s.traceResourceHandler(handler)
+}
+
+func (s *MCPServer) handleSubscribe(
+	ctx context.Context,
+	id any,
+	request mcp.SubscribeRequest,
+) (*mcp.EmptyResult, *requestError) {
+	if !s.capabilities.resources.subscribe {
+		return nil, &requestError{
+			id:   id,
+			code: mcp.METHOD_NOT_FOUND,
+			err:  fmt.Errorf("resource subscriptions %w", ErrUnsupported),
+		}
+	}
+
+	session := ClientSessionFromContext(ctx)
+	if session == nil || !session.Initialized() {
+		return nil, &requestError{
+			id:   id,
+			code: mcp.INTERNAL_ERROR,
+			err:  ErrSessionNotInitialized,
+		}
+	}
+
+	if !s.resourceExists(ctx, request.Params.URI) {
+		return nil, &requestError{
+			id:   id,
+			code: mcp.RESOURCE_NOT_FOUND,
+			err: fmt.Errorf(
+				"cannot subscribe to resource URI '%s': %w",
+				request.Params.URI,
+				ErrResourceNotFound,
+			),
+		}
+	}
+
+	// Subscriptions are tracked per session, so sessions without an ID (e.g.
+	// stateless streamable HTTP) can't subscribe
+	if session.SessionID() == "" {
+		return nil, &requestError{
+			id:   id,
+			code: mcp.INVALID_REQUEST,
+			err:  fmt.Errorf("resource subscriptions: %w", ErrSessionIDRequired),
+		}
+	}
+	// Nor can sessions whose end the server is not told about, e.g.
+	// streamable HTTP sessions initialized by another server instance
+	if !sessionKeepsState(session) {
+		return nil, &requestError{
+			id:   id,
+			code: mcp.INVALID_REQUEST,
+			err:  fmt.Errorf("resource subscriptions: %w", ErrSessionNotFound),
+		}
+	}
+
+	s.subscriptionsMu.Lock()
+	uris, ok := s.resourceSubscriptions[session.SessionID()]
+	if !ok {
+		uris = make(map[string]struct{})
+		s.resourceSubscriptions[session.SessionID()] = uris
+	}
+	uris[request.Params.URI] = struct{}{}
+	s.subscriptionsMu.Unlock()
+
+	return &mcp.EmptyResult{}, nil
+}
+
+func (s *MCPServer) handleUnsubscribe(
+	ctx context.Context,
+	id any,
+	request mcp.UnsubscribeRequest,
+) (*mcp.EmptyResult, *requestError) {
+	if !s.capabilities.resources.subscribe {
+		return nil, &requestError{
+			id:   id,
+			code: mcp.METHOD_NOT_FOUND,
+			err:  fmt.Errorf("resource subscriptions %w", ErrUnsupported),
+		}
+	}
+
+	session := ClientSessionFromContext(ctx)
+	if session == nil || !session.Initialized() {
+		return nil, &requestError{
+			id:   id,
+			code: mcp.INTERNAL_ERROR,
+			err:  ErrSessionNotInitialized,
+		}
+	}
+
+	s.subscriptionsMu.Lock()
+	if uris, ok := s.resourceSubscriptions[session.SessionID()]; ok {
+		delete(uris, request.Params.URI)
+		if len(uris) == 0 {
+			delete(s.resourceSubscriptions, session.SessionID())
+		}
+	}
+	s.subscriptionsMu.Unlock()
+
+	return &mcp.EmptyResult{}, nil
+}
+
+// resourceExists reports whether the URI names a registered resource or
+// matches one of the registered resource templates, including those of the
+// session in ctx. Like for resources/read, resources and templates hidden by
+// enforced filters don't exist.
+func (s *MCPServer) resourceExists(ctx context.Context, uri string) bool {
+	sessionResources, sessionTemplates := sessionResourcesFromContext(ctx)
+	if entry, ok := sessionResources[uri]; ok {
+		return s.resourceVisible(ctx, entry.Resource)
+	}
+	s.resourcesMu.RLock()
+	entry, ok := s.resources[uri]
+	s.resourcesMu.RUnlock()
+	if ok {
+		return s.resourceVisible(ctx, entry.resource)
+	}
+
+	for _, entry := range sessionTemplates {
+		if matchesTemplate(uri, entry.Template.URITemplate) {
+			return s.resourceTemplateVisible(ctx, entry.Template)
+		}
+	}
+	var template mcp.ResourceTemplate
+	var matched bool
+	s.resourcesMu.RLock()
+	for _, entry := range s.resourceTemplates {
+		if matchesTemplate(uri, entry.template.URITemplate) {
+			template, matched = entry.template, true
+			break
+		}
+	}
+	s.resourcesMu.RUnlock()
+	return matched && s.resourceTemplateVisible(ctx, template)
+}
+
 // matchesTemplate checks if a URI matches a URI template pattern
 func matchesTemplate(uri string, template *mcp.URITemplate) bool {
 	return template.Regexp().MatchString(uri)
@@ -830,12 +1358,24 @@ func (s *MCPServer) handleListPrompts(
 	id any,
 	request mcp.ListPromptsRequest,
 ) (*mcp.ListPromptsResult, *requestError) {
+	sessionPrompts := sessionPromptsFromContext(ctx)
+
 	s.promptsMu.RLock()
-	prompts := make([]mcp.Prompt, 0, len(s.prompts))
-	for _, prompt := range s.prompts {
-		prompts = append(prompts, prompt)
+	prompts := make([]mcp.Prompt, 0, len(s.prompts)+len(sessionPrompts))
+	for name, prompt := range s.prompts {
+		// Session-specific prompts override global ones
+		if _, ok := sessionPrompts[name]; !ok {
+			prompts = append(prompts, prompt)
+		}
 	}
 	s.promptsMu.RUnlock()
+	for _, entry := range sessionPrompts {
+		prompts = append(prompts, entry.Prompt)
+	}
+
+	s.listFiltersMu.RLock()
+	prompts = applyListFilters(ctx, s.promptFilters, prompts)
+	s.listFiltersMu.RUnlock()
 
 	// sort prompts by name
 	sort.Slice(prompts, func(i, j int) bool {
@@ -868,11 +1408,17 @@ func (s *MCPServer) handleGetPrompt(
 	id any,
 	request mcp.GetPromptRequest,
 ) (*mcp.GetPromptResult, *requestError) {
-	s.promptsMu.RLock()
-	handler, ok := s.promptHandlers[request.Params.Name]
-	s.promptsMu.RUnlock()
-
+	// Session-specific prompts take precedence over global ones
+	sessionPrompt, ok := sessionPromptsFromContext(ctx)[request.Params.Name]
+	prompt, handler := sessionPrompt.Prompt, sessionPrompt.Handler
 	if !ok {
+		s.promptsMu.RLock()
+		prompt = s.prompts[request.Params.Name]
+		handler, ok = s.promptHandlers[request.Params.Name]
+		s.promptsMu.RUnlock()
+	}
+
+	if !ok || !s.promptVisible(ctx, prompt) {
 		return nil, &requestError{
 			id:   id,
 			code: mcp.INVALID_PARAMS,
@@ -880,6 +1426,16 @@ func (s *MCPServer) handleGetPrompt(
 		}
 	}
 
+	s.middlewareMu.RLock()
+	mw := s.promptMiddlewares
+	s.middlewareMu.RUnlock()
+
+	// Apply middlewares in reverse order
+	for i := len(mw) - 1; i >= 0; i-- {
+		handler = mw[i](handler)
+	}
+	handler = s.tracePromptHandler(handler)
+
 	result, err := handler(ctx, request)
 	if err != nil {
 		return nil, &requestError{
@@ -987,30 +1543,7 @@ func (s *MCPServer) handleToolCall(
 	id any,
 	request mcp.CallToolRequest,
 ) (*mcp.CallToolResult, *requestError) {
-	// First check session-specific tools
-	var tool ServerTool
-	var ok bool
-
-	session := ClientSessionFromContext(ctx)
-	if session != nil {
-		if sessionWithTools, typeAssertOk := session.(SessionWithTools); typeAssertOk {
-			if sessionTools := sessionWithTools.GetSessionTools(); sessionTools != nil {
-				var sessionOk bool
-				tool, sessionOk = sessionTools[request.Params.Name]
-				if sessionOk {
-					ok = true
-				}
-			}
-		}
-	}
-
-	// If not found in session tools, check global tools
-	if !ok {
-		s.toolsMu.RLock()
-		tool, ok = s.tools[request.Params.Name]
-		s.toolsMu.RUnlock()
-	}
-
+	tool, ok := s.lookupTool(ctx, request.Params.Name)
 	if !ok {
 		return nil, &requestError{
 			id:   id,
@@ -1018,6 +1551,24 @@ func (s *MCPServer) handleToolCall(
 			err:  fmt.Errorf("tool '%s' not found: %w", request.Params.Name, ErrToolNotFound),
 		}
 	}
+	setMetricsTool(ctx, request.Params.Name)
+
+	if s.validateToolArguments {
+		arguments, err := validateToolArguments(tool, request.Params.Arguments)
+		if err != nil {
+			reqErr := &requestError{
+				id:   id,
+				code: mcp.INVALID_PARAMS,
+				err:  err,
+			}
+			var argsErr *ToolArgumentsError
+			if errors.As(err, &argsErr) {
+				reqErr.data = map[string]any{"violations": argsErr.Violations}
+			}
+			return nil, reqErr
+		}
+		request.Params.Arguments = arguments
+	}
 
 	finalHandler := tool.Handler
 
@@ -1029,8 +1580,22 @@ func (s *MCPServer) handleToolCall(
 	for i := len(mw) - 1; i >= 0; i-- {
 		finalHandler = mw[i](finalHandler)
 	}
+	finalHandler = s.traceToolHandler(finalHandler)
+
+	release, err := s.acquireToolSlot(ctx, tool)
+	if err != nil {
+		code := mcp.INTERNAL_ERROR
+		if errors.Is(err, ErrToolConcurrencyLimit) {
+			code = mcp.SERVER_BUSY
+		}
+		return nil, &requestError{
+			id:   id,
+			code: code,
+			err:  err,
+		}
+	}
 
-	result, err := finalHandler(ctx, request)
+	result, err := s.callToolWithTimeout(ctx, id, tool, finalHandler, request, release)
 	if err != nil {
 		return nil, &requestError{
 			id:   id,
@@ -1039,13 +1604,97 @@ func (s *MCPServer) handleToolCall(
 		}
 	}
 
+	if err := validateStructuredContent(tool, result); err != nil {
+		return nil, &requestError{
+			id:   id,
+			code: mcp.INTERNAL_ERROR,
+			err:  err,
+		}
+	}
+
 	return result, nil
 }
 
+// lookupTool returns the tool of the given name, among the tools of the
+// session in ctx first, then among the global tools.
+func (s *MCPServer) lookupTool(ctx context.Context, name string) (ServerTool, bool) {
+	if session, ok := ClientSessionFromContext(ctx).(SessionWithTools); ok {
+		if tool, ok := session.GetSessionTools()[name]; ok {
+			return tool, true
+		}
+	}
+
+	s.toolsMu.RLock()
+	defer s.toolsMu.RUnlock()
+	tool, ok := s.tools[name]
+	return tool, ok
+}
+
+// validateToolArguments checks the arguments of a call against the tool's
+// input schema and returns them with the declared defaults applied.
+func validateToolArguments(serverTool ServerTool, arguments any) (any, error) {
+	tool := serverTool.Tool
+	schema, err := serverTool.schemas.inputSchema(tool)
+	if err != nil {
+		return nil, fmt.Errorf("tool '%s' has an invalid input schema: %w", tool.Name, err)
+	}
+
+	// Omitted arguments are an empty object
+	if arguments == nil {
+		arguments = map[string]any{}
+	}
+	args, err := normalizeJSON(arguments)
+	if err != nil {
+		return nil, fmt.Errorf("arguments of tool '%s' cannot be encoded: %w", tool.Name, err)
+	}
+
+	applySchemaDefaults(schema.schema, args)
+	if violations := schema.validate(args); len(violations) > 0 {
+		return nil, &ToolArgumentsError{Tool: tool.Name, Violations: violations}
+	}
+	return args, nil
+}
+
+// validateStructuredContent checks that a successful result of a tool which
+// declares an output schema carries structured content matching that schema.
+func validateStructuredContent(serverTool ServerTool, result *mcp.CallToolResult) error {
+	tool := serverTool.Tool
+	if !tool.HasOutputSchema() || result == nil || result.IsError {
+		return nil
+	}
+
+	if result.StructuredContent == nil {
+		return fmt.Errorf("tool '%s' declares an output schema but returned no structured content: %w", tool.Name, ErrInvalidToolOutput)
+	}
+
+	schema, err := serverTool.schemas.outputSchema(tool)
+	if err != nil {
+		return fmt.Errorf("tool '%s' has an invalid output schema: %w", tool.Name, err)
+	}
+
+	content, err := normalizeJSON(result.StructuredContent)
+	if err != nil {
+		return fmt.Errorf("tool '%s' returned structured content that cannot be encoded: %w", tool.Name, err)
+	}
+
+	if violations := schema.validate(content); len(violations) > 0 {
+		return fmt.Errorf("tool '%s' returned structured content not matching its output schema (%s): %w",
+			tool.Name, strings.Join(violationStrings(violations), "; "), ErrInvalidToolOutput)
+	}
+	return nil
+}
+
 func (s *MCPServer) handleNotification(
 	ctx context.Context,
 	notification mcp.JSONRPCNotification,
 ) mcp.JSONRPCMessage {
+	switch notification.Method {
+	case mcp.MethodNotificationCancelled:
+		s.handleCancelledNotification(ctx, notification)
+	case mcp.MethodNotificationRootsListChanged:
+		s.handleRootsListChanged(ctx)
+	}
+
 	s.notificationHandlersMu.RLock()
 	handler, ok := s.notificationHandlers[notification.Method]
 	s.notificationHandlersMu.RUnlock()
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"strings"
 	"testing"
 	"time"
 
@@ -1829,6 +1830,533 @@ func TestMCPServer_WithRecover(t *testing.T) {
 	assert.Nil(t, errorResponse.Error.Data)
 }
 
+func TestMCPServer_WithRecoverResourcesAndPrompts(t *testing.T) {
+	server := NewMCPServer(
+		"test-server",
+		"1.0.0",
+		WithRecovery(),
+	)
+
+	server.AddResource(
+		mcp.NewResource("test://panic", "panic"),
+		func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			panic("test panic")
+		},
+	)
+	server.AddResourceTemplate(
+		mcp.NewResourceTemplate("test://panic/{id}", "panic-template"),
+		func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			panic("test panic")
+		},
+	)
+	server.AddPrompt(
+		mcp.NewPrompt("panic-prompt"),
+		func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			panic("test panic")
+		},
+	)
+
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{
+			name:    "resource",
+			message: `{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"test://panic"}}`,
+			want:    "panic recovered in test://panic resource handler: test panic",
+		},
+		{
+			name:    "resource template",
+			message: `{"jsonrpc":"2.0","id":2,"method":"resources/read","params":{"uri":"test://panic/1"}}`,
+			want:    "panic recovered in test://panic/1 resource handler: test panic",
+		},
+		{
+			name:    "prompt",
+			message: `{"jsonrpc":"2.0","id":3,"method":"prompts/get","params":{"name":"panic-prompt"}}`,
+			want:    "panic recovered in panic-prompt prompt handler: test panic",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := server.HandleMessage(context.Background(), []byte(tt.message))
+			errorResponse, ok := response.(mcp.JSONRPCError)
+			require.True(t, ok)
+			assert.Equal(t, mcp.INTERNAL_ERROR, errorResponse.Error.Code)
+			assert.Equal(t, tt.want, errorResponse.Error.Message)
+		})
+	}
+}
+
+func TestMCPServer_ResourceAndPromptHandlerMiddleware(t *testing.T) {
+	var calls []string
+	server := NewMCPServer(
+		"test-server",
+		"1.0.0",
+		WithResourceHandlerMiddleware(func(next ResourceHandlerFunc) ResourceHandlerFunc {
+			return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+				calls = append(calls, "resource outer")
+				return next(ctx, request)
+			}
+		}),
+		WithResourceHandlerMiddleware(func(next ResourceHandlerFunc) ResourceHandlerFunc {
+			return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+				calls = append(calls, "resource inner")
+				return next(ctx, request)
+			}
+		}),
+		WithPromptHandlerMiddleware(func(next PromptHandlerFunc) PromptHandlerFunc {
+			return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+				calls = append(calls, "prompt")
+				return next(ctx, request)
+			}
+		}),
+	)
+
+	server.AddResourceTemplate(
+		mcp.NewResourceTemplate("test://items/{id}", "items"),
+		func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			calls = append(calls, "resource handler")
+			return []mcp.ResourceContents{mcp.TextResourceContents{URI: request.Params.URI, Text: "item"}}, nil
+		},
+	)
+	server.AddPrompt(
+		mcp.NewPrompt("greeting"),
+		func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			calls = append(calls, "prompt handler")
+			return mcp.NewGetPromptResult("greeting", nil), nil
+		},
+	)
+
+	response := server.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"test://items/1"}}`))
+	_, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok)
+	response = server.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":2,"method":"prompts/get","params":{"name":"greeting"}}`))
+	_, ok = response.(mcp.JSONRPCResponse)
+	require.True(t, ok)
+
+	assert.Equal(t, []string{"resource outer", "resource inner", "resource handler", "prompt", "prompt handler"}, calls)
+}
+
+func TestMCPServer_ResourceAndPromptFilters(t *testing.T) {
+	type adminKey struct{}
+	isAdmin := func(ctx context.Context) bool {
+		admin, _ := ctx.Value(adminKey{}).(bool)
+		return admin
+	}
+	newServer := func(opts ...ServerOption) *MCPServer {
+		opts = append(opts,
+			WithPaginationLimit(1),
+			WithResourceFilter(func(ctx context.Context, resources []mcp.Resource) []mcp.Resource {
+				if isAdmin(ctx) {
+					return resources
+				}
+				var visible []mcp.Resource
+				for _, resource := range resources {
+					if !strings.HasPrefix(resource.URI, "admin://") {
+						visible = append(visible, resource)
+					}
+				}
+				return visible
+			}),
+			WithResourceTemplateFilter(func(ctx context.Context, templates []mcp.ResourceTemplate) []mcp.ResourceTemplate {
+				if isAdmin(ctx) {
+					return templates
+				}
+				return nil
+			}),
+			WithPromptFilter(func(ctx context.Context, prompts []mcp.Prompt) []mcp.Prompt {
+				if isAdmin(ctx) {
+					return prompts
+				}
+				var visible []mcp.Prompt
+				for _, prompt := range prompts {
+					if prompt.Name != "admin" {
+						visible = append(visible, prompt)
+					}
+				}
+				return visible
+			}),
+		)
+		server := NewMCPServer("test-server", "1.0.0", opts...)
+		resourceHandler := func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return []mcp.ResourceContents{mcp.TextResourceContents{URI: request.Params.URI, Text: "contents"}}, nil
+		}
+		server.AddResource(mcp.NewResource("admin://secrets", "a-secrets"), resourceHandler)
+		server.AddResource(mcp.NewResource("test://public", "b-public"), resourceHandler)
+		server.AddResourceTemplate(mcp.NewResourceTemplate("admin://users/{id}", "users"), ResourceTemplateHandlerFunc(resourceHandler))
+		promptHandler := func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			return mcp.NewGetPromptResult(request.Params.Name, nil), nil
+		}
+		server.AddPrompt(mcp.NewPrompt("admin"), promptHandler)
+		server.AddPrompt(mcp.NewPrompt("public"), promptHandler)
+		return server
+	}
+	userCtx := context.Background()
+	adminCtx := context.WithValue(context.Background(), adminKey{}, true)
+
+	t.Run("lists are filtered before pagination", func(t *testing.T) {
+		server := newServer()
+
+		response := server.HandleMessage(userCtx, []byte(`{"jsonrpc":"2.0","id":1,"method":"resources/list"}`))
+		resp, ok := response.(mcp.JSONRPCResponse)
+		require.True(t, ok)
+		result := resp.Result.(mcp.ListResourcesResult)
+		require.Len(t, result.Resources, 1)
+		assert.Equal(t, "test://public", result.Resources[0].URI)
+
+		response = server.HandleMessage(adminCtx, []byte(`{"jsonrpc":"2.0","id":2,"method":"resources/list"}`))
+		resp, ok = response.(mcp.JSONRPCResponse)
+		require.True(t, ok)
+		result = resp.Result.(mcp.ListResourcesResult)
+		require.Len(t, result.Resources, 1)
+		assert.Equal(t, "admin://secrets", result.Resources[0].URI)
+
+		response = server.HandleMessage(userCtx, []byte(`{"jsonrpc":"2.0","id":3,"method":"resources/templates/list"}`))
+		resp, ok = response.(mcp.JSONRPCResponse)
+		require.True(t, ok)
+		assert.Empty(t, resp.Result.(mcp.ListResourceTemplatesResult).ResourceTemplates)
+
+		response = server.HandleMessage(userCtx, []byte(`{"jsonrpc":"2.0","id":4,"method":"prompts/list"}`))
+		resp, ok = response.(mcp.JSONRPCResponse)
+		require.True(t, ok)
+		prompts := resp.Result.(mcp.ListPromptsResult).Prompts
+		require.Len(t, prompts, 1)
+		assert.Equal(t, "public", prompts[0].Name)
+	})
+
+	t.Run("reads are not filtered by default", func(t *testing.T) {
+		server := newServer()
+
+		response := server.HandleMessage(userCtx, []byte(`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"admin://secrets"}}`))
+		_, ok := response.(mcp.JSONRPCResponse)
+		assert.True(t, ok)
+	})
+
+	t.Run("enforcement hides filtered items", func(t *testing.T) {
+		server := newServer(WithFilterEnforcement())
+
+		for _, uri := range []string{"admin://secrets", "admin://users/1"} {
+			response := server.HandleMessage(userCtx, []byte(`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"`+uri+`"}}`))
+			errResp, ok := response.(mcp.JSONRPCError)
+			require.True(t, ok, uri)
+			assert.Equal(t, mcp.RESOURCE_NOT_FOUND, errResp.Error.Code, uri)
+
+			response = server.HandleMessage(adminCtx, []byte(`{"jsonrpc":"2.0","id":2,"method":"resources/read","params":{"uri":"`+uri+`"}}`))
+			_, ok = response.(mcp.JSONRPCResponse)
+			assert.True(t, ok, uri)
+		}
+
+		response := server.HandleMessage(userCtx, []byte(`{"jsonrpc":"2.0","id":3,"method":"prompts/get","params":{"name":"admin"}}`))
+		errResp, ok := response.(mcp.JSONRPCError)
+		require.True(t, ok)
+		assert.Equal(t, mcp.INVALID_PARAMS, errResp.Error.Code)
+		assert.Contains(t, errResp.Error.Message, "not found")
+
+		response = server.HandleMessage(userCtx, []byte(`{"jsonrpc":"2.0","id":4,"method":"prompts/get","params":{"name":"public"}}`))
+		_, ok = response.(mcp.JSONRPCResponse)
+		assert.True(t, ok)
+	})
+
+	t.Run("enforcement hides filtered items from subscriptions", func(t *testing.T) {
+		server := newServer(WithFilterEnforcement(), WithResourceCapabilities(true, false))
+		session := &fakeSession{sessionID: "filtered", notificationChannel: make(chan mcp.JSONRPCNotification, 10), initialized: true}
+		subscribe := func(ctx context.Context, uri string) mcp.JSONRPCMessage {
+			return server.HandleMessage(server.WithContext(ctx, session), []byte(`{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{"uri":"`+uri+`"}}`))
+		}
+
+		for _, uri := range []string{"admin://secrets", "admin://users/1", "test://unknown"} {
+			errResp, ok := subscribe(userCtx, uri).(mcp.JSONRPCError)
+			require.True(t, ok, uri)
+			assert.Equal(t, mcp.RESOURCE_NOT_FOUND, errResp.Error.Code, uri)
+		}
+		for _, uri := range []string{"admin://secrets", "admin://users/1"} {
+			_, ok := subscribe(adminCtx, uri).(mcp.JSONRPCResponse)
+			assert.True(t, ok, uri)
+		}
+	})
+
+	t.Run("enforcement hides filtered items from completions", func(t *testing.T) {
+		server := newServer(WithFilterEnforcement())
+		server.AddPrompt(mcp.NewPrompt("admin", mcp.WithArgument("level", mcp.ArgumentEnum("low", "high"))), func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			return mcp.NewGetPromptResult(request.Params.Name, nil), nil
+		})
+		server.AddResourceTemplates(ServerResourceTemplate{
+			Template: mcp.NewResourceTemplate("admin://teams/{id}", "teams"),
+			Handler: func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+				return nil, nil
+			},
+			Completions: map[string]CompletionHandlerFunc{
+				"id": func(ctx context.Context, request mcp.CompleteRequest) ([]string, error) {
+					return []string{"red", "blue"}, nil
+				},
+			},
+		})
+		complete := func(ctx context.Context, ref, argument string) mcp.JSONRPCMessage {
+			return server.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":1,"method":"completion/complete","params":{"ref":`+ref+`,"argument":{"name":"`+argument+`","value":""}}}`))
+		}
+
+		tests := []struct {
+			name     string
+			ref      string
+			argument string
+			code     int
+		}{
+			{name: "hidden prompt", ref: `{"type":"ref/prompt","name":"admin"}`, argument: "level", code: mcp.INVALID_PARAMS},
+			{name: "unknown prompt", ref: `{"type":"ref/prompt","name":"unknown"}`, argument: "level", code: mcp.INVALID_PARAMS},
+			{name: "hidden template", ref: `{"type":"ref/resource","uri":"admin://teams/{id}"}`, argument: "id", code: mcp.RESOURCE_NOT_FOUND},
+			{name: "hidden resource", ref: `{"type":"ref/resource","uri":"admin://secrets"}`, argument: "id", code: mcp.RESOURCE_NOT_FOUND},
+			{name: "unknown resource", ref: `{"type":"ref/resource","uri":"test://unknown"}`, argument: "id", code: mcp.RESOURCE_NOT_FOUND},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				errResp, ok := complete(userCtx, tt.ref, tt.argument).(mcp.JSONRPCError)
+				require.True(t, ok)
+				assert.Equal(t, tt.code, errResp.Error.Code)
+				assert.Contains(t, errResp.Error.Message, "not found")
+			})
+		}
+
+		resp, ok := complete(adminCtx, `{"type":"ref/prompt","name":"admin"}`, "level").(mcp.JSONRPCResponse)
+		require.True(t, ok)
+		assert.Equal(t, []string{"low", "high"}, resp.Result.(mcp.CompleteResult).Completion.Values)
+		resp, ok = complete(adminCtx, `{"type":"ref/resource","uri":"admin://teams/{id}"}`, "id").(mcp.JSONRPCResponse)
+		require.True(t, ok)
+		assert.Equal(t, []string{"red", "blue"}, resp.Result.(mcp.CompleteResult).Completion.Values)
+	})
+}
+
+func TestMCPServer_ProtocolVersionStoredOnSession(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0")
+	session := NewInProcessSession("protocol-version-session", nil)
+	ctx := server.WithContext(context.Background(), session)
+
+	response := server.HandleMessage(ctx, []byte(`{
+		"jsonrpc": "2.0",
+		"id": 1,
+		"method": "initialize",
+		"params": {"protocolVersion": "2025-06-18", "clientInfo": {"name": "test", "version": "1.0.0"}}
+	}`))
+	_, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok)
+	assert.Equal(t, "2025-06-18", session.GetProtocolVersion())
+
+	// Batches are forbidden by the negotiated revision
+	response = server.HandleMessage(ctx, []byte(`[{"jsonrpc": "2.0", "id": 2, "method": "ping"}]`))
+	errorResponse, ok := response.(mcp.JSONRPCError)
+	require.True(t, ok)
+	assert.Equal(t, mcp.INVALID_REQUEST, errorResponse.Error.Code)
+	assert.Equal(t, "JSON-RPC batching is not supported in protocol version 2025-06-18", errorResponse.Error.Message)
+}
+
+func TestMCPServer_ToolCallStructuredOutput(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0")
+
+	type weather struct {
+		Temperature float64 `json:"temperature"`
+		Conditions  string  `json:"conditions,omitempty"`
+	}
+
+	outputSchema := mcp.WithOutputSchema(
+		mcp.WithNumber("temperature", mcp.Required()),
+		mcp.WithString("conditions"),
+	)
+
+	server.AddTools(
+		ServerTool{
+			Tool: mcp.NewTool("valid", outputSchema),
+			Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return mcp.NewToolResultStructured(weather{Temperature: 21.5, Conditions: "sunny"}), nil
+			},
+		},
+		ServerTool{
+			Tool: mcp.NewTool("mismatch", outputSchema),
+			Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return mcp.NewToolResultStructured(map[string]any{"temperature": "warm"}), nil
+			},
+		},
+		ServerTool{
+			Tool: mcp.NewTool("missing", outputSchema),
+			Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return mcp.NewToolResultText("21.5"), nil
+			},
+		},
+		ServerTool{
+			Tool: mcp.NewTool("tool-error", outputSchema),
+			Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return mcp.NewToolResultError("weather service unavailable"), nil
+			},
+		},
+	)
+
+	call := func(name string) mcp.JSONRPCMessage {
+		return server.HandleMessage(context.Background(), []byte(fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"method": "tools/call",
+			"params": {"name": %q}
+		}`, name)))
+	}
+
+	t.Run("matching structured content", func(t *testing.T) {
+		resp, ok := call("valid").(mcp.JSONRPCResponse)
+		require.True(t, ok)
+		result, ok := resp.Result.(mcp.CallToolResult)
+		require.True(t, ok)
+		assert.Equal(t, weather{Temperature: 21.5, Conditions: "sunny"}, result.StructuredContent)
+	})
+
+	t.Run("mismatching structured content", func(t *testing.T) {
+		resp, ok := call("mismatch").(mcp.JSONRPCError)
+		require.True(t, ok)
+		assert.Equal(t, mcp.INTERNAL_ERROR, resp.Error.Code)
+		assert.Contains(t, resp.Error.Message, "$.temperature: expected number, got string")
+	})
+
+	t.Run("missing structured content", func(t *testing.T) {
+		resp, ok := call("missing").(mcp.JSONRPCError)
+		require.True(t, ok)
+		assert.Equal(t, mcp.INTERNAL_ERROR, resp.Error.Code)
+		assert.Contains(t, resp.Error.Message, "returned no structured content")
+	})
+
+	t.Run("tool errors are not validated", func(t *testing.T) {
+		resp, ok := call("tool-error").(mcp.JSONRPCResponse)
+		require.True(t, ok)
+		result, ok := resp.Result.(mcp.CallToolResult)
+		require.True(t, ok)
+		assert.True(t, result.IsError)
+	})
+
+	t.Run("output schema is decoded once", func(t *testing.T) {
+		schemas := server.tools["valid"].schemas
+		require.NotNil(t, schemas)
+		call("valid")
+		decoded := schemas.output
+		require.NotNil(t, decoded)
+
+		call("valid")
+		decoded.schema["properties"].(map[string]any)["marker"] = true
+		cached, err := schemas.outputSchema(server.tools["valid"].Tool)
+		require.NoError(t, err)
+		assert.Contains(t, cached.schema["properties"], "marker")
+	})
+}
+
+func TestMCPServer_ToolArgumentValidation(t *testing.T) {
+	var received map[string]any
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		received = request.GetArguments()
+		return mcp.NewToolResultText("ok"), nil
+	}
+
+	newServer := func(opts ...ServerOption) *MCPServer {
+		server := NewMCPServer("test-server", "1.0.0", opts...)
+		server.AddTool(mcp.NewTool("search",
+			mcp.WithString("query", mcp.Required(), mcp.MinLength(2)),
+			mcp.WithString("order", mcp.Enum("asc", "desc"), mcp.DefaultString("asc")),
+			mcp.WithString("lang", mcp.Pattern("^[a-z]{2}$")),
+			mcp.WithNumber("limit", mcp.Min(1), mcp.Max(100), mcp.DefaultNumber(10)),
+			mcp.WithArray("tags", mcp.WithStringItems(), mcp.MinItems(1)),
+		), handler)
+		server.AddTool(mcp.NewToolWithRawSchema("raw", "", json.RawMessage(`{
+			"type": "object",
+			"properties": {"id": {"type": "integer"}},
+			"required": ["id"]
+		}`)), handler)
+		return server
+	}
+	call := func(server *MCPServer, name string, arguments string) mcp.JSONRPCMessage {
+		received = nil
+		return server.HandleMessage(context.Background(), []byte(fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"method": "tools/call",
+			"params": {"name": %q, "arguments": %s}
+		}`, name, arguments)))
+	}
+
+	t.Run("defaults are applied to valid arguments", func(t *testing.T) {
+		resp, ok := call(newServer(WithToolArgumentValidation()), "search", `{"query": "mcp", "tags": ["go"]}`).(mcp.JSONRPCResponse)
+		require.True(t, ok)
+		assert.Equal(t, map[string]any{
+			"query": "mcp",
+			"order": "asc",
+			"limit": float64(10),
+			"tags":  []any{"go"},
+		}, received)
+		assert.NotNil(t, resp.Result)
+	})
+
+	t.Run("invalid arguments are rejected with each violation", func(t *testing.T) {
+		resp, ok := call(newServer(WithToolArgumentValidation()), "search", `{"order": "random", "lang": "english", "limit": 500, "tags": []}`).(mcp.JSONRPCError)
+		require.True(t, ok)
+		assert.Nil(t, received)
+		assert.Equal(t, mcp.INVALID_PARAMS, resp.Error.Code)
+		assert.Contains(t, resp.Error.Message, "invalid arguments for tool 'search'")
+
+		data, ok := resp.Error.Data.(map[string]any)
+		require.True(t, ok)
+		violations, ok := data["violations"].([]SchemaViolation)
+		require.True(t, ok)
+		paths := make([]string, len(violations))
+		for i, v := range violations {
+			paths[i] = v.Path
+		}
+		assert.Equal(t, []string{"$", "$.lang", "$.limit", "$.order", "$.tags"}, paths)
+	})
+
+	t.Run("raw input schema", func(t *testing.T) {
+		server := newServer(WithToolArgumentValidation())
+		resp, ok := call(server, "raw", `{"id": 1.5}`).(mcp.JSONRPCError)
+		require.True(t, ok)
+		assert.Equal(t, mcp.INVALID_PARAMS, resp.Error.Code)
+		assert.Contains(t, resp.Error.Message, "$.id: expected integer, got number")
+
+		_, ok = call(server, "raw", `{"id": 2}`).(mcp.JSONRPCResponse)
+		assert.True(t, ok)
+	})
+
+	t.Run("arguments are not validated by default", func(t *testing.T) {
+		_, ok := call(newServer(), "search", `{"order": "random"}`).(mcp.JSONRPCResponse)
+		require.True(t, ok)
+		assert.Equal(t, map[string]any{"order": "random"}, received)
+	})
+}
+
+func TestAddTypedTool(t *testing.T) {
+	type greetArgs struct {
+		Name  string `json:"name" jsonschema:"minLength=1"`
+		Times int    `json:"times,omitempty" jsonschema:"default=1"`
+	}
+	server := NewMCPServer("test-server", "1.0.0", WithToolArgumentValidation())
+	AddTypedTool(server, mcp.NewTypedTool[greetArgs]("greet"), func(ctx context.Context, request mcp.CallToolRequest, args greetArgs) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(strings.Repeat("hello "+args.Name+" ", args.Times)), nil
+	})
+
+	call := func(arguments string) mcp.JSONRPCMessage {
+		return server.HandleMessage(context.Background(), []byte(fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"method": "tools/call",
+			"params": {"name": "greet", "arguments": %s}
+		}`, arguments)))
+	}
+
+	resp, ok := call(`{"name": "Ada"}`).(mcp.JSONRPCResponse)
+	require.True(t, ok)
+	result, ok := resp.Result.(mcp.CallToolResult)
+	require.True(t, ok)
+	require.Len(t, result.Content, 1)
+	assert.Equal(t, "hello Ada ", result.Content[0].(mcp.TextContent).Text)
+
+	errResp, ok := call(`{"times": 2}`).(mcp.JSONRPCError)
+	require.True(t, ok)
+	assert.Equal(t, mcp.INVALID_PARAMS, errResp.Error.Code)
+	assert.Contains(t, errResp.Error.Message, `missing required property "name"`)
+}
+
 func getTools(length int) []mcp.Tool {
 	list := make([]mcp.Tool, 0, 10000)
 	for i := range length {
@@ -1960,7 +2488,7 @@ func TestMCPServer_ProtocolNegotiation(t *testing.T) {
 		},
 		{
 			name:            "Client requests current latest - should respond with same version",
-			clientVersion:   mcp.LATEST_PROTOCOL_VERSION, // "2025-03-26"
+			clientVersion:   mcp.LATEST_PROTOCOL_VERSION, // "2025-06-18"
 			expectedVersion: mcp.LATEST_PROTOCOL_VERSION,
 		},
 		{
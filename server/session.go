@@ -3,6 +3,8 @@ package server
 import (
 	"context"
 	"fmt"
+	"maps"
+	"sync"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
@@ -39,6 +41,56 @@ type SessionWithTools interface {
 	SetSessionTools(tools map[string]ServerTool)
 }
 
+// SessionWithResources is an extension of ClientSession that can store session-specific
+// resources and resource templates
+type SessionWithResources interface {
+	ClientSession
+	// GetSessionResources returns the resources specific to this session, keyed by URI
+	// This method must be thread-safe for concurrent access
+	GetSessionResources() map[string]ServerResource
+	// SetSessionResources sets resources specific to this session
+	// This method must be thread-safe for concurrent access
+	SetSessionResources(resources map[string]ServerResource)
+	// GetSessionResourceTemplates returns the resource templates specific to this session,
+	// keyed by URI template
+	// This method must be thread-safe for concurrent access
+	GetSessionResourceTemplates() map[string]ServerResourceTemplate
+	// SetSessionResourceTemplates sets resource templates specific to this session
+	// This method must be thread-safe for concurrent access
+	SetSessionResourceTemplates(templates map[string]ServerResourceTemplate)
+}
+
+// SessionWithPrompts is an extension of ClientSession that can store session-specific prompts
+type SessionWithPrompts interface {
+	ClientSession
+	// GetSessionPrompts returns the prompts specific to this session, keyed by name
+	// This method must be thread-safe for concurrent access
+	GetSessionPrompts() map[string]ServerPrompt
+	// SetSessionPrompts sets prompts specific to this session
+	// This method must be thread-safe for concurrent access
+	SetSessionPrompts(prompts map[string]ServerPrompt)
+}
+
+// sessionItems is a thread-safe set of session-specific items, such as
+// resources or prompts, keyed by name. The zero value is ready to use.
+type sessionItems[T any] struct {
+	mu    sync.RWMutex
+	items map[string]T
+}
+
+// get returns a copy of the items.
+func (s *sessionItems[T]) get() map[string]T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return maps.Clone(s.items)
+}
+
+func (s *sessionItems[T]) set(items map[string]T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.items = items
+}
+
 // SessionWithClientInfo is an extension of ClientSession that can store client info
 type SessionWithClientInfo interface {
 	ClientSession
@@ -48,6 +100,17 @@ type SessionWithClientInfo interface {
 	SetClientInfo(clientInfo mcp.Implementation)
 }
 
+// SessionWithProtocolVersion is an extension of ClientSession that stores the
+// protocol version negotiated during initialization
+type SessionWithProtocolVersion interface {
+	ClientSession
+	// GetProtocolVersion returns the negotiated protocol version, or an empty
+	// string if the session has not been initialized yet
+	GetProtocolVersion() string
+	// SetProtocolVersion sets the negotiated protocol version
+	SetProtocolVersion(version string)
+}
+
 // SessionWithStreamableHTTPConfig extends ClientSession to support streamable HTTP transport configurations
 type SessionWithStreamableHTTPConfig interface {
 	ClientSession
@@ -92,6 +155,7 @@ func (s *MCPServer) RegisterSession(
 	if _, exists := s.sessions.LoadOrStore(sessionID, session); exists {
 		return ErrSessionExists
 	}
+	s.metrics.SessionOpened(sessionTransport(session))
 	s.hooks.RegisterSession(ctx, session)
 	return nil
 }
@@ -134,6 +198,7 @@ func (s *MCPServer) sendNotificationToAllClients(notification mcp.JSONRPCNotific
 			case session.NotificationChannel() <- notification:
 				// Successfully sent notification
 			default:
+				s.metrics.NotificationDropped(notification.Method)
 				// Channel is blocked, if there's an error hook, use it
 				if s.hooks != nil && len(s.hooks.OnError) > 0 {
 					err := ErrNotificationChannelBlocked
@@ -163,6 +228,7 @@ func (s *MCPServer) sendNotificationToSpecificClient(session ClientSession, noti
 	case session.NotificationChannel() <- notification:
 		return nil
 	default:
+		s.metrics.NotificationDropped(notification.Method)
 		// Channel is blocked, if there's an error hook, use it
 		if s.hooks != nil && len(s.hooks.OnError) > 0 {
 			err := ErrNotificationChannelBlocked
@@ -209,11 +275,43 @@ func (s *MCPServer) UnregisterSession(
 	if !ok {
 		return
 	}
+	if _, ok := sessionValue.(*streamableHttpSession); !ok {
+		s.closeSession(sessionID)
+	}
 	if session, ok := sessionValue.(ClientSession); ok {
+		s.metrics.SessionClosed(sessionTransport(session))
 		s.hooks.UnregisterSession(ctx, session)
 	}
 }
 
+// sessionWithState is implemented by sessions that the MCP server may only
+// keep state for, such as resource subscriptions, when their transport tells
+// it when they end.
+type sessionWithState interface {
+	keepsState() bool
+}
+
+// sessionKeepsState reports whether the MCP server may keep state for the
+// session until it ends.
+func sessionKeepsState(session ClientSession) bool {
+	if stateful, ok := session.(sessionWithState); ok {
+		return stateful.keepsState()
+	}
+	return true
+}
+
+// closeSession drops the state held for a session that ended. Streamable
+// HTTP sessions are only registered while the client listens on a GET
+// stream, which it may reopen at any time, so their state is dropped when the
+// session is deleted or expires instead.
+func (s *MCPServer) closeSession(sessionID string) {
+	s.removeResourceSubscriptions(sessionID)
+	s.removeRoots(sessionID)
+	if s.rateLimiter != nil {
+		s.rateLimiter.removeSession(sessionID)
+	}
+}
+
 // SendNotificationToAllClients sends a notification to all the currently active clients.
 func (s *MCPServer) SendNotificationToAllClients(
 	method string,
@@ -245,6 +343,7 @@ func (s *MCPServer) sendNotificationCore(
 	case session.NotificationChannel() <- notification:
 		return nil
 	default:
+		s.metrics.NotificationDropped(notification.Method)
 		// Channel is blocked, if there's an error hook, use it
 		if s.hooks != nil && len(s.hooks.OnError) > 0 {
 			method := notification.Method
@@ -343,6 +442,8 @@ func (s *MCPServer) AddSessionTools(sessionID string, tools ...ServerTool) error
 
 	// Add new tools
 	for _, tool := range tools {
+		tool.schemas = &toolSchemas{}
+		tool.limiter = newToolLimiter(s.toolMaxConcurrencyOf(tool))
 		newSessionTools[tool.Tool.Name] = tool
 	}
 
@@ -438,3 +539,249 @@ func (s *MCPServer) DeleteSessionTools(sessionID string, names ...string) error
 
 	return nil
 }
+
+// AddSessionResource adds a resource for a specific session
+func (s *MCPServer) AddSessionResource(sessionID string, resource mcp.Resource, handler ResourceHandlerFunc) error {
+	return s.AddSessionResources(sessionID, ServerResource{Resource: resource, Handler: handler})
+}
+
+// AddSessionResources adds resources for a specific session. Session
+// resources take precedence over global resources with the same URI.
+func (s *MCPServer) AddSessionResources(sessionID string, resources ...ServerResource) error {
+	session, err := s.sessionWithResources(sessionID)
+	if err != nil {
+		return err
+	}
+
+	s.implicitlyRegisterResourceCapabilities()
+
+	newSessionResources := maps.Clone(session.GetSessionResources())
+	if newSessionResources == nil {
+		newSessionResources = make(map[string]ServerResource, len(resources))
+	}
+	for _, resource := range resources {
+		newSessionResources[resource.Resource.URI] = resource
+	}
+	session.SetSessionResources(newSessionResources)
+
+	s.notifySessionResourcesListChanged(session)
+	return nil
+}
+
+// DeleteSessionResources removes resources from a specific session
+func (s *MCPServer) DeleteSessionResources(sessionID string, uris ...string) error {
+	session, err := s.sessionWithResources(sessionID)
+	if err != nil {
+		return err
+	}
+
+	sessionResources := maps.Clone(session.GetSessionResources())
+	var exists bool
+	for _, uri := range uris {
+		if _, ok := sessionResources[uri]; ok {
+			delete(sessionResources, uri)
+			exists = true
+		}
+	}
+	if !exists {
+		return nil
+	}
+	session.SetSessionResources(sessionResources)
+
+	s.notifySessionResourcesListChanged(session)
+	return nil
+}
+
+// AddSessionResourceTemplate adds a resource template for a specific session
+func (s *MCPServer) AddSessionResourceTemplate(
+	sessionID string,
+	template mcp.ResourceTemplate,
+	handler ResourceTemplateHandlerFunc,
+) error {
+	return s.AddSessionResourceTemplates(sessionID, ServerResourceTemplate{Template: template, Handler: handler})
+}
+
+// AddSessionResourceTemplates adds resource templates for a specific session.
+// Session templates are matched before global templates.
+func (s *MCPServer) AddSessionResourceTemplates(sessionID string, templates ...ServerResourceTemplate) error {
+	session, err := s.sessionWithResources(sessionID)
+	if err != nil {
+		return err
+	}
+
+	s.implicitlyRegisterResourceCapabilities()
+
+	newSessionTemplates := maps.Clone(session.GetSessionResourceTemplates())
+	if newSessionTemplates == nil {
+		newSessionTemplates = make(map[string]ServerResourceTemplate, len(templates))
+	}
+	for _, template := range templates {
+		if len(template.Completions) > 0 {
+			s.implicitlyRegisterCompletionCapabilities()
+		}
+		newSessionTemplates[template.Template.URITemplate.Raw()] = template
+	}
+	session.SetSessionResourceTemplates(newSessionTemplates)
+
+	s.notifySessionResourcesListChanged(session)
+	return nil
+}
+
+// DeleteSessionResourceTemplates removes resource templates, identified by
+// their URI template, from a specific session
+func (s *MCPServer) DeleteSessionResourceTemplates(sessionID string, uriTemplates ...string) error {
+	session, err := s.sessionWithResources(sessionID)
+	if err != nil {
+		return err
+	}
+
+	sessionTemplates := maps.Clone(session.GetSessionResourceTemplates())
+	var exists bool
+	for _, uriTemplate := range uriTemplates {
+		if _, ok := sessionTemplates[uriTemplate]; ok {
+			delete(sessionTemplates, uriTemplate)
+			exists = true
+		}
+	}
+	if !exists {
+		return nil
+	}
+	session.SetSessionResourceTemplates(sessionTemplates)
+
+	s.notifySessionResourcesListChanged(session)
+	return nil
+}
+
+// AddSessionPrompt adds a prompt for a specific session
+func (s *MCPServer) AddSessionPrompt(sessionID string, prompt mcp.Prompt, handler PromptHandlerFunc) error {
+	return s.AddSessionPrompts(sessionID, ServerPrompt{Prompt: prompt, Handler: handler})
+}
+
+// AddSessionPrompts adds prompts for a specific session. Session prompts
+// take precedence over global prompts with the same name.
+func (s *MCPServer) AddSessionPrompts(sessionID string, prompts ...ServerPrompt) error {
+	session, err := s.sessionWithPrompts(sessionID)
+	if err != nil {
+		return err
+	}
+
+	s.implicitlyRegisterPromptCapabilities()
+
+	newSessionPrompts := maps.Clone(session.GetSessionPrompts())
+	if newSessionPrompts == nil {
+		newSessionPrompts = make(map[string]ServerPrompt, len(prompts))
+	}
+	for _, prompt := range prompts {
+		if len(prompt.Completions) > 0 || hasEnumArguments(prompt.Prompt) {
+			s.implicitlyRegisterCompletionCapabilities()
+		}
+		newSessionPrompts[prompt.Prompt.Name] = prompt
+	}
+	session.SetSessionPrompts(newSessionPrompts)
+
+	s.notifySessionPromptsListChanged(session)
+	return nil
+}
+
+// DeleteSessionPrompts removes prompts from a specific session
+func (s *MCPServer) DeleteSessionPrompts(sessionID string, names ...string) error {
+	session, err := s.sessionWithPrompts(sessionID)
+	if err != nil {
+		return err
+	}
+
+	sessionPrompts := maps.Clone(session.GetSessionPrompts())
+	var exists bool
+	for _, name := range names {
+		if _, ok := sessionPrompts[name]; ok {
+			delete(sessionPrompts, name)
+			exists = true
+		}
+	}
+	if !exists {
+		return nil
+	}
+	session.SetSessionPrompts(sessionPrompts)
+
+	s.notifySessionPromptsListChanged(session)
+	return nil
+}
+
+func (s *MCPServer) sessionWithResources(sessionID string) (SessionWithResources, error) {
+	sessionValue, ok := s.sessions.Load(sessionID)
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	session, ok := sessionValue.(SessionWithResources)
+	if !ok {
+		return nil, ErrSessionDoesNotSupportResources
+	}
+	return session, nil
+}
+
+func (s *MCPServer) sessionWithPrompts(sessionID string) (SessionWithPrompts, error) {
+	sessionValue, ok := s.sessions.Load(sessionID)
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	session, ok := sessionValue.(SessionWithPrompts)
+	if !ok {
+		return nil, ErrSessionDoesNotSupportPrompts
+	}
+	return session, nil
+}
+
+// notifySessionResourcesListChanged tells an initialized session that its
+// list of resources changed, if the server declared resources.listChanged.
+func (s *MCPServer) notifySessionResourcesListChanged(session ClientSession) {
+	if s.capabilities.resources != nil && s.capabilities.resources.listChanged {
+		s.notifySessionListChanged(session, mcp.MethodNotificationResourcesListChanged)
+	}
+}
+
+// notifySessionPromptsListChanged tells an initialized session that its
+// list of prompts changed, if the server declared prompts.listChanged.
+func (s *MCPServer) notifySessionPromptsListChanged(session ClientSession) {
+	if s.capabilities.prompts != nil && s.capabilities.prompts.listChanged {
+		s.notifySessionListChanged(session, mcp.MethodNotificationPromptsListChanged)
+	}
+}
+
+func (s *MCPServer) notifySessionListChanged(session ClientSession, method string) {
+	// The client of an uninitialized session has not listed anything yet
+	if !session.Initialized() {
+		return
+	}
+	sessionID := session.SessionID()
+	if err := s.SendNotificationToSpecificClient(sessionID, method, nil); err != nil {
+		// The list was changed, only the notification failed
+		if s.hooks != nil && len(s.hooks.OnError) > 0 {
+			hooks := s.hooks
+			go func(sID string, hooks *Hooks) {
+				ctx := context.Background()
+				hooks.onError(ctx, nil, "notification", map[string]any{
+					"method":    method,
+					"sessionID": sID,
+				}, fmt.Errorf("failed to send notification after updating session list: %w", err))
+			}(sessionID, hooks)
+		}
+	}
+}
+
+// sessionResourcesFromContext returns the session-specific resources and
+// resource templates of the session in ctx, if any.
+func sessionResourcesFromContext(ctx context.Context) (map[string]ServerResource, map[string]ServerResourceTemplate) {
+	if session, ok := ClientSessionFromContext(ctx).(SessionWithResources); ok {
+		return session.GetSessionResources(), session.GetSessionResourceTemplates()
+	}
+	return nil, nil
+}
+
+// sessionPromptsFromContext returns the session-specific prompts of the
+// session in ctx, if any.
+func sessionPromptsFromContext(ctx context.Context) map[string]ServerPrompt {
+	if session, ok := ClientSessionFromContext(ctx).(SessionWithPrompts); ok {
+		return session.GetSessionPrompts()
+	}
+	return nil
+}
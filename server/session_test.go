@@ -1507,3 +1507,197 @@ func TestMCPServer_LoggingNotificationFormat(t *testing.T) {
 		})
 	}
 }
+
+func TestMCPServer_SessionResources(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0", WithResourceCapabilities(false, true))
+	textResource := func(text string) ResourceHandlerFunc {
+		return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return []mcp.ResourceContents{mcp.TextResourceContents{URI: request.Params.URI, Text: text}}, nil
+		}
+	}
+	server.AddResource(mcp.NewResource("test://shared", "shared"), textResource("global shared"))
+	server.AddResource(mcp.NewResource("test://global", "global"), textResource("global"))
+
+	session := NewInProcessSession("session-1", nil)
+	other := NewInProcessSession("session-2", nil)
+	require.NoError(t, server.RegisterSession(context.Background(), session))
+	require.NoError(t, server.RegisterSession(context.Background(), other))
+	session.Initialize()
+	other.Initialize()
+
+	err := server.AddSessionResources(session.SessionID(),
+		ServerResource{Resource: mcp.NewResource("test://shared", "shared"), Handler: textResource("session shared")},
+		ServerResource{Resource: mcp.NewResource("test://private", "private"), Handler: textResource("private")},
+	)
+	require.NoError(t, err)
+
+	select {
+	case notification := <-session.Notifications():
+		assert.Equal(t, mcp.MethodNotificationResourcesListChanged, notification.Method)
+	case <-time.After(100 * time.Millisecond):
+		t.Error("Expected notification not received")
+	}
+	select {
+	case notification := <-other.Notifications():
+		t.Errorf("Unexpected notification for other session: %s", notification.Method)
+	default:
+	}
+
+	listResources := func(session ClientSession) []string {
+		response := server.HandleMessage(server.WithContext(context.Background(), session),
+			[]byte(`{"jsonrpc":"2.0","id":1,"method":"resources/list"}`))
+		resp, ok := response.(mcp.JSONRPCResponse)
+		require.True(t, ok, "unexpected response %#v", response)
+		result, ok := resp.Result.(mcp.ListResourcesResult)
+		require.True(t, ok)
+		var uris []string
+		for _, resource := range result.Resources {
+			uris = append(uris, resource.URI)
+		}
+		return uris
+	}
+	readResource := func(session ClientSession, uri string) (string, bool) {
+		response := server.HandleMessage(server.WithContext(context.Background(), session),
+			[]byte(`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"`+uri+`"}}`))
+		resp, ok := response.(mcp.JSONRPCResponse)
+		if !ok {
+			return "", false
+		}
+		result := resp.Result.(mcp.ReadResourceResult)
+		return result.Contents[0].(mcp.TextResourceContents).Text, true
+	}
+
+	assert.ElementsMatch(t, []string{"test://shared", "test://global", "test://private"}, listResources(session))
+	assert.ElementsMatch(t, []string{"test://shared", "test://global"}, listResources(other))
+
+	text, ok := readResource(session, "test://shared")
+	require.True(t, ok)
+	assert.Equal(t, "session shared", text)
+	text, ok = readResource(other, "test://shared")
+	require.True(t, ok)
+	assert.Equal(t, "global shared", text)
+	_, ok = readResource(other, "test://private")
+	assert.False(t, ok, "other sessions must not read session resources")
+
+	require.NoError(t, server.DeleteSessionResources(session.SessionID(), "test://shared"))
+	select {
+	case notification := <-session.Notifications():
+		assert.Equal(t, mcp.MethodNotificationResourcesListChanged, notification.Method)
+	case <-time.After(100 * time.Millisecond):
+		t.Error("Expected notification not received")
+	}
+	text, ok = readResource(session, "test://shared")
+	require.True(t, ok)
+	assert.Equal(t, "global shared", text)
+	assert.Contains(t, session.GetSessionResources(), "test://private")
+}
+
+func TestMCPServer_SessionResourceTemplates(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0", WithResourceCapabilities(false, false))
+	session := NewInProcessSession("session-1", nil)
+	require.NoError(t, server.RegisterSession(context.Background(), session))
+	session.Initialize()
+
+	err := server.AddSessionResourceTemplate(session.SessionID(),
+		mcp.NewResourceTemplate("test://users/{id}", "user"),
+		func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return []mcp.ResourceContents{mcp.TextResourceContents{
+				URI:  request.Params.URI,
+				Text: "user " + request.Params.Arguments["id"].([]string)[0],
+			}}, nil
+		},
+	)
+	require.NoError(t, err)
+
+	// listChanged is disabled, so no notification is sent
+	select {
+	case notification := <-session.Notifications():
+		t.Errorf("Unexpected notification: %s", notification.Method)
+	default:
+	}
+
+	ctx := server.WithContext(context.Background(), session)
+	response := server.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":1,"method":"resources/templates/list"}`))
+	resp, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok)
+	templates := resp.Result.(mcp.ListResourceTemplatesResult).ResourceTemplates
+	require.Len(t, templates, 1)
+	assert.Equal(t, "test://users/{id}", templates[0].URITemplate.Raw())
+
+	response = server.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":2,"method":"resources/read","params":{"uri":"test://users/42"}}`))
+	resp, ok = response.(mcp.JSONRPCResponse)
+	require.True(t, ok, "unexpected response %#v", response)
+	contents := resp.Result.(mcp.ReadResourceResult).Contents
+	assert.Equal(t, "user 42", contents[0].(mcp.TextResourceContents).Text)
+
+	require.NoError(t, server.DeleteSessionResourceTemplates(session.SessionID(), "test://users/{id}"))
+	response = server.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":3,"method":"resources/read","params":{"uri":"test://users/42"}}`))
+	errResp, ok := response.(mcp.JSONRPCError)
+	require.True(t, ok)
+	assert.Equal(t, mcp.RESOURCE_NOT_FOUND, errResp.Error.Code)
+}
+
+func TestMCPServer_SessionPrompts(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0", WithPromptCapabilities(true))
+	promptHandler := func(description string) PromptHandlerFunc {
+		return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			return mcp.NewGetPromptResult(description, nil), nil
+		}
+	}
+	server.AddPrompt(mcp.NewPrompt("greeting"), promptHandler("global greeting"))
+
+	session := NewInProcessSession("session-1", nil)
+	require.NoError(t, server.RegisterSession(context.Background(), session))
+	session.Initialize()
+
+	err := server.AddSessionPrompts(session.SessionID(),
+		ServerPrompt{Prompt: mcp.NewPrompt("greeting"), Handler: promptHandler("session greeting")},
+		ServerPrompt{Prompt: mcp.NewPrompt("farewell"), Handler: promptHandler("session farewell")},
+	)
+	require.NoError(t, err)
+
+	select {
+	case notification := <-session.Notifications():
+		assert.Equal(t, mcp.MethodNotificationPromptsListChanged, notification.Method)
+	case <-time.After(100 * time.Millisecond):
+		t.Error("Expected notification not received")
+	}
+
+	ctx := server.WithContext(context.Background(), session)
+	response := server.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":1,"method":"prompts/list"}`))
+	resp, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok)
+	prompts := resp.Result.(mcp.ListPromptsResult).Prompts
+	require.Len(t, prompts, 2)
+	assert.Equal(t, "farewell", prompts[0].Name)
+	assert.Equal(t, "greeting", prompts[1].Name)
+
+	getPrompt := func(ctx context.Context, name string) string {
+		response := server.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":2,"method":"prompts/get","params":{"name":"`+name+`"}}`))
+		resp, ok := response.(mcp.JSONRPCResponse)
+		require.True(t, ok, "unexpected response %#v", response)
+		return resp.Result.(mcp.GetPromptResult).Description
+	}
+	assert.Equal(t, "session greeting", getPrompt(ctx, "greeting"))
+	assert.Equal(t, "global greeting", getPrompt(context.Background(), "greeting"))
+
+	require.NoError(t, server.DeleteSessionPrompts(session.SessionID(), "greeting"))
+	assert.Equal(t, "global greeting", getPrompt(ctx, "greeting"))
+	assert.Equal(t, "session farewell", getPrompt(ctx, "farewell"))
+}
+
+func TestMCPServer_SessionResourcesAndPromptsUnsupported(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0")
+	session := &sessionTestClient{
+		sessionID:           "session-1",
+		notificationChannel: make(chan mcp.JSONRPCNotification, 10),
+	}
+	require.NoError(t, server.RegisterSession(context.Background(), session))
+
+	err := server.AddSessionResource(session.SessionID(), mcp.NewResource("test://a", "a"), nil)
+	assert.ErrorIs(t, err, ErrSessionDoesNotSupportResources)
+	err = server.AddSessionPrompt(session.SessionID(), mcp.NewPrompt("a"), nil)
+	assert.ErrorIs(t, err, ErrSessionDoesNotSupportPrompts)
+	err = server.AddSessionResource("unknown", mcp.NewResource("test://a", "a"), nil)
+	assert.ErrorIs(t, err, ErrSessionNotFound)
+}
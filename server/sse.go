@@ -4,7 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -28,8 +28,16 @@ type sseSession struct {
 	notificationChannel chan mcp.JSONRPCNotification
 	initialized         atomic.Bool
 	loggingLevel        atomic.Value
-	tools               sync.Map     // stores session-specific tools
-	clientInfo          atomic.Value // stores session-specific client info
+	tools               sync.Map       // stores session-specific tools
+	clientInfo          atomic.Value   // stores session-specific client info
+	protocolVersion     atomic.Value   // stores the negotiated protocol version
+	pendingRequests     clientRequests // requests sent to the client awaiting a response
+
+	resources         sessionItems[ServerResource]         // stores session-specific resources
+	resourceTemplates sessionItems[ServerResourceTemplate] // stores session-specific resource templates
+	prompts           sessionItems[ServerPrompt]           // stores session-specific prompts
+
+	authInfo *AuthInfo // identity of the bearer of the token that opened the session, if any
 }
 
 // SSEContextFunc is a function that takes an existing context and the current
@@ -95,6 +103,30 @@ func (s *sseSession) SetSessionTools(tools map[string]ServerTool) {
 	}
 }
 
+func (s *sseSession) GetSessionResources() map[string]ServerResource {
+	return s.resources.get()
+}
+
+func (s *sseSession) SetSessionResources(resources map[string]ServerResource) {
+	s.resources.set(resources)
+}
+
+func (s *sseSession) GetSessionResourceTemplates() map[string]ServerResourceTemplate {
+	return s.resourceTemplates.get()
+}
+
+func (s *sseSession) SetSessionResourceTemplates(templates map[string]ServerResourceTemplate) {
+	s.resourceTemplates.set(templates)
+}
+
+func (s *sseSession) GetSessionPrompts() map[string]ServerPrompt {
+	return s.prompts.get()
+}
+
+func (s *sseSession) SetSessionPrompts(prompts map[string]ServerPrompt) {
+	s.prompts.set(prompts)
+}
+
 func (s *sseSession) GetClientInfo() mcp.Implementation {
 	if value := s.clientInfo.Load(); value != nil {
 		if clientInfo, ok := value.(mcp.Implementation); ok {
@@ -108,11 +140,70 @@ func (s *sseSession) SetClientInfo(clientInfo mcp.Implementation) {
 	s.clientInfo.Store(clientInfo)
 }
 
+func (s *sseSession) GetProtocolVersion() string {
+	if version, ok := s.protocolVersion.Load().(string); ok {
+		return version
+	}
+	return ""
+}
+
+func (s *sseSession) SetProtocolVersion(version string) {
+	s.protocolVersion.Store(version)
+}
+
+// deliverResponse routes a response posted by the client to the request
+// waiting for it.
+func (s *sseSession) deliverResponse(message json.RawMessage) bool {
+	return s.pendingRequests.deliver(message)
+}
+
+// sendRequest queues a request to the client on the SSE stream and waits for
+// the response, which the client posts to the message endpoint.
+func (s *sseSession) sendRequest(ctx context.Context, method mcp.MCPMethod, params any) (json.RawMessage, error) {
+	id := s.requestID.Add(1)
+	return s.pendingRequests.send(ctx, id, method, params, func(request mcp.JSONRPCRequest) error {
+		eventData, err := json.Marshal(request)
+		if err != nil {
+			return err
+		}
+		select {
+		case s.eventQueue <- fmt.Sprintf("event: message\ndata: %s\n\n", eventData):
+			return nil
+		case <-s.done:
+			return fmt.Errorf("session closed")
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+}
+
+// RequestSampling sends a sampling request to the client and waits for the response.
+func (s *sseSession) RequestSampling(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	return requestClient[mcp.CreateMessageResult](ctx, s.sendRequest, mcp.MethodSamplingCreateMessage, request.CreateMessageParams)
+}
+
+// ListRoots sends a roots/list request to the client and waits for the response.
+func (s *sseSession) ListRoots(ctx context.Context, request mcp.ListRootsRequest) (*mcp.ListRootsResult, error) {
+	return requestClient[mcp.ListRootsResult](ctx, s.sendRequest, mcp.MethodListRoots, request.Params)
+}
+
+// RequestElicitation sends an elicitation request to the client and waits for the response.
+func (s *sseSession) RequestElicitation(ctx context.Context, request mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+	return requestClient[mcp.ElicitResult](ctx, s.sendRequest, mcp.MethodElicitationCreate, request.Params)
+}
+
 var (
-	_ ClientSession         = (*sseSession)(nil)
-	_ SessionWithTools      = (*sseSession)(nil)
-	_ SessionWithLogging    = (*sseSession)(nil)
-	_ SessionWithClientInfo = (*sseSession)(nil)
+	_ ClientSession              = (*sseSession)(nil)
+	_ SessionWithTools           = (*sseSession)(nil)
+	_ SessionWithResources       = (*sseSession)(nil)
+	_ SessionWithPrompts         = (*sseSession)(nil)
+	_ SessionWithLogging         = (*sseSession)(nil)
+	_ SessionWithClientInfo      = (*sseSession)(nil)
+	_ SessionWithProtocolVersion = (*sseSession)(nil)
+	_ SessionWithSampling        = (*sseSession)(nil)
+	_ SessionWithRoots           = (*sseSession)(nil)
+	_ SessionWithElicitation     = (*sseSession)(nil)
+	_ sessionWithClientResponses = (*sseSession)(nil)
 )
 
 // SSEServer implements a Server-Sent Events (SSE) based MCP server.
@@ -133,6 +224,9 @@ type SSEServer struct {
 	keepAlive         bool
 	keepAliveInterval time.Duration
 
+	logger *slog.Logger
+	auth   *authorizer
+
 	mu sync.RWMutex
 }
 
@@ -257,6 +351,25 @@ func WithSSEContextFunc(fn SSEContextFunc) SSEOption {
 	}
 }
 
+// WithSSELogger sets the structured logger of the server, slog.Default() by
+// default.
+func WithSSELogger(logger *slog.Logger) SSEOption {
+	return func(s *SSEServer) {
+		s.logger = logger
+	}
+}
+
+// WithSSEAuth makes the server an OAuth 2.1 resource server: requests to the
+// SSE and message endpoints must carry a bearer token accepted by the
+// verifier of config, and ServeHTTP serves the protected resource metadata on
+// its well-known path. The identity of the bearer is available to handlers
+// with AuthInfoFromContext.
+func WithSSEAuth(config AuthConfig) SSEOption {
+	return func(s *SSEServer) {
+		s.auth = newAuthorizer(config)
+	}
+}
+
 // NewSSEServer creates a new SSE server instance with the given MCP server and options.
 func NewSSEServer(server *MCPServer, opts ...SSEOption) *SSEServer {
 	s := &SSEServer{
@@ -266,6 +379,7 @@ func NewSSEServer(server *MCPServer, opts ...SSEOption) *SSEServer {
 		useFullURLForMessageEndpoint: true,
 		keepAlive:                    false,
 		keepAliveInterval:            10 * time.Second,
+		logger:                       slog.Default(),
 	}
 
 	// Apply all options
@@ -335,6 +449,12 @@ func (s *SSEServer) handleSSE(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
+	if s.auth != nil {
+		var ok bool
+		if r, ok = s.auth.authorize(w, r); !ok {
+			return
+		}
+	}
 
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
@@ -353,10 +473,13 @@ func (s *SSEServer) handleSSE(w http.ResponseWriter, r *http.Request) {
 		eventQueue:          make(chan string, 100), // Buffer for events
 		sessionID:           sessionID,
 		notificationChannel: make(chan mcp.JSONRPCNotification, 100),
+		authInfo:            AuthInfoFromContext(r.Context()),
 	}
 
 	s.sessions.Store(sessionID, session)
 	defer s.sessions.Delete(sessionID)
+	// fail the requests still waiting for a response once the stream is closed
+	defer session.pendingRequests.close(ErrSessionTerminated)
 
 	if err := s.server.RegisterSession(r.Context(), session); err != nil {
 		http.Error(
@@ -470,6 +593,12 @@ func (s *SSEServer) handleMessage(w http.ResponseWriter, r *http.Request) {
 		s.writeJSONRPCError(w, nil, mcp.INVALID_REQUEST, "Method not allowed")
 		return
 	}
+	if s.auth != nil {
+		var ok bool
+		if r, ok = s.auth.authorize(w, r); !ok {
+			return
+		}
+	}
 
 	sessionID := r.URL.Query().Get("sessionId")
 	if sessionID == "" {
@@ -483,6 +612,12 @@ func (s *SSEServer) handleMessage(w http.ResponseWriter, r *http.Request) {
 	}
 	session := sessionI.(*sseSession)
 
+	// A session can only be used with tokens of the identity that opened it
+	if s.auth != nil && !sameIdentity(session.authInfo, AuthInfoFromContext(r.Context())) {
+		http.Error(w, "Session belongs to another identity", http.StatusForbidden)
+		return
+	}
+
 	// Set the client context before handling the message
 	ctx := s.server.WithContext(r.Context(), session)
 	if s.contextFunc != nil {
@@ -496,6 +631,12 @@ func (s *SSEServer) handleMessage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Responses to requests sent to the client are routed to the waiting caller
+	if session.pendingRequests.deliver(rawMessage) {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
 	// Create a context that preserves all values from parent ctx but won't be canceled when the parent is canceled.
 	// this is required because the http ctx will be canceled when the client disconnects
 	detachedCtx := context.WithoutCancel(ctx)
@@ -517,7 +658,8 @@ func (s *SSEServer) handleMessage(w http.ResponseWriter, r *http.Request) {
 			var message string
 			if eventData, err := json.Marshal(response); err != nil {
 				// If there is an error marshalling the response, send a generic error response
-				log.Printf("failed to marshal response: %v", err)
+				attrs := append(messageLogAttrs(rawMessage), logKeySessionID, sessionID, logKeyError, err)
+				s.logger.Error("failed to marshal response", attrs...)
 				message = "event: message\ndata: {\"error\": \"internal error\",\"jsonrpc\": \"2.0\", \"id\": null}\n\n"
 			} else {
 				message = fmt.Sprintf("event: message\ndata: %s\n\n", eventData)
@@ -531,7 +673,8 @@ func (s *SSEServer) handleMessage(w http.ResponseWriter, r *http.Request) {
 				// Session is closed, don't try to queue
 			default:
 				// Queue is full, log this situation
-				log.Printf("Event queue full for session %s", sessionID)
+				attrs := append(messageLogAttrs(rawMessage), logKeySessionID, sessionID)
+				s.logger.Error("Event queue full", attrs...)
 			}
 		}
 	}(messageCtx)
@@ -703,6 +846,10 @@ func (s *SSEServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	path := r.URL.Path
+	if s.auth != nil && path == s.auth.metadataPath {
+		s.auth.serveMetadata(w, r)
+		return
+	}
 	// Use exact path matching rather than Contains
 	ssePath := s.CompleteSsePath()
 	if ssePath != "" && path == ssePath {
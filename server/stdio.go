@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"log/slog"
 	"os"
 	"os/signal"
 	"sync"
@@ -27,17 +28,25 @@ type StdioContextFunc func(ctx context.Context) context.Context
 // communicate via standard input/output streams using JSON-RPC messages.
 type StdioServer struct {
 	server      *MCPServer
-	errLogger   *log.Logger
+	logger      *slog.Logger
 	contextFunc StdioContextFunc
 }
 
 // StdioOption defines a function type for configuring StdioServer
 type StdioOption func(*StdioServer)
 
-// WithErrorLogger sets the error logger for the server
+// WithErrorLogger sets the error logger for the server. Records are written
+// as text through the logger, with its prefix and flags.
 func WithErrorLogger(logger *log.Logger) StdioOption {
 	return func(s *StdioServer) {
-		s.errLogger = logger
+		s.logger = slogFromLogLogger(logger)
+	}
+}
+
+// WithStdioLogger sets the structured logger of the server.
+func WithStdioLogger(logger *slog.Logger) StdioOption {
+	return func(s *StdioServer) {
+		s.logger = logger
 	}
 }
 
@@ -55,18 +64,16 @@ type stdioSession struct {
 	notifications   chan mcp.JSONRPCNotification
 	initialized     atomic.Bool
 	loggingLevel    atomic.Value
-	clientInfo      atomic.Value                     // stores session-specific client info
-	writer          io.Writer                        // for sending requests to client
-	requestID       atomic.Int64                     // for generating unique request IDs
-	mu              sync.RWMutex                     // protects writer
-	pendingRequests map[int64]chan *samplingResponse // for tracking pending sampling requests
-	pendingMu       sync.RWMutex                     // protects pendingRequests
-}
-
-// samplingResponse represents a response to a sampling request
-type samplingResponse struct {
-	result *mcp.CreateMessageResult
-	err    error
+	clientInfo      atomic.Value   // stores session-specific client info
+	protocolVersion atomic.Value   // stores the negotiated protocol version
+	writer          io.Writer      // for sending requests to client
+	requestID       atomic.Int64   // for generating unique request IDs
+	mu              sync.RWMutex   // protects writer
+	pendingRequests clientRequests // for tracking pending requests to the client
+
+	resources         sessionItems[ServerResource]
+	resourceTemplates sessionItems[ServerResourceTemplate]
+	prompts           sessionItems[ServerPrompt]
 }
 
 func (s *stdioSession) SessionID() string {
@@ -100,6 +107,17 @@ func (s *stdioSession) SetClientInfo(clientInfo mcp.Implementation) {
 	s.clientInfo.Store(clientInfo)
 }
 
+func (s *stdioSession) GetProtocolVersion() string {
+	if version, ok := s.protocolVersion.Load().(string); ok {
+		return version
+	}
+	return ""
+}
+
+func (s *stdioSession) SetProtocolVersion(version string) {
+	s.protocolVersion.Store(version)
+}
+
 func (s *stdioSession) SetLogLevel(level mcp.LoggingLevel) {
 	s.loggingLevel.Store(level)
 }
@@ -112,8 +130,14 @@ func (s *stdioSession) GetLogLevel() mcp.LoggingLevel {
 	return level.(mcp.LoggingLevel)
 }
 
-// RequestSampling sends a sampling request to the client and waits for the response.
-func (s *stdioSession) RequestSampling(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+// deliverResponse routes a response from the client to the request waiting
+// for it.
+func (s *stdioSession) deliverResponse(message json.RawMessage) bool {
+	return s.pendingRequests.deliver(message)
+}
+
+// sendRequest sends a request to the client and waits for the response.
+func (s *stdioSession) sendRequest(ctx context.Context, method mcp.MCPMethod, params any) (json.RawMessage, error) {
 	s.mu.RLock()
 	writer := s.writer
 	s.mu.RUnlock()
@@ -125,54 +149,60 @@ func (s *stdioSession) RequestSampling(ctx context.Context, request mcp.CreateMe
 	// Generate a unique request ID
 	id := s.requestID.Add(1)
 
-	// Create a response channel for this request
-	responseChan := make(chan *samplingResponse, 1)
-	s.pendingMu.Lock()
-	s.pendingRequests[id] = responseChan
-	s.pendingMu.Unlock()
-
-	// Cleanup function to remove the pending request
-	cleanup := func() {
-		s.pendingMu.Lock()
-		delete(s.pendingRequests, id)
-		s.pendingMu.Unlock()
-	}
-	defer cleanup()
-
-	// Create the JSON-RPC request
-	jsonRPCRequest := struct {
-		JSONRPC string                  `json:"jsonrpc"`
-		ID      int64                   `json:"id"`
-		Method  string                  `json:"method"`
-		Params  mcp.CreateMessageParams `json:"params"`
-	}{
-		JSONRPC: mcp.JSONRPC_VERSION,
-		ID:      id,
-		Method:  string(mcp.MethodSamplingCreateMessage),
-		Params:  request.CreateMessageParams,
-	}
+	return s.pendingRequests.send(ctx, id, method, params, func(request mcp.JSONRPCRequest) error {
+		requestBytes, err := json.Marshal(request)
+		if err != nil {
+			return err
+		}
+		requestBytes = append(requestBytes, '\n')
+		_, err = writer.Write(requestBytes)
+		return err
+	})
+}
 
-	// Marshal and send the request
-	requestBytes, err := json.Marshal(jsonRPCRequest)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal sampling request: %w", err)
-	}
-	requestBytes = append(requestBytes, '\n')
+// RequestSampling sends a sampling request to the client and waits for the response.
+func (s *stdioSession) RequestSampling(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	return requestClient[mcp.CreateMessageResult](ctx, s.sendRequest, mcp.MethodSamplingCreateMessage, request.CreateMessageParams)
+}
 
-	if _, err := writer.Write(requestBytes); err != nil {
-		return nil, fmt.Errorf("failed to write sampling request: %w", err)
-	}
+// ListRoots sends a roots/list request to the client and waits for the response.
+func (s *stdioSession) ListRoots(ctx context.Context, request mcp.ListRootsRequest) (*mcp.ListRootsResult, error) {
+	return requestClient[mcp.ListRootsResult](ctx, s.sendRequest, mcp.MethodListRoots, request.Params)
+}
 
-	// Wait for the response or context cancellation
-	select {
-	case <-ctx.Done():
-		return nil, ctx.Err()
-	case response := <-responseChan:
-		if response.err != nil {
-			return nil, response.err
-		}
-		return response.result, nil
-	}
+// RequestElicitation sends an elicitation request to the client and waits for the response.
+func (s *stdioSession) RequestElicitation(ctx context.Context, request mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+	return requestClient[mcp.ElicitResult](ctx, s.sendRequest, mcp.MethodElicitationCreate, request.Params)
+}
+
+// GetSessionResources returns the resources specific to the session.
+func (s *stdioSession) GetSessionResources() map[string]ServerResource {
+	return s.resources.get()
+}
+
+// SetSessionResources sets the resources specific to the session.
+func (s *stdioSession) SetSessionResources(resources map[string]ServerResource) {
+	s.resources.set(resources)
+}
+
+// GetSessionResourceTemplates returns the resource templates specific to the session.
+func (s *stdioSession) GetSessionResourceTemplates() map[string]ServerResourceTemplate {
+	return s.resourceTemplates.get()
+}
+
+// SetSessionResourceTemplates sets the resource templates specific to the session.
+func (s *stdioSession) SetSessionResourceTemplates(templates map[string]ServerResourceTemplate) {
+	s.resourceTemplates.set(templates)
+}
+
+// GetSessionPrompts returns the prompts specific to the session.
+func (s *stdioSession) GetSessionPrompts() map[string]ServerPrompt {
+	return s.prompts.get()
+}
+
+// SetSessionPrompts sets the prompts specific to the session.
+func (s *stdioSession) SetSessionPrompts(prompts map[string]ServerPrompt) {
+	s.prompts.set(prompts)
 }
 
 // SetWriter sets the writer for sending requests to the client.
@@ -183,34 +213,41 @@ func (s *stdioSession) SetWriter(writer io.Writer) {
 }
 
 var (
-	_ ClientSession         = (*stdioSession)(nil)
-	_ SessionWithLogging    = (*stdioSession)(nil)
-	_ SessionWithClientInfo = (*stdioSession)(nil)
-	_ SessionWithSampling   = (*stdioSession)(nil)
+	_ ClientSession              = (*stdioSession)(nil)
+	_ SessionWithLogging         = (*stdioSession)(nil)
+	_ SessionWithResources       = (*stdioSession)(nil)
+	_ SessionWithPrompts         = (*stdioSession)(nil)
+	_ SessionWithClientInfo      = (*stdioSession)(nil)
+	_ SessionWithProtocolVersion = (*stdioSession)(nil)
+	_ SessionWithSampling        = (*stdioSession)(nil)
+	_ SessionWithRoots           = (*stdioSession)(nil)
+	_ SessionWithElicitation     = (*stdioSession)(nil)
+	_ sessionWithClientResponses = (*stdioSession)(nil)
 )
 
 var stdioSessionInstance = stdioSession{
-	notifications:   make(chan mcp.JSONRPCNotification, 100),
-	pendingRequests: make(map[int64]chan *samplingResponse),
+	notifications: make(chan mcp.JSONRPCNotification, 100),
 }
 
 // NewStdioServer creates a new stdio server wrapper around an MCPServer.
-// It initializes the server with a default error logger that discards all output.
+// It initializes the server with a default logger writing text records to stderr.
 func NewStdioServer(server *MCPServer) *StdioServer {
 	return &StdioServer{
 		server: server,
-		errLogger: log.New(
-			os.Stderr,
-			"",
-			log.LstdFlags,
-		), // Default to discarding logs
+		logger: slog.New(slog.NewTextHandler(os.Stderr, nil)),
 	}
 }
 
 // SetErrorLogger configures where error messages from the StdioServer are logged.
-// The provided logger will receive all error messages generated during server operation.
+// The provided logger will receive all error messages generated during server operation,
+// written as text through the logger, with its prefix and flags.
 func (s *StdioServer) SetErrorLogger(logger *log.Logger) {
-	s.errLogger = logger
+	s.logger = slogFromLogLogger(logger)
+}
+
+// SetLogger sets the structured logger of the server.
+func (s *StdioServer) SetLogger(logger *slog.Logger) {
+	s.logger = logger
 }
 
 // SetContextFunc sets a function that will be called to customise the context
@@ -228,7 +265,10 @@ func (s *StdioServer) handleNotifications(ctx context.Context, stdout io.Writer)
 		select {
 		case notification := <-stdioSessionInstance.notifications:
 			if err := s.writeResponse(notification, stdout); err != nil {
-				s.errLogger.Printf("Error writing notification: %v", err)
+				s.logger.Error("Error writing notification",
+					logKeySessionID, stdioSessionInstance.SessionID(),
+					logKeyMethod, notification.Method,
+					logKeyError, err)
 			}
 		case <-ctx.Done():
 			return
@@ -253,7 +293,7 @@ func (s *StdioServer) processInputStream(ctx context.Context, reader *bufio.Read
 			if err == io.EOF {
 				return nil
 			}
-			s.errLogger.Printf("Error reading input: %v", err)
+			s.logger.Error("Error reading input", logKeyError, err)
 			return err
 		}
 
@@ -261,7 +301,7 @@ func (s *StdioServer) processInputStream(ctx context.Context, reader *bufio.Read
 			if err == io.EOF {
 				return nil
 			}
-			s.errLogger.Printf("Error handling message: %v", err)
+			s.logger.Error("Error handling message", logKeyError, err)
 			return err
 		}
 	}
@@ -343,8 +383,8 @@ func (s *StdioServer) processMessage(
 		return s.writeResponse(response, writer)
 	}
 
-	// Check if this is a response to a sampling request
-	if s.handleSamplingResponse(rawMessage) {
+	// Check if this is a response to a request sent to the client
+	if s.handleClientResponse(rawMessage) {
 		return nil
 	}
 
@@ -352,13 +392,15 @@ func (s *StdioServer) processMessage(
 	var baseMessage struct {
 		Method string `json:"method"`
 	}
-	if json.Unmarshal(rawMessage, &baseMessage) == nil && baseMessage.Method == "tools/call" {
-		// Process tool calls concurrently to avoid blocking on sampling requests
+	if isJSONRPCBatch(rawMessage) || (json.Unmarshal(rawMessage, &baseMessage) == nil && baseMessage.Method == "tools/call") {
+		// Process tool calls and batches, which may hold tool calls,
+		// concurrently to avoid blocking on sampling requests
 		go func() {
 			response := s.server.HandleMessage(ctx, rawMessage)
 			if response != nil {
 				if err := s.writeResponse(response, writer); err != nil {
-					s.errLogger.Printf("Error writing tool response: %v", err)
+					attrs := append(messageLogAttrs(rawMessage), logKeySessionID, stdioSessionInstance.SessionID(), logKeyError, err)
+					s.logger.Error("Error writing tool response", attrs...)
 				}
 			}
 		}()
@@ -378,63 +420,10 @@ func (s *StdioServer) processMessage(
 	return nil
 }
 
-// handleSamplingResponse checks if the message is a response to a sampling request
-// and routes it to the appropriate pending request channel.
-func (s *StdioServer) handleSamplingResponse(rawMessage json.RawMessage) bool {
-	return stdioSessionInstance.handleSamplingResponse(rawMessage)
-}
-
-// handleSamplingResponse handles incoming sampling responses for this session
-func (s *stdioSession) handleSamplingResponse(rawMessage json.RawMessage) bool {
-	// Try to parse as a JSON-RPC response
-	var response struct {
-		JSONRPC string          `json:"jsonrpc"`
-		ID      json.Number     `json:"id"`
-		Result  json.RawMessage `json:"result,omitempty"`
-		Error   *struct {
-			Code    int    `json:"code"`
-			Message string `json:"message"`
-		} `json:"error,omitempty"`
-	}
-
-	if err := json.Unmarshal(rawMessage, &response); err != nil {
-		return false
-	}
-	// Parse the ID as int64
-	idInt64, err := response.ID.Int64()
-	if err != nil || (response.Result == nil && response.Error == nil) {
-		return false
-	}
-
-	// Look for a pending request with this ID
-	s.pendingMu.RLock()
-	responseChan, exists := s.pendingRequests[idInt64]
-	s.pendingMu.RUnlock()
-
-	if !exists {
-		return false
-	} // Parse and send the response
-	samplingResp := &samplingResponse{}
-
-	if response.Error != nil {
-		samplingResp.err = fmt.Errorf("sampling request failed: %s", response.Error.Message)
-	} else {
-		var result mcp.CreateMessageResult
-		if err := json.Unmarshal(response.Result, &result); err != nil {
-			samplingResp.err = fmt.Errorf("failed to unmarshal sampling response: %w", err)
-		} else {
-			samplingResp.result = &result
-		}
-	}
-
-	// Send the response (non-blocking)
-	select {
-	case responseChan <- samplingResp:
-	default:
-		// Channel is full or closed, ignore
-	}
-
-	return true
+// handleClientResponse checks if the message is a response to a request sent
+// to the client and routes it to the appropriate pending request channel.
+func (s *StdioServer) handleClientResponse(rawMessage json.RawMessage) bool {
+	return stdioSessionInstance.pendingRequests.deliver(rawMessage)
 }
 
 // writeResponse marshals and writes a JSON-RPC response message followed by a newline.
@@ -4,10 +4,13 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"log"
+	"log/slog"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
@@ -20,8 +23,8 @@ func TestStdioServer(t *testing.T) {
 		if stdioServer.server == nil {
 			t.Error("MCPServer should not be nil")
 		}
-		if stdioServer.errLogger == nil {
-			t.Error("errLogger should not be nil")
+		if stdioServer.logger == nil {
+			t.Error("logger should not be nil")
 		}
 	})
 
@@ -268,3 +271,155 @@ func TestStdioServer(t *testing.T) {
 		}
 	})
 }
+
+func TestStdioServer_Batch(t *testing.T) {
+	stdinReader, stdinWriter := io.Pipe()
+	stdoutReader, stdoutWriter := io.Pipe()
+
+	mcpServer := NewMCPServer("test", "1.0.0")
+	stdioServer := NewStdioServer(mcpServer)
+	stdioServer.SetErrorLogger(log.New(io.Discard, "", 0))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	serverErrCh := make(chan error, 1)
+	go func() {
+		err := stdioServer.Listen(ctx, stdinReader, stdoutWriter)
+		if err != nil && err != io.EOF && err != context.Canceled {
+			serverErrCh <- err
+		}
+		stdoutWriter.Close()
+		close(serverErrCh)
+	}()
+
+	scanner := bufio.NewScanner(stdoutReader)
+	send := func(message string) []byte {
+		if _, err := stdinWriter.Write([]byte(message + "\n")); err != nil {
+			t.Fatal(err)
+		}
+		if !scanner.Scan() {
+			t.Fatal("failed to read response")
+		}
+		return scanner.Bytes()
+	}
+
+	// Batching is only allowed by protocol versions that support it
+	send(`{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {"protocolVersion": "2025-03-26", "clientInfo": {"name": "test-client", "version": "1.0.0"}}}`)
+
+	responseBytes := send(`[{"jsonrpc": "2.0", "id": 2, "method": "ping"}, {"jsonrpc": "2.0", "method": "notifications/initialized"}, {"jsonrpc": "2.0", "id": 3, "method": "ping"}]`)
+
+	var responses []map[string]any
+	if err := json.Unmarshal(responseBytes, &responses); err != nil {
+		t.Fatalf("failed to unmarshal batch response %s: %v", responseBytes, err)
+	}
+	if len(responses) != 2 {
+		t.Fatalf("expected 2 responses, got %d", len(responses))
+	}
+	for i, id := range []float64{2, 3} {
+		if responses[i]["id"] != id {
+			t.Errorf("expected id %v, got %v", id, responses[i]["id"])
+		}
+		if responses[i]["error"] != nil {
+			t.Errorf("unexpected error in response: %v", responses[i]["error"])
+		}
+	}
+
+	cancel()
+	stdinWriter.Close()
+
+	if err := <-serverErrCh; err != nil {
+		t.Errorf("unexpected server error: %v", err)
+	}
+}
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
+
+// recordWriter sends every JSON log record written to it on a channel.
+type recordWriter chan map[string]any
+
+func (w recordWriter) Write(p []byte) (int, error) {
+	var record map[string]any
+	if err := json.Unmarshal(p, &record); err != nil {
+		return 0, err
+	}
+	w <- record
+	return len(p), nil
+}
+
+func TestStdioServer_Logger(t *testing.T) {
+	mcpServer := NewMCPServer("test", "1.0.0")
+	mcpServer.AddTool(mcp.NewTool("echo"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	records := make(recordWriter, 1)
+	stdioServer := NewStdioServer(mcpServer)
+	stdioServer.SetLogger(slog.New(slog.NewJSONHandler(records, nil)))
+
+	err := stdioServer.processMessage(context.Background(),
+		`{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"echo"}}`, failingWriter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case record := <-records:
+		expected := map[string]any{
+			"level":     "ERROR",
+			"msg":       "Error writing tool response",
+			"method":    "tools/call",
+			"requestID": float64(7),
+			"tool":      "echo",
+			"sessionID": "stdio",
+			"error":     "broken pipe",
+		}
+		for key, value := range expected {
+			if record[key] != value {
+				t.Errorf("expected %s=%v, got %v", key, value, record[key])
+			}
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for log record")
+	}
+}
+
+// lineWriter sends every line written to it on a channel.
+type lineWriter chan string
+
+func (w lineWriter) Write(p []byte) (int, error) {
+	w <- string(p)
+	return len(p), nil
+}
+
+func TestStdioServer_ErrorLogger(t *testing.T) {
+	mcpServer := NewMCPServer("test", "1.0.0")
+	mcpServer.AddTool(mcp.NewTool("echo"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	lines := make(lineWriter, 1)
+	stdioServer := NewStdioServer(mcpServer)
+	stdioServer.SetErrorLogger(log.New(lines, "[mcp] ", log.Lmsgprefix))
+
+	err := stdioServer.processMessage(context.Background(),
+		`{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"echo"}}`, failingWriter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case line := <-lines:
+		expected := "[mcp] ERROR: Error writing tool response method=tools/call requestID=7 tool=echo sessionID=stdio error=\"broken pipe\"\n"
+		if line != expected {
+			t.Errorf("expected %q, got %q", expected, line)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for log line")
+	}
+}
@@ -3,11 +3,15 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"maps"
 	"mime"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -86,13 +90,69 @@ func WithStreamableHTTPServer(srv *http.Server) StreamableHTTPOption {
 	}
 }
 
+// WithEventStore enables stream resumability. Every event sent on an SSE
+// stream of a session is stored and carries an ID, and a GET request of the
+// session with the Last-Event-ID header replays the events sent on that
+// stream after it. Requests keep being processed when their stream is
+// dropped, so that their response can be replayed. Streams without a session
+// ID, e.g. in stateless mode, can't be resumed, as they can't be told apart.
+func WithEventStore(store EventStore) StreamableHTTPOption {
+	return func(s *StreamableHTTPServer) {
+		s.eventStore = store
+	}
+}
+
+// WithClientRequestTimeout bounds how long the server waits for the client's
+// response to its requests, such as sampling requests. By default, the
+// server waits as long as the context of the request allows.
+func WithClientRequestTimeout(timeout time.Duration) StreamableHTTPOption {
+	return func(s *StreamableHTTPServer) {
+		s.clientRequestTimeout = timeout
+	}
+}
+
+// WithSessionIdleTimeout sets how long a session initialized by the server is
+// kept once no request or stream of it is in progress. When a session
+// expires, the state kept for it, such as its resource subscriptions and
+// session-specific tools, is dropped as if the client had deleted it. The
+// default is DefaultSessionIdleTimeout, and a non-positive timeout keeps
+// sessions until they are deleted.
+func WithSessionIdleTimeout(timeout time.Duration) StreamableHTTPOption {
+	return func(s *StreamableHTTPServer) {
+		s.sessionIdleTimeout = timeout
+	}
+}
+
 // WithLogger sets the logger for the server
 func WithLogger(logger util.Logger) StreamableHTTPOption {
+	return func(s *StreamableHTTPServer) {
+		s.logger = util.ToSlog(logger)
+	}
+}
+
+// WithStreamableHTTPLogger sets the structured logger of the server,
+// slog.Default() by default.
+func WithStreamableHTTPLogger(logger *slog.Logger) StreamableHTTPOption {
 	return func(s *StreamableHTTPServer) {
 		s.logger = logger
 	}
 }
 
+// WithStreamableHTTPAuth makes the server an OAuth 2.1 resource server:
+// requests must carry a bearer token accepted by the verifier of config, and
+// the protected resource metadata is served on its well-known path. The
+// identity of the bearer is available to handlers with AuthInfoFromContext.
+//
+// A session is bound to the identity that initialized it: requests for it
+// with the token of another identity are rejected with 403. Bindings are kept
+// in memory, so sessions initialized by another server instance are not
+// bound.
+func WithStreamableHTTPAuth(config AuthConfig) StreamableHTTPOption {
+	return func(s *StreamableHTTPServer) {
+		s.auth = newAuthorizer(config)
+	}
+}
+
 // StreamableHTTPServer implements a Streamable-http based MCP server.
 // It communicates with clients over HTTP protocol, supporting both direct HTTP responses, and SSE streams.
 // https://modelcontextprotocol.io/specification/2025-03-26/basic/transports#streamable-http
@@ -114,13 +174,11 @@ func WithLogger(logger util.Logger) StreamableHTTPOption {
 // not trigger the session registration. So the methods like `SendNotificationToSpecificClient`
 // or `hooks.onRegisterSession` will not be triggered for POST messages.
 //
-// The current implementation does not support the following features from the specification:
-//   - Batching of requests/notifications/responses in arrays.
-//   - Stream Resumability
+// Stream resumability is enabled with the WithEventStore option.
 type StreamableHTTPServer struct {
 	server            *MCPServer
 	sessionTools      *sessionToolsStore
-	sessionRequestIDs sync.Map // sessionId --> last requestID(*atomic.Int64)
+	sessionPrimitives *sessionPrimitivesStore
 
 	httpServer *http.Server
 	mu         sync.RWMutex
@@ -129,19 +187,29 @@ type StreamableHTTPServer struct {
 	contextFunc             HTTPContextFunc
 	sessionIdManager        SessionIdManager
 	listenHeartbeatInterval time.Duration
-	logger                  util.Logger
+	logger                  *slog.Logger
 	sessionLogLevels        *sessionLogLevelsStore
+	sessionProtocolVersions *sessionProtocolVersionsStore
+	eventStore              EventStore
+	clientRequestTimeout    time.Duration
+	auth                    *authorizer
+	sessionIdleTimeout      time.Duration
+	sessionStates           sync.Map // sessionID -> *sessionState, for the sessions initialized by this server
+	liveStreams             sync.Map // streamID -> *liveStream, for the POST streams being written
 }
 
 // NewStreamableHTTPServer creates a new streamable-http server instance
 func NewStreamableHTTPServer(server *MCPServer, opts ...StreamableHTTPOption) *StreamableHTTPServer {
 	s := &StreamableHTTPServer{
-		server:           server,
-		sessionTools:     newSessionToolsStore(),
-		sessionLogLevels: newSessionLogLevelsStore(),
-		endpointPath:     "/mcp",
-		sessionIdManager: &InsecureStatefulSessionIdManager{},
-		logger:           util.DefaultLogger(),
+		server:                  server,
+		sessionTools:            newSessionToolsStore(),
+		sessionPrimitives:       &sessionPrimitivesStore{},
+		sessionLogLevels:        newSessionLogLevelsStore(),
+		sessionProtocolVersions: newSessionProtocolVersionsStore(),
+		endpointPath:            "/mcp",
+		sessionIdManager:        &InsecureStatefulSessionIdManager{},
+		logger:                  slog.Default(),
+		sessionIdleTimeout:      DefaultSessionIdleTimeout,
 	}
 
 	// Apply all options
@@ -153,6 +221,20 @@ func NewStreamableHTTPServer(server *MCPServer, opts ...StreamableHTTPOption) *S
 
 // ServeHTTP implements the http.Handler interface.
 func (s *StreamableHTTPServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.auth != nil {
+		if r.URL.Path == s.auth.metadataPath {
+			s.auth.serveMetadata(w, r)
+			return
+		}
+		var ok bool
+		if r, ok = s.auth.authorize(w, r); !ok {
+			return
+		}
+		if !s.authorizeSession(w, r) {
+			return
+		}
+	}
+
 	switch r.Method {
 	case http.MethodPost:
 		s.handlePost(w, r)
@@ -174,6 +256,9 @@ func (s *StreamableHTTPServer) Start(addr string) error {
 	if s.httpServer == nil {
 		mux := http.NewServeMux()
 		mux.Handle(s.endpointPath, s)
+		if s.auth != nil {
+			mux.Handle(s.auth.metadataPath, s)
+		}
 		s.httpServer = &http.Server{
 			Addr:    addr,
 			Handler: mux,
@@ -207,10 +292,41 @@ func (s *StreamableHTTPServer) Shutdown(ctx context.Context) error {
 
 // --- internal methods ---
 
+// authorizeSession rejects a request for a session with the token of
+// another identity than the one that initialized the session. Sessions that
+// were not initialized by this server, or that ended, have no owner: their
+// requests are rejected as well.
+func (s *StreamableHTTPServer) authorizeSession(w http.ResponseWriter, r *http.Request) bool {
+	sessionID := r.Header.Get(headerKeySessionID)
+	if sessionID == "" {
+		return true
+	}
+	value, ok := s.sessionStates.Load(sessionID)
+	if !ok || value.(*sessionState).owner == nil {
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return false
+	}
+	if !sameIdentity(value.(*sessionState).owner, AuthInfoFromContext(r.Context())) {
+		http.Error(w, "Session belongs to another identity", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
 const (
-	headerKeySessionID = "Mcp-Session-Id"
+	headerKeySessionID       = "Mcp-Session-Id"
+	headerKeyProtocolVersion = "MCP-Protocol-Version"
+	headerKeyLastEventID     = "Last-Event-ID"
 )
 
+// DefaultSessionIdleTimeout is how long a session is kept without activity
+// by default. See WithSessionIdleTimeout.
+const DefaultSessionIdleTimeout = 30 * time.Minute
+
+// defaultStreamableHTTPProtocolVersion is assumed for requests that carry no
+// MCP-Protocol-Version header, as the header was introduced after it.
+const defaultStreamableHTTPProtocolVersion = "2025-03-26"
+
 func (s *StreamableHTTPServer) handlePost(w http.ResponseWriter, r *http.Request) {
 	// post request carry request/notification message
 
@@ -228,10 +344,18 @@ func (s *StreamableHTTPServer) handlePost(w http.ResponseWriter, r *http.Request
 		s.writeJSONRPCError(w, nil, mcp.PARSE_ERROR, fmt.Sprintf("read request body error: %v", err))
 		return
 	}
+	// A batch is handled as a whole by the MCP server, which can't initialize
+	// a session from within a batch
+	isBatch := isJSONRPCBatch(rawData)
 	var baseMessage struct {
 		Method mcp.MCPMethod `json:"method"`
 	}
-	if err := json.Unmarshal(rawData, &baseMessage); err != nil {
+	if isBatch {
+		if !json.Valid(rawData) {
+			s.writeJSONRPCError(w, nil, mcp.PARSE_ERROR, "request body is not valid json")
+			return
+		}
+	} else if err := json.Unmarshal(rawData, &baseMessage); err != nil {
 		s.writeJSONRPCError(w, nil, mcp.PARSE_ERROR, "request body is not valid json")
 		return
 	}
@@ -240,10 +364,14 @@ func (s *StreamableHTTPServer) handlePost(w http.ResponseWriter, r *http.Request
 	// Prepare the session for the mcp server
 	// The session is ephemeral. Its life is the same as the request. It's only created
 	// for interaction with the mcp server.
-	var sessionID string
+	var sessionID, protocolVersion string
+	var state *sessionState
 	if isInitializeRequest {
 		// generate a new one for initialize request
 		sessionID = s.sessionIdManager.Generate()
+		if sessionID != "" {
+			state = s.startSession(sessionID, AuthInfoFromContext(r.Context()))
+		}
 	} else {
 		// Get session ID from header.
 		// Stateful servers need the client to carry the session ID.
@@ -257,12 +385,43 @@ func (s *StreamableHTTPServer) handlePost(w http.ResponseWriter, r *http.Request
 			http.Error(w, "Session terminated", http.StatusNotFound)
 			return
 		}
+		protocolVersion, err = s.requestProtocolVersion(r, sessionID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if isBatch && !mcp.ProtocolVersionAllowsBatching(protocolVersion) {
+			s.writeJSONRPCError(w, nil, mcp.INVALID_REQUEST, batchingErrorMessage(protocolVersion))
+			return
+		}
+		state = s.acquireSession(sessionID)
+	}
+	if state != nil {
+		defer s.releaseSession(sessionID, state)
 	}
 
-	session := newStreamableHttpSession(sessionID, s.sessionTools, s.sessionLogLevels)
+	// Responses to requests sent to the client are routed to the waiting caller
+	if state != nil && state.requests.deliver(rawData) {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	session := s.newSession(sessionID, state)
+	if protocolVersion != "" {
+		session.protocolVersion.Store(protocolVersion)
+		w.Header().Set(headerKeyProtocolVersion, protocolVersion)
+	}
+
+	// With resumable streams, the request is processed to completion even if
+	// the client drops the stream, as the client can replay the response
+	resumable := s.eventStore != nil && sessionID != ""
+	baseCtx := r.Context()
+	if resumable {
+		baseCtx = context.WithoutCancel(baseCtx)
+	}
 
 	// Set the client context before handling the message
-	ctx := s.server.WithContext(r.Context(), session)
+	ctx := s.server.WithContext(baseCtx, session)
 	if s.contextFunc != nil {
 		ctx = s.contextFunc(ctx, r)
 	}
@@ -270,43 +429,70 @@ func (s *StreamableHTTPServer) handlePost(w http.ResponseWriter, r *http.Request
 	// handle potential notifications
 	mu := sync.Mutex{}
 	upgradedHeader := false
+	var streamID string
 	done := make(chan struct{})
+	if resumable {
+		streamID = newStreamID(sessionID)
+		live := &liveStream{}
+		s.liveStreams.Store(streamID, live)
+		defer s.closeLiveStream(streamID, live)
+	}
 
 	ctx = context.WithValue(ctx, requestHeader, r.Header)
+
+	// logError logs an error along with the attributes identifying the
+	// session and the message
+	logError := func(msg string, err error) {
+		attrs := append(messageLogAttrs(json.RawMessage(rawData)), logKeySessionID, sessionID, logKeyStreamID, streamID, logKeyError, err)
+		s.logger.Error(msg, attrs...)
+	}
+
+	// writeEvent writes a notification or a request to the client on the
+	// response stream, upgrading the response to SSE if needed
+	writeEvent := func(data any) {
+		mu.Lock()
+		defer mu.Unlock()
+		// if the done chan is closed, as the request is terminated, just return
+		select {
+		case <-done:
+			return
+		default:
+		}
+		defer func() {
+			flusher, ok := w.(http.Flusher)
+			if ok {
+				flusher.Flush()
+			}
+		}()
+
+		// if there's notifications, upgradedHeader to SSE response
+		if !upgradedHeader {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Header().Set("Connection", "keep-alive")
+			w.Header().Set("Cache-Control", "no-cache")
+			w.WriteHeader(http.StatusOK)
+			upgradedHeader = true
+		}
+		err := s.writeStreamEvent(ctx, w, streamID, data)
+		if err != nil {
+			logError("Failed to write SSE event", err)
+			return
+		}
+	}
+	// forward notifications and requests until the message is handled; the
+	// ones still queued by then are written before the response
+	stopForwarding := make(chan struct{})
+	forwardingDone := make(chan struct{})
 	go func() {
+		defer close(forwardingDone)
 		for {
 			select {
 			case nt := <-session.notificationChannel:
-				func() {
-					mu.Lock()
-					defer mu.Unlock()
-					// if the done chan is closed, as the request is terminated, just return
-					select {
-					case <-done:
-						return
-					default:
-					}
-					defer func() {
-						flusher, ok := w.(http.Flusher)
-						if ok {
-							flusher.Flush()
-						}
-					}()
-
-					// if there's notifications, upgradedHeader to SSE response
-					if !upgradedHeader {
-						w.Header().Set("Content-Type", "text/event-stream")
-						w.Header().Set("Connection", "keep-alive")
-						w.Header().Set("Cache-Control", "no-cache")
-						w.WriteHeader(http.StatusOK)
-						upgradedHeader = true
-					}
-					err := writeSSEEvent(w, nt)
-					if err != nil {
-						s.logger.Errorf("Failed to write SSE event: %v", err)
-						return
-					}
-				}()
+				writeEvent(nt)
+			case req := <-session.requests:
+				writeEvent(req)
+			case <-stopForwarding:
+				return
 			case <-done:
 				return
 			case <-ctx.Done():
@@ -317,9 +503,20 @@ func (s *StreamableHTTPServer) handlePost(w http.ResponseWriter, r *http.Request
 
 	// Process message through MCPServer
 	response := s.server.HandleMessage(ctx, rawData)
+	// Requests sent from now on go out on the listening stream, while the
+	// queued ones are written before the response
+	session.closeStream()
+	close(stopForwarding)
+	<-forwardingDone
 	if response == nil {
-		// For notifications, just send 202 Accepted with no body
-		w.WriteHeader(http.StatusAccepted)
+		mu.Lock()
+		defer mu.Unlock()
+		defer close(done)
+		// For notifications and cancelled requests, just send 202 Accepted with
+		// no body, unless the response was already upgraded to an SSE stream
+		if !upgradedHeader {
+			w.WriteHeader(http.StatusAccepted)
+		}
 		return
 	}
 
@@ -340,8 +537,33 @@ func (s *StreamableHTTPServer) handlePost(w http.ResponseWriter, r *http.Request
 			w.WriteHeader(http.StatusOK)
 			upgradedHeader = true
 		}
-		if err := writeSSEEvent(w, response); err != nil {
-			s.logger.Errorf("Failed to write final SSE response event: %v", err)
+		// Write queued notifications, e.g. the final progress update, and
+		// requests before the response ends the stream
+	drain:
+		for {
+			select {
+			case nt := <-session.notificationChannel:
+				if err := s.writeStreamEvent(ctx, w, streamID, nt); err != nil {
+					logError("Failed to write SSE event", err)
+				}
+			case req := <-session.requests:
+				if err := s.writeStreamEvent(ctx, w, streamID, req); err != nil {
+					logError("Failed to write SSE event", err)
+				}
+			default:
+				break drain
+			}
+		}
+		// The responses of a batch are multiplexed over the stream, one
+		// event per response
+		responses, ok := response.([]mcp.JSONRPCMessage)
+		if !ok {
+			responses = []mcp.JSONRPCMessage{response}
+		}
+		for _, response := range responses {
+			if err := s.writeStreamEvent(ctx, w, streamID, response); err != nil {
+				logError("Failed to write final SSE response event", err)
+			}
 		}
 	} else {
 		w.Header().Set("Content-Type", "application/json")
@@ -349,10 +571,15 @@ func (s *StreamableHTTPServer) handlePost(w http.ResponseWriter, r *http.Request
 			// send the session ID back to the client
 			w.Header().Set(headerKeySessionID, sessionID)
 		}
+		if isInitializeRequest {
+			if version := session.GetProtocolVersion(); version != "" {
+				w.Header().Set(headerKeyProtocolVersion, version)
+			}
+		}
 		w.WriteHeader(http.StatusOK)
 		err := json.NewEncoder(w).Encode(response)
 		if err != nil {
-			s.logger.Errorf("Failed to write response: %v", err)
+			logError("Failed to write response", err)
 		}
 	}
 }
@@ -369,8 +596,56 @@ func (s *StreamableHTTPServer) handleGet(w http.ResponseWriter, r *http.Request)
 		// but the MCP server requires a unique ID for registering, so we use a random one
 		sessionID = uuid.New().String()
 	}
+	logger := s.logger.With(logKeySessionID, sessionID)
+
+	protocolVersion, err := s.requestProtocolVersion(r, r.Header.Get(headerKeySessionID))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set(headerKeyProtocolVersion, protocolVersion)
+
+	// Resuming a stream replays the events the client missed. The stream of
+	// a POST request is followed until the request is complete, while the
+	// standalone stream goes on as usual. Streams without a session are not
+	// stored, since they could be replayed by anyone.
+	var streamID string
+	var missed []storedEvent
+	if s.eventStore != nil && r.Header.Get(headerKeySessionID) != "" {
+		streamID = standaloneStreamID(r.Header.Get(headerKeySessionID))
+	}
+	if lastEventID := r.Header.Get(headerKeyLastEventID); lastEventID != "" && s.eventStore != nil {
+		if streamID == "" {
+			http.Error(w, "Resuming a stream requires a session ID", http.StatusBadRequest)
+			return
+		}
+		replayedStreamID, events, err := s.replayEvents(r, lastEventID)
+		switch {
+		case err != nil:
+			logger.Error("Failed to replay events", logKeyEventID, lastEventID, logKeyError, err)
+		case replayedStreamID != streamID:
+			s.resumePostStream(w, r, replayedStreamID, events)
+			return
+		default:
+			missed = events
+		}
+	}
 
-	session := newStreamableHttpSession(sessionID, s.sessionTools, s.sessionLogLevels)
+	session := newStreamableHttpSession(sessionID, s.sessionTools, s.sessionPrimitives, s.sessionLogLevels, s.sessionProtocolVersions)
+	session.protocolVersion.Store(protocolVersion)
+	if r.Header.Get(headerKeySessionID) != "" {
+		// The session doesn't expire while the client listens
+		if session.state = s.acquireSession(sessionID); session.state != nil {
+			defer s.releaseSession(sessionID, session.state)
+		}
+		s.enableClientRequests(session)
+	}
+	// Heartbeats share the request IDs of the session, if it can send requests
+	requestIDs := session.requestIDs
+	if requestIDs == nil {
+		requestIDs = new(atomic.Int64)
+	}
 	if err := s.server.RegisterSession(r.Context(), session); err != nil {
 		http.Error(w, fmt.Sprintf("Session registration failed: %v", err), http.StatusBadRequest)
 		return
@@ -388,6 +663,12 @@ func (s *StreamableHTTPServer) handleGet(w http.ResponseWriter, r *http.Request)
 		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
 		return
 	}
+	for _, event := range missed {
+		if err := writeStoredSSEEvent(w, event.id, event.message); err != nil {
+			logger.Error("Failed to write replayed SSE event", logKeyEventID, event.id, logKeyError, err)
+			return
+		}
+	}
 	flusher.Flush()
 
 	// Start notification handler for this session
@@ -404,6 +685,12 @@ func (s *StreamableHTTPServer) handleGet(w http.ResponseWriter, r *http.Request)
 				case <-done:
 					return
 				}
+			case req := <-session.requests:
+				select {
+				case writeChan <- req:
+				case <-done:
+					return
+				}
 			case <-done:
 				return
 			}
@@ -420,7 +707,7 @@ func (s *StreamableHTTPServer) handleGet(w http.ResponseWriter, r *http.Request)
 				case <-ticker.C:
 					message := mcp.JSONRPCRequest{
 						JSONRPC: "2.0",
-						ID:      mcp.NewRequestId(s.nextRequestID(sessionID)),
+						ID:      mcp.NewRequestId(requestIDs.Add(1)),
 						Request: mcp.Request{
 							Method: "ping",
 						},
@@ -447,8 +734,8 @@ func (s *StreamableHTTPServer) handleGet(w http.ResponseWriter, r *http.Request)
 			if data == nil {
 				continue
 			}
-			if err := writeSSEEvent(w, data); err != nil {
-				s.logger.Errorf("Failed to write SSE event: %v", err)
+			if err := s.writeStreamEvent(r.Context(), w, streamID, data); err != nil {
+				logger.Error("Failed to write SSE event", logKeyStreamID, streamID, logKeyError, err)
 				return
 			}
 			flusher.Flush()
@@ -461,6 +748,10 @@ func (s *StreamableHTTPServer) handleGet(w http.ResponseWriter, r *http.Request)
 func (s *StreamableHTTPServer) handleDelete(w http.ResponseWriter, r *http.Request) {
 	// delete request terminate the session
 	sessionID := r.Header.Get(headerKeySessionID)
+	if _, err := s.requestProtocolVersion(r, sessionID); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	notAllowed, err := s.sessionIdManager.Terminate(sessionID)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Session termination failed: %v", err), http.StatusInternalServerError)
@@ -471,13 +762,107 @@ func (s *StreamableHTTPServer) handleDelete(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	s.endSession(sessionID)
+	w.WriteHeader(http.StatusOK)
+}
+
+// sessionState tracks the activity of a session initialized by the server,
+// which expires once it stays idle for the idle timeout.
+type sessionState struct {
+	mu     sync.Mutex
+	active int         // requests and streams of the session in progress
+	expiry *time.Timer // running while the session is idle
+	ended  bool
+
+	owner      *AuthInfo      // identity that initialized the session, with auth
+	requests   clientRequests // requests sent to the client awaiting a response
+	requestIDs atomic.Int64   // ID of the last request sent to the client
+}
+
+// startSession tracks a session initialized by the current request, on
+// behalf of owner if the server requires authorization.
+func (s *StreamableHTTPServer) startSession(sessionID string, owner *AuthInfo) *sessionState {
+	state := &sessionState{active: 1, owner: owner}
+	s.sessionStates.Store(sessionID, state)
+	return state
+}
+
+// acquireSession marks a session as active until releaseSession is called.
+// It returns nil for sessions that were not initialized by the server, or
+// that ended.
+func (s *StreamableHTTPServer) acquireSession(sessionID string) *sessionState {
+	value, ok := s.sessionStates.Load(sessionID)
+	if !ok {
+		return nil
+	}
+	state := value.(*sessionState)
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.ended {
+		return nil
+	}
+	state.active++
+	if state.expiry != nil {
+		state.expiry.Stop()
+	}
+	return state
+}
+
+// releaseSession ends an activity of the session, starting its idle timeout
+// once none is left.
+func (s *StreamableHTTPServer) releaseSession(sessionID string, state *sessionState) {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	state.active--
+	if state.active > 0 || state.ended || s.sessionIdleTimeout <= 0 {
+		return
+	}
+	if state.expiry == nil {
+		state.expiry = time.AfterFunc(s.sessionIdleTimeout, func() {
+			s.expireSession(sessionID, state)
+		})
+	} else {
+		state.expiry.Reset(s.sessionIdleTimeout)
+	}
+}
+
+// expireSession ends a session that stayed idle for the idle timeout, unless
+// it became active again in the meantime.
+func (s *StreamableHTTPServer) expireSession(sessionID string, state *sessionState) {
+	state.mu.Lock()
+	if state.active > 0 || state.ended {
+		state.mu.Unlock()
+		return
+	}
+	state.ended = true
+	state.mu.Unlock()
+
+	s.logger.Debug("Session expired", logKeySessionID, sessionID)
+	s.endSession(sessionID)
+}
+
+// endSession drops the state kept for a session, once the client deleted it
+// or it expired.
+func (s *StreamableHTTPServer) endSession(sessionID string) {
+	if value, ok := s.sessionStates.LoadAndDelete(sessionID); ok {
+		state := value.(*sessionState)
+		state.mu.Lock()
+		state.ended = true
+		if state.expiry != nil {
+			state.expiry.Stop()
+		}
+		state.mu.Unlock()
+		// fail the requests still waiting for a response from the client
+		state.requests.close(ErrSessionTerminated)
+	}
+
+	// drop the state the MCP server holds for the session
+	s.server.closeSession(sessionID)
 	// remove the session relateddata from the sessionToolsStore
 	s.sessionTools.delete(sessionID)
+	s.sessionPrimitives.delete(sessionID)
 	s.sessionLogLevels.delete(sessionID)
-	// remove current session's requstID information
-	s.sessionRequestIDs.Delete(sessionID)
-
-	w.WriteHeader(http.StatusOK)
+	s.sessionProtocolVersions.delete(sessionID)
 }
 
 func writeSSEEvent(w io.Writer, data any) error {
@@ -492,6 +877,182 @@ func writeSSEEvent(w io.Writer, data any) error {
 	return nil
 }
 
+// newStreamID generates the ID of the SSE stream of a POST request of the
+// given session.
+func newStreamID(sessionID string) string {
+	return sessionID + "_" + uuid.New().String()
+}
+
+// standaloneStreamID returns the ID of the SSE stream opened by GET requests
+// of the given session. A session has a single standalone stream, so a new
+// GET request goes on with the stream of the previous ones.
+func standaloneStreamID(sessionID string) string {
+	return sessionID + "_standalone"
+}
+
+// liveStream forwards the events of a POST stream being written to the GET
+// requests resuming it.
+type liveStream struct {
+	mu        sync.Mutex
+	closed    bool
+	listeners []*streamListener
+}
+
+type streamListener struct {
+	events chan storedEvent
+	done   chan struct{}
+}
+
+// closeLiveStream ends the GET requests following a POST stream, once its
+// request is complete.
+func (s *StreamableHTTPServer) closeLiveStream(streamID string, live *liveStream) {
+	s.liveStreams.Delete(streamID)
+	live.mu.Lock()
+	defer live.mu.Unlock()
+	live.closed = true
+	for _, listener := range live.listeners {
+		close(listener.events)
+	}
+	live.listeners = nil
+}
+
+// writeStreamEvent writes data as an SSE event of the given stream. With an
+// event store, the event of a resumable stream, i.e. with an ID, is stored
+// first and carries its event ID.
+func (s *StreamableHTTPServer) writeStreamEvent(ctx context.Context, w io.Writer, streamID string, data any) error {
+	if s.eventStore == nil || streamID == "" {
+		return writeSSEEvent(w, data)
+	}
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal data: %w", err)
+	}
+
+	// Storing and forwarding under the lock of the live stream guarantees
+	// that a resuming request either replays the event or receives it
+	var live *liveStream
+	if value, ok := s.liveStreams.Load(streamID); ok {
+		live = value.(*liveStream)
+		live.mu.Lock()
+	}
+	eventID, err := s.eventStore.StoreEvent(ctx, streamID, jsonData)
+	if err == nil && live != nil {
+		event := storedEvent{id: eventID, streamID: streamID, message: jsonData}
+		for _, listener := range live.listeners {
+			select {
+			case listener.events <- event:
+			case <-listener.done:
+			}
+		}
+	}
+	if live != nil {
+		live.mu.Unlock()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to store event: %w", err)
+	}
+	return writeStoredSSEEvent(w, eventID, jsonData)
+}
+
+func writeStoredSSEEvent(w io.Writer, eventID string, data json.RawMessage) error {
+	_, err := fmt.Fprintf(w, "id: %s\nevent: message\ndata: %s\n\n", eventID, data)
+	if err != nil {
+		return fmt.Errorf("failed to write SSE event: %w", err)
+	}
+	return nil
+}
+
+// replayEvents returns the events stored after lastEventID, along with the ID
+// of their stream. Events of streams belonging to another session are not
+// replayed.
+func (s *StreamableHTTPServer) replayEvents(r *http.Request, lastEventID string) (string, []storedEvent, error) {
+	var missed []storedEvent
+	streamID, err := s.eventStore.ReplayEventsAfter(r.Context(), lastEventID, func(eventID string, message json.RawMessage) error {
+		missed = append(missed, storedEvent{id: eventID, message: message})
+		return nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	if !strings.HasPrefix(streamID, r.Header.Get(headerKeySessionID)+"_") {
+		return "", nil, fmt.Errorf("stream %s belongs to another session", streamID)
+	}
+	for i := range missed {
+		missed[i].streamID = streamID
+	}
+	return streamID, missed, nil
+}
+
+// resumePostStream replays the missed events of the stream of a POST request,
+// then forwards its new events until the request is complete.
+func (s *StreamableHTTPServer) resumePostStream(w http.ResponseWriter, r *http.Request, streamID string, missed []storedEvent) {
+	logger := s.logger.With(logKeySessionID, r.Header.Get(headerKeySessionID), logKeyStreamID, streamID)
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	lastEventID := r.Header.Get(headerKeyLastEventID)
+	for _, event := range missed {
+		if err := writeStoredSSEEvent(w, event.id, event.message); err != nil {
+			logger.Error("Failed to write replayed SSE event", logKeyEventID, event.id, logKeyError, err)
+			return
+		}
+		lastEventID = event.id
+	}
+	flusher.Flush()
+
+	value, ok := s.liveStreams.Load(streamID)
+	if !ok {
+		// The request is already complete
+		return
+	}
+	live := value.(*liveStream)
+	listener := &streamListener{events: make(chan storedEvent, 16), done: make(chan struct{})}
+	defer close(listener.done)
+
+	// Events stored since the replay are written before listening, while the
+	// writer of the stream waits for the lock
+	live.mu.Lock()
+	if live.closed {
+		live.mu.Unlock()
+		return
+	}
+	_, err := s.eventStore.ReplayEventsAfter(r.Context(), lastEventID, func(eventID string, message json.RawMessage) error {
+		return writeStoredSSEEvent(w, eventID, message)
+	})
+	if err == nil {
+		live.listeners = append(live.listeners, listener)
+	}
+	live.mu.Unlock()
+	if err != nil {
+		logger.Error("Failed to replay events", logKeyEventID, lastEventID, logKeyError, err)
+		return
+	}
+	flusher.Flush()
+
+	for {
+		select {
+		case event, ok := <-listener.events:
+			if !ok {
+				return
+			}
+			if err := writeStoredSSEEvent(w, event.id, event.message); err != nil {
+				logger.Error("Failed to write SSE event", logKeyEventID, event.id, logKeyError, err)
+				return
+			}
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
 // writeJSONRPCError writes a JSON-RPC error response with the given error details.
 func (s *StreamableHTTPServer) writeJSONRPCError(
 	w http.ResponseWriter,
@@ -504,15 +1065,71 @@ func (s *StreamableHTTPServer) writeJSONRPCError(
 	w.WriteHeader(http.StatusBadRequest)
 	err := json.NewEncoder(w).Encode(response)
 	if err != nil {
-		s.logger.Errorf("Failed to write JSONRPCError: %v", err)
+		s.logger.Error("Failed to write JSONRPCError", logKeyRequestID, id, logKeyError, err)
+	}
+}
+
+// requestProtocolVersion returns the protocol version of a request to the
+// given session. The MCP-Protocol-Version header must name a supported
+// version matching the one negotiated by the session. Without the header,
+// the negotiated version is used, falling back to 2025-03-26.
+func (s *StreamableHTTPServer) requestProtocolVersion(r *http.Request, sessionID string) (string, error) {
+	var negotiated string
+	if sessionID != "" {
+		negotiated = s.sessionProtocolVersions.get(sessionID)
+	}
+
+	version := r.Header.Get(headerKeyProtocolVersion)
+	if version == "" {
+		if negotiated != "" {
+			return negotiated, nil
+		}
+		return defaultStreamableHTTPProtocolVersion, nil
+	}
+	if !slices.Contains(mcp.ValidProtocolVersions, version) {
+		return "", fmt.Errorf("unsupported protocol version: %s", version)
+	}
+	if negotiated != "" && negotiated != version {
+		return "", fmt.Errorf("protocol version %s does not match the negotiated version %s", version, negotiated)
 	}
+	return version, nil
 }
 
-// nextRequestID gets the next incrementing requestID for the current session
-func (s *StreamableHTTPServer) nextRequestID(sessionID string) int64 {
-	actual, _ := s.sessionRequestIDs.LoadOrStore(sessionID, new(atomic.Int64))
-	counter := actual.(*atomic.Int64)
-	return counter.Add(1)
+// newSession creates the ephemeral session of a POST request.
+func (s *StreamableHTTPServer) newSession(sessionID string, state *sessionState) *streamableHttpSession {
+	session := newStreamableHttpSession(sessionID, s.sessionTools, s.sessionPrimitives, s.sessionLogLevels, s.sessionProtocolVersions)
+	session.state = state
+	s.enableClientRequests(session)
+	return session
+}
+
+// enableClientRequests lets the session send requests to the client. The
+// pending requests are shared by all the streams of the session, so that a
+// response posted by the client reaches the caller waiting for it. Only
+// sessions initialized by the server can send requests: the responses to the
+// requests of stateless sessions could not be routed back to them, and the
+// state of other sessions would never be released.
+func (s *StreamableHTTPServer) enableClientRequests(session *streamableHttpSession) {
+	if session.state == nil {
+		return
+	}
+	session.pendingRequests = &session.state.requests
+	session.requestIDs = &session.state.requestIDs
+	session.requestTimeout = s.clientRequestTimeout
+	session.listeningSession = func() *streamableHttpSession {
+		return s.listeningSession(session.sessionID)
+	}
+}
+
+// listeningSession returns the session of the GET request listening for
+// messages from the server, if any.
+func (s *StreamableHTTPServer) listeningSession(sessionID string) *streamableHttpSession {
+	value, ok := s.server.sessions.Load(sessionID)
+	if !ok {
+		return nil
+	}
+	session, _ := value.(*streamableHttpSession)
+	return session
 }
 
 // --- session ---
@@ -549,6 +1166,35 @@ func (s *sessionLogLevelsStore) delete(sessionID string) {
 	delete(s.logs, sessionID)
 }
 
+type sessionProtocolVersionsStore struct {
+	mu       sync.RWMutex
+	versions map[string]string // sessionID -> negotiated protocol version
+}
+
+func newSessionProtocolVersionsStore() *sessionProtocolVersionsStore {
+	return &sessionProtocolVersionsStore{
+		versions: make(map[string]string),
+	}
+}
+
+func (s *sessionProtocolVersionsStore) get(sessionID string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.versions[sessionID]
+}
+
+func (s *sessionProtocolVersionsStore) set(sessionID string, version string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.versions[sessionID] = version
+}
+
+func (s *sessionProtocolVersionsStore) delete(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.versions, sessionID)
+}
+
 type sessionToolsStore struct {
 	mu    sync.RWMutex
 	tools map[string]map[string]ServerTool // sessionID -> toolName -> tool
@@ -578,6 +1224,48 @@ func (s *sessionToolsStore) delete(sessionID string) {
 	delete(s.tools, sessionID)
 }
 
+// sessionItemsStore keeps session-specific items, such as resources or
+// prompts, per session. The zero value is ready to use.
+type sessionItemsStore[T any] struct {
+	mu    sync.RWMutex
+	items map[string]map[string]T // sessionID -> name -> item
+}
+
+func (s *sessionItemsStore[T]) get(sessionID string) map[string]T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return maps.Clone(s.items[sessionID])
+}
+
+func (s *sessionItemsStore[T]) set(sessionID string, items map[string]T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.items == nil {
+		s.items = make(map[string]map[string]T)
+	}
+	s.items[sessionID] = items
+}
+
+func (s *sessionItemsStore[T]) delete(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.items, sessionID)
+}
+
+// sessionPrimitivesStore keeps the session-specific resources, resource
+// templates and prompts of the streamable-http sessions.
+type sessionPrimitivesStore struct {
+	resources         sessionItemsStore[ServerResource]
+	resourceTemplates sessionItemsStore[ServerResourceTemplate]
+	prompts           sessionItemsStore[ServerPrompt]
+}
+
+func (s *sessionPrimitivesStore) delete(sessionID string) {
+	s.resources.delete(sessionID)
+	s.resourceTemplates.delete(sessionID)
+	s.prompts.delete(sessionID)
+}
+
 // streamableHttpSession is a session for streamable-http transport
 // When in POST handlers(request/notification), it's ephemeral, and only exists in the life of the request handler.
 // When in GET handlers(listening), it's a real session, and will be registered in the MCP server.
@@ -585,16 +1273,31 @@ type streamableHttpSession struct {
 	sessionID           string
 	notificationChannel chan mcp.JSONRPCNotification // server -> client notifications
 	tools               *sessionToolsStore
+	primitives          *sessionPrimitivesStore
 	upgradeToSSE        atomic.Bool
 	logLevels           *sessionLogLevelsStore
+	protocolVersions    *sessionProtocolVersionsStore
+	protocolVersion     atomic.Value            // protocol version of the current request
+	requests            chan mcp.JSONRPCRequest // server -> client requests
+	pendingRequests     *clientRequests         // nil if the session can't send requests
+	requestIDs          *atomic.Int64
+	requestTimeout      time.Duration
+	listeningSession    func() *streamableHttpSession
+	state               *sessionState // nil if the session was not initialized by the server
+
+	streamMu     sync.Mutex
+	streamClosed bool // whether the stream of the POST request has ended
 }
 
-func newStreamableHttpSession(sessionID string, toolStore *sessionToolsStore, levels *sessionLogLevelsStore) *streamableHttpSession {
+func newStreamableHttpSession(sessionID string, toolStore *sessionToolsStore, primitives *sessionPrimitivesStore, levels *sessionLogLevelsStore, versions *sessionProtocolVersionsStore) *streamableHttpSession {
 	s := &streamableHttpSession{
 		sessionID:           sessionID,
 		notificationChannel: make(chan mcp.JSONRPCNotification, 100),
+		requests:            make(chan mcp.JSONRPCRequest, 10),
 		tools:               toolStore,
+		primitives:          primitives,
 		logLevels:           levels,
+		protocolVersions:    versions,
 	}
 	return s
 }
@@ -635,9 +1338,64 @@ func (s *streamableHttpSession) SetSessionTools(tools map[string]ServerTool) {
 	s.tools.set(s.sessionID, tools)
 }
 
+func (s *streamableHttpSession) GetSessionResources() map[string]ServerResource {
+	return s.primitives.resources.get(s.sessionID)
+}
+
+func (s *streamableHttpSession) SetSessionResources(resources map[string]ServerResource) {
+	s.primitives.resources.set(s.sessionID, resources)
+}
+
+func (s *streamableHttpSession) GetSessionResourceTemplates() map[string]ServerResourceTemplate {
+	return s.primitives.resourceTemplates.get(s.sessionID)
+}
+
+func (s *streamableHttpSession) SetSessionResourceTemplates(templates map[string]ServerResourceTemplate) {
+	s.primitives.resourceTemplates.set(s.sessionID, templates)
+}
+
+func (s *streamableHttpSession) GetSessionPrompts() map[string]ServerPrompt {
+	return s.primitives.prompts.get(s.sessionID)
+}
+
+func (s *streamableHttpSession) SetSessionPrompts(prompts map[string]ServerPrompt) {
+	s.primitives.prompts.set(s.sessionID, prompts)
+}
+
+// GetProtocolVersion returns the version negotiated by the session, or the
+// version of the current request for stateless sessions.
+func (s *streamableHttpSession) GetProtocolVersion() string {
+	if s.sessionID != "" {
+		if version := s.protocolVersions.get(s.sessionID); version != "" {
+			return version
+		}
+	}
+	if version, ok := s.protocolVersion.Load().(string); ok {
+		return version
+	}
+	return ""
+}
+
+func (s *streamableHttpSession) SetProtocolVersion(version string) {
+	s.protocolVersion.Store(version)
+	if s.sessionID != "" {
+		s.protocolVersions.set(s.sessionID, version)
+	}
+}
+
+// keepsState reports whether the session was initialized by the server,
+// which tracks when it ends.
+func (s *streamableHttpSession) keepsState() bool {
+	return s.state != nil
+}
+
 var (
-	_ SessionWithTools   = (*streamableHttpSession)(nil)
-	_ SessionWithLogging = (*streamableHttpSession)(nil)
+	_ SessionWithTools           = (*streamableHttpSession)(nil)
+	_ SessionWithResources       = (*streamableHttpSession)(nil)
+	_ SessionWithPrompts         = (*streamableHttpSession)(nil)
+	_ SessionWithLogging         = (*streamableHttpSession)(nil)
+	_ SessionWithProtocolVersion = (*streamableHttpSession)(nil)
+	_ sessionWithState           = (*streamableHttpSession)(nil)
 )
 
 func (s *streamableHttpSession) UpgradeToSSEWhenReceiveNotification() {
@@ -646,6 +1404,98 @@ func (s *streamableHttpSession) UpgradeToSSEWhenReceiveNotification() {
 
 var _ SessionWithStreamableHTTPConfig = (*streamableHttpSession)(nil)
 
+// sendRequest sends a request to the client on the session's stream and waits
+// for the response, which the client posts in a separate request.
+func (s *streamableHttpSession) sendRequest(ctx context.Context, method mcp.MCPMethod, params any) (json.RawMessage, error) {
+	if s.pendingRequests == nil {
+		return nil, fmt.Errorf("%s requests are only supported by sessions initialized by this server", method)
+	}
+
+	if s.requestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.requestTimeout)
+		defer cancel()
+	}
+
+	id := s.requestIDs.Add(1)
+	response, err := s.pendingRequests.send(ctx, id, method, params, s.writeRequest(ctx))
+	if errors.Is(err, context.DeadlineExceeded) {
+		return nil, fmt.Errorf("%s request timed out: %w", method, err)
+	}
+	return response, err
+}
+
+// writeRequest returns a function queuing a request on the stream of the
+// session, or on the listening stream once the stream of the POST request
+// has ended.
+func (s *streamableHttpSession) writeRequest(ctx context.Context) func(mcp.JSONRPCRequest) error {
+	return func(request mcp.JSONRPCRequest) error {
+		// The stream is not closed while a request is being queued, so that
+		// the request is either forwarded or written before the response
+		s.streamMu.Lock()
+		if !s.streamClosed {
+			defer s.streamMu.Unlock()
+			// the response to the current request must then be sent as SSE too
+			s.UpgradeToSSEWhenReceiveNotification()
+			return queueRequest(ctx, s.requests, request)
+		}
+		s.streamMu.Unlock()
+
+		listening := s.listeningSession()
+		if listening == nil || listening == s {
+			return fmt.Errorf("no stream available to send the request")
+		}
+		return queueRequest(ctx, listening.requests, request)
+	}
+}
+
+func queueRequest(ctx context.Context, requests chan<- mcp.JSONRPCRequest, request mcp.JSONRPCRequest) error {
+	select {
+	case requests <- request:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// closeStream marks the stream of the POST request as ended.
+func (s *streamableHttpSession) closeStream() {
+	s.streamMu.Lock()
+	defer s.streamMu.Unlock()
+	s.streamClosed = true
+}
+
+// deliverResponse routes a response posted by the client to the request
+// waiting for it.
+func (s *streamableHttpSession) deliverResponse(message json.RawMessage) bool {
+	if s.pendingRequests == nil {
+		return false
+	}
+	return s.pendingRequests.deliver(message)
+}
+
+// RequestSampling sends a sampling request to the client and waits for the response.
+func (s *streamableHttpSession) RequestSampling(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	return requestClient[mcp.CreateMessageResult](ctx, s.sendRequest, mcp.MethodSamplingCreateMessage, request.CreateMessageParams)
+}
+
+// ListRoots sends a roots/list request to the client and waits for the response.
+func (s *streamableHttpSession) ListRoots(ctx context.Context, request mcp.ListRootsRequest) (*mcp.ListRootsResult, error) {
+	return requestClient[mcp.ListRootsResult](ctx, s.sendRequest, mcp.MethodListRoots, request.Params)
+}
+
+// RequestElicitation sends an elicitation request to the client and waits for the response.
+func (s *streamableHttpSession) RequestElicitation(ctx context.Context, request mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+	return requestClient[mcp.ElicitResult](ctx, s.sendRequest, mcp.MethodElicitationCreate, request.Params)
+}
+
+var (
+	_ SessionWithSampling        = (*streamableHttpSession)(nil)
+	_ SessionWithRoots           = (*streamableHttpSession)(nil)
+	_ SessionWithElicitation     = (*streamableHttpSession)(nil)
+	_ sessionWithClientResponses = (*streamableHttpSession)(nil)
+)
+
 // --- session id manager ---
 
 type SessionIdManager interface {
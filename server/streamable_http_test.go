@@ -902,3 +902,407 @@ func postJSON(url string, bodyObject any) (*http.Response, error) {
 	req.Header.Set("Content-Type", "application/json")
 	return http.DefaultClient.Do(req)
 }
+
+// postSessionJSON posts a message within the given session.
+func postSessionJSON(url, sessionID string, bodyObject any) (*http.Response, error) {
+	jsonBody, _ := json.Marshal(bodyObject)
+	req, _ := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(headerKeySessionID, sessionID)
+	return http.DefaultClient.Do(req)
+}
+
+// deleteSession terminates the given session.
+func deleteSession(url, sessionID string) (*http.Response, error) {
+	req, _ := http.NewRequest(http.MethodDelete, url, nil)
+	req.Header.Set(headerKeySessionID, sessionID)
+	return http.DefaultClient.Do(req)
+}
+
+func TestStreamableHTTP_POST_Progress(t *testing.T) {
+	mcpServer := NewMCPServer("test-mcp-server", "1.0", WithProgressInterval(time.Hour))
+	mcpServer.AddTool(mcp.NewTool("progressTool"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		for i := 1; i <= 5; i++ {
+			ProgressFromContext(ctx).Report(float64(i), 5, "")
+		}
+		return mcp.NewToolResultText("done"), nil
+	})
+	server := NewTestStreamableHTTPServer(mcpServer, WithStateLess(true))
+	defer server.Close()
+
+	callToolRequest := map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params": map[string]any{
+			"name":  "progressTool",
+			"_meta": map[string]any{"progressToken": "token-1"},
+		},
+	}
+	resp, err := postJSON(server.URL, callToolRequest)
+	if err != nil {
+		t.Fatalf("Failed to send message: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.Header.Get("content-type") != "text/event-stream" {
+		t.Errorf("Expected content-type text/event-stream, got %s", resp.Header.Get("content-type"))
+	}
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Failed to read response: %v", err)
+	}
+
+	// the burst is coalesced into the first and the last report
+	if count := strings.Count(string(responseBody), mcp.MethodNotificationProgress); count != 2 {
+		t.Errorf("Expected 2 progress notifications, got %d: %s", count, string(responseBody))
+	}
+	lines := strings.Split(strings.TrimSpace(string(responseBody)), "\n")
+	lastLine := lines[len(lines)-1]
+	if !strings.Contains(lastLine, "done") {
+		t.Errorf("Expected the response after the progress notifications, got %s", lastLine)
+	}
+}
+
+func TestStreamableHTTP_ProtocolVersion(t *testing.T) {
+	mcpServer := NewMCPServer("test-mcp-server", "1.0")
+	mcpServer.AddTool(mcp.NewTool("version"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		session := ClientSessionFromContext(ctx).(SessionWithProtocolVersion)
+		return mcp.NewToolResultText(session.GetProtocolVersion()), nil
+	})
+	server := NewTestStreamableHTTPServer(mcpServer)
+	defer server.Close()
+
+	post := func(t *testing.T, body any, headers map[string]string) *http.Response {
+		t.Helper()
+		jsonBody, _ := json.Marshal(body)
+		req, _ := http.NewRequest(http.MethodPost, server.URL, bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Failed to send message: %v", err)
+		}
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	resp := post(t, map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "initialize",
+		"params": map[string]any{
+			"protocolVersion": "2025-06-18",
+			"clientInfo":      map[string]any{"name": "test-client", "version": "1.0.0"},
+		},
+	}, nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+	if got := resp.Header.Get(headerKeyProtocolVersion); got != "2025-06-18" {
+		t.Errorf("Expected protocol version header 2025-06-18, got %q", got)
+	}
+	sessionID := resp.Header.Get(headerKeySessionID)
+	if sessionID == "" {
+		t.Fatal("Expected session id in header")
+	}
+
+	callVersion := map[string]any{
+		"jsonrpc": "2.0",
+		"id":      2,
+		"method":  "tools/call",
+		"params":  map[string]any{"name": "version"},
+	}
+
+	t.Run("negotiated version is stored on the session", func(t *testing.T) {
+		for _, headers := range []map[string]string{
+			{headerKeySessionID: sessionID, headerKeyProtocolVersion: "2025-06-18"},
+			{headerKeySessionID: sessionID},
+		} {
+			resp := post(t, callVersion, headers)
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+			}
+			var response jsonRPCResponse
+			if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if content := fmt.Sprint(response.Result["content"]); !strings.Contains(content, "2025-06-18") {
+				t.Errorf("Expected the tool to see version 2025-06-18, got %s", content)
+			}
+		}
+	})
+
+	t.Run("unsupported version is rejected", func(t *testing.T) {
+		resp := post(t, callVersion, map[string]string{
+			headerKeySessionID:       sessionID,
+			headerKeyProtocolVersion: "1999-01-01",
+		})
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("version not matching the negotiated one is rejected", func(t *testing.T) {
+		resp := post(t, callVersion, map[string]string{
+			headerKeySessionID:       sessionID,
+			headerKeyProtocolVersion: "2025-03-26",
+		})
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("batches are rejected", func(t *testing.T) {
+		resp := post(t, []any{callVersion}, map[string]string{
+			headerKeySessionID:       sessionID,
+			headerKeyProtocolVersion: "2025-06-18",
+		})
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", resp.StatusCode)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		if !strings.Contains(string(body), "not supported in protocol version 2025-06-18") {
+			t.Errorf("Unexpected error body: %s", body)
+		}
+	})
+}
+
+func TestStreamableHTTP_POST_Batch(t *testing.T) {
+	mcpServer := NewMCPServer("test-mcp-server", "1.0")
+	mcpServer.AddTool(mcp.NewTool("notify"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if err := ServerFromContext(ctx).SendNotificationToClient(ctx, "test/notification", nil); err != nil {
+			return nil, err
+		}
+		return mcp.NewToolResultText("notified"), nil
+	})
+	server := NewTestStreamableHTTPServer(mcpServer)
+	defer server.Close()
+
+	resp, err := postJSON(server.URL, initRequest)
+	if err != nil {
+		t.Fatalf("Failed to send message: %v", err)
+	}
+	resp.Body.Close()
+	sessionID := resp.Header.Get(headerKeySessionID)
+
+	postBatch := func(t *testing.T, batch []any) *http.Response {
+		t.Helper()
+		jsonBody, _ := json.Marshal(batch)
+		req, _ := http.NewRequest(http.MethodPost, server.URL, bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(headerKeySessionID, sessionID)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Failed to send message: %v", err)
+		}
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	t.Run("responses are returned as a JSON array", func(t *testing.T) {
+		resp := postBatch(t, []any{
+			map[string]any{"jsonrpc": "2.0", "id": 1, "method": "ping"},
+			map[string]any{"jsonrpc": "2.0", "method": "notifications/initialized"},
+			map[string]any{"jsonrpc": "2.0", "id": 2, "method": "tools/list"},
+		})
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+		}
+		if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Expected content type application/json, got %s", ct)
+		}
+		var responses []jsonRPCResponse
+		if err := json.NewDecoder(resp.Body).Decode(&responses); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if len(responses) != 2 || responses[0].ID != 1 || responses[1].ID != 2 {
+			t.Errorf("Expected responses to requests 1 and 2, got %+v", responses)
+		}
+	})
+
+	t.Run("responses are multiplexed over SSE", func(t *testing.T) {
+		resp := postBatch(t, []any{
+			map[string]any{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": map[string]any{"name": "notify"}},
+			map[string]any{"jsonrpc": "2.0", "id": 2, "method": "ping"},
+		})
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+		}
+		if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+			t.Fatalf("Expected content type text/event-stream, got %s", ct)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		events := strings.Count(string(body), "event: message")
+		if events != 3 {
+			t.Errorf("Expected a notification and two responses, got %d events: %s", events, body)
+		}
+		if !strings.Contains(string(body), `"id":1`) || !strings.Contains(string(body), `"id":2`) {
+			t.Errorf("Expected responses to requests 1 and 2: %s", body)
+		}
+	})
+}
+
+func TestStreamableHTTP_Resumability(t *testing.T) {
+	release := make(chan struct{})
+	mcpServer := NewMCPServer("test-mcp-server", "1.0")
+	mcpServer.AddTool(mcp.NewTool("notify"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		srv := ServerFromContext(ctx)
+		if err := srv.SendNotificationToClient(ctx, "test/first", nil); err != nil {
+			return nil, err
+		}
+		if err := srv.SendNotificationToClient(ctx, "test/second", nil); err != nil {
+			return nil, err
+		}
+		if req.GetBool("wait", false) {
+			<-release
+		}
+		return mcp.NewToolResultText("done"), nil
+	})
+	server := NewTestStreamableHTTPServer(mcpServer, WithEventStore(NewInMemoryEventStore(0)))
+	defer server.Close()
+
+	resp, err := postJSON(server.URL, initRequest)
+	if err != nil {
+		t.Fatalf("Failed to send message: %v", err)
+	}
+	resp.Body.Close()
+	sessionID := resp.Header.Get(headerKeySessionID)
+
+	callTool := func(t *testing.T, wait bool) *http.Response {
+		t.Helper()
+		jsonBody, _ := json.Marshal(map[string]any{
+			"jsonrpc": "2.0",
+			"id":      1,
+			"method":  "tools/call",
+			"params":  map[string]any{"name": "notify", "arguments": map[string]any{"wait": wait}},
+		})
+		req, _ := http.NewRequest(http.MethodPost, server.URL, bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(headerKeySessionID, sessionID)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Failed to send message: %v", err)
+		}
+		if resp.Header.Get("Content-Type") != "text/event-stream" {
+			t.Fatalf("Expected content-type text/event-stream, got %s", resp.Header.Get("Content-Type"))
+		}
+		return resp
+	}
+	resume := func(t *testing.T, lastEventID string) string {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		req.Header.Set(headerKeySessionID, sessionID)
+		req.Header.Set(headerKeyLastEventID, lastEventID)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Failed to send message: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+		}
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("Failed to read response: %v", err)
+		}
+		return string(body)
+	}
+	readEventID := func(t *testing.T, reader *bufio.Reader) string {
+		t.Helper()
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				t.Fatalf("Failed to read event: %v", err)
+			}
+			if id, ok := strings.CutPrefix(line, "id: "); ok {
+				return strings.TrimSpace(id)
+			}
+		}
+	}
+
+	t.Run("events of a complete stream are replayed", func(t *testing.T) {
+		resp := callTool(t, false)
+		defer resp.Body.Close()
+		firstEventID := readEventID(t, bufio.NewReader(resp.Body))
+
+		body := resume(t, firstEventID)
+		if strings.Contains(body, "test/first") {
+			t.Errorf("Expected the events after the last event ID only: %s", body)
+		}
+		if !strings.Contains(body, "test/second") || !strings.Contains(body, "done") {
+			t.Errorf("Expected the missed notification and the response: %s", body)
+		}
+		if strings.Count(body, "id: ") != 2 {
+			t.Errorf("Expected replayed events to carry their ID: %s", body)
+		}
+	})
+
+	t.Run("a stream being written is followed until its response", func(t *testing.T) {
+		resp := callTool(t, true)
+		firstEventID := readEventID(t, bufio.NewReader(resp.Body))
+		resp.Body.Close()
+
+		time.AfterFunc(50*time.Millisecond, func() { close(release) })
+		body := resume(t, firstEventID)
+		if !strings.Contains(body, "test/second") || !strings.Contains(body, "done") {
+			t.Errorf("Expected the missed notification and the response: %s", body)
+		}
+	})
+
+	t.Run("events of another session are not replayed", func(t *testing.T) {
+		resp := callTool(t, false)
+		defer resp.Body.Close()
+		firstEventID := readEventID(t, bufio.NewReader(resp.Body))
+
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		req.Header.Set(headerKeyLastEventID, firstEventID)
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		getResp, err := http.DefaultClient.Do(req.WithContext(ctx))
+		if err != nil {
+			t.Fatalf("Failed to send message: %v", err)
+		}
+		defer getResp.Body.Close()
+		body, _ := io.ReadAll(getResp.Body)
+		if strings.Contains(string(body), "test/second") {
+			t.Errorf("Expected no replayed events: %s", body)
+		}
+	})
+
+	t.Run("streams without a session are not resumable", func(t *testing.T) {
+		statelessServer := NewTestStreamableHTTPServer(mcpServer, WithStateLess(true), WithEventStore(NewInMemoryEventStore(0)))
+		defer statelessServer.Close()
+
+		resp, err := postJSON(statelessServer.URL, map[string]any{
+			"jsonrpc": "2.0",
+			"id":      1,
+			"method":  "tools/call",
+			"params":  map[string]any{"name": "notify"},
+		})
+		if err != nil {
+			t.Fatalf("Failed to send message: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if !strings.Contains(string(body), "test/second") {
+			t.Fatalf("Expected the notifications on the stream: %s", body)
+		}
+		if strings.Contains(string(body), "id: ") {
+			t.Errorf("Expected events without ID: %s", body)
+		}
+
+		req, _ := http.NewRequest(http.MethodGet, statelessServer.URL, nil)
+		req.Header.Set(headerKeyLastEventID, "_standalone_1")
+		getResp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Failed to send message: %v", err)
+		}
+		getResp.Body.Close()
+		if getResp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", getResp.StatusCode)
+		}
+	})
+}
@@ -0,0 +1,172 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// errToolTimeout is the context cause of a tool call exceeding its timeout.
+var errToolTimeout = errors.New("tool call timed out")
+
+// WithToolTimeout sets the default timeout of tool calls, for the tools
+// whose ServerTool.Timeout is zero. When a call exceeds it, the handler
+// context is cancelled and the call returns a tool error result. The
+// OnToolCallTimeout hooks are notified.
+func WithToolTimeout(timeout time.Duration) ServerOption {
+	return func(s *MCPServer) {
+		s.toolTimeout = timeout
+	}
+}
+
+// WithToolMaxConcurrency sets the default maximum number of concurrent calls
+// of each tool, across all sessions, for the tools whose
+// ServerTool.MaxConcurrency is zero.
+func WithToolMaxConcurrency(limit int) ServerOption {
+	return func(s *MCPServer) {
+		s.toolMaxConcurrency = limit
+	}
+}
+
+// WithToolQueueTimeout sets how long a tool call over the tool's concurrency
+// limit waits for another call to finish. Calls still waiting after it, or
+// all calls over the limit if it is zero, are rejected with a SERVER_BUSY
+// error wrapping ErrToolConcurrencyLimit.
+func WithToolQueueTimeout(timeout time.Duration) ServerOption {
+	return func(s *MCPServer) {
+		s.toolQueueTimeout = timeout
+	}
+}
+
+// toolLimiter bounds the number of concurrent calls of a tool. A limit of
+// zero or less means no limit.
+type toolLimiter struct {
+	limit int
+	slots chan struct{}
+}
+
+func newToolLimiter(limit int) *toolLimiter {
+	limiter := &toolLimiter{limit: limit}
+	if limit > 0 {
+		limiter.slots = make(chan struct{}, limit)
+	}
+	return limiter
+}
+
+// toolMaxConcurrencyOf returns the concurrency limit of a tool.
+func (s *MCPServer) toolMaxConcurrencyOf(tool ServerTool) int {
+	if tool.MaxConcurrency != 0 {
+		return tool.MaxConcurrency
+	}
+	return s.toolMaxConcurrency
+}
+
+// acquireToolSlot waits for the tool to be below its concurrency limit and
+// returns a function releasing the slot taken by the call.
+func (s *MCPServer) acquireToolSlot(ctx context.Context, tool ServerTool) (func(), error) {
+	limiter := tool.limiter
+	if limiter == nil {
+		// Tools set on a session directly, with SetSessionTools, were not
+		// registered through the server and share a limiter by name
+		limit := s.toolMaxConcurrencyOf(tool)
+		s.toolLimitersMu.Lock()
+		limiter = s.toolLimiters[tool.Tool.Name]
+		if limiter == nil || limiter.limit != limit {
+			// Calls holding a slot of a previous limiter release it on their own
+			limiter = newToolLimiter(limit)
+			s.toolLimiters[tool.Tool.Name] = limiter
+		}
+		s.toolLimitersMu.Unlock()
+	}
+	if limiter.limit <= 0 {
+		return func() {}, nil
+	}
+
+	name, limit := tool.Tool.Name, limiter.limit
+
+	release := func() { <-limiter.slots }
+	select {
+	case limiter.slots <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	limitErr := fmt.Errorf("tool '%s' already has %d calls in progress: %w", name, limit, ErrToolConcurrencyLimit)
+	if s.toolQueueTimeout <= 0 {
+		return nil, limitErr
+	}
+	timer := time.NewTimer(s.toolQueueTimeout)
+	defer timer.Stop()
+	select {
+	case limiter.slots <- struct{}{}:
+		return release, nil
+	case <-timer.C:
+		return nil, limitErr
+	case <-ctx.Done():
+		return nil, context.Cause(ctx)
+	}
+}
+
+// callToolWithTimeout calls the handler, bounded by the tool's timeout, and
+// calls release once the handler returns. A handler still running when the
+// timeout expires is abandoned: its context is cancelled and the call returns
+// a tool error, but its slot is only released when it actually returns.
+func (s *MCPServer) callToolWithTimeout(
+	ctx context.Context,
+	id any,
+	tool ServerTool,
+	handler ToolHandlerFunc,
+	request mcp.CallToolRequest,
+	release func(),
+) (*mcp.CallToolResult, error) {
+	timeout := tool.Timeout
+	if timeout == 0 {
+		timeout = s.toolTimeout
+	}
+	if timeout <= 0 {
+		defer release()
+		return handler(ctx, request)
+	}
+
+	handlerCtx, cancel := context.WithTimeoutCause(ctx, timeout, errToolTimeout)
+	defer cancel()
+
+	type outcome struct {
+		result   *mcp.CallToolResult
+		err      error
+		panicked any
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		defer release()
+		var o outcome
+		defer func() {
+			if r := recover(); r != nil {
+				o.panicked = r
+			}
+			done <- o
+		}()
+		o.result, o.err = handler(handlerCtx, request)
+	}()
+
+	select {
+	case o := <-done:
+		if o.panicked != nil {
+			// Surface the panic in the request goroutine, as without a timeout
+			panic(o.panicked)
+		}
+		if o.err == nil || !errors.Is(context.Cause(handlerCtx), errToolTimeout) {
+			return o.result, o.err
+		}
+	case <-handlerCtx.Done():
+		if !errors.Is(context.Cause(handlerCtx), errToolTimeout) {
+			return nil, context.Cause(handlerCtx)
+		}
+	}
+
+	s.hooks.toolCallTimeout(ctx, id, &request, timeout)
+	return mcp.NewToolResultError(fmt.Sprintf("tool '%s' timed out after %s", tool.Tool.Name, timeout)), nil
+}
@@ -0,0 +1,186 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func callTool(server *MCPServer, name string) mcp.JSONRPCMessage {
+	return server.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"`+name+`"}}`))
+}
+
+func TestMCPServer_ToolTimeout(t *testing.T) {
+	var timeouts atomic.Int32
+	hooks := &Hooks{}
+	hooks.AddOnToolCallTimeout(func(ctx context.Context, id any, message *mcp.CallToolRequest, timeout time.Duration) {
+		assert.Equal(t, "slow", message.Params.Name)
+		assert.Equal(t, 20*time.Millisecond, timeout)
+		timeouts.Add(1)
+	})
+	server := NewMCPServer("test-server", "1.0.0", WithToolTimeout(20*time.Millisecond), WithHooks(hooks))
+
+	unblock := make(chan struct{})
+	defer close(unblock)
+	cancelled := make(chan struct{})
+	server.AddTool(mcp.NewTool("slow"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		<-ctx.Done()
+		close(cancelled)
+		// Ignore the cancellation for a while, like a handler stuck in a call
+		<-unblock
+		return mcp.NewToolResultText("done"), nil
+	})
+	server.AddTools(ServerTool{
+		Tool: mcp.NewTool("unbounded"),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			time.Sleep(40 * time.Millisecond)
+			return mcp.NewToolResultText("done"), ctx.Err()
+		},
+		Timeout: -1,
+	})
+
+	start := time.Now()
+	response := callTool(server, "slow")
+	assert.Less(t, time.Since(start), time.Second)
+	resp, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok, "unexpected response %#v", response)
+	result := resp.Result.(mcp.CallToolResult)
+	assert.True(t, result.IsError)
+	assert.Equal(t, "tool 'slow' timed out after 20ms", result.Content[0].(mcp.TextContent).Text)
+	assert.Equal(t, int32(1), timeouts.Load())
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("handler context was not cancelled")
+	}
+
+	response = callTool(server, "unbounded")
+	resp, ok = response.(mcp.JSONRPCResponse)
+	require.True(t, ok, "unexpected response %#v", response)
+	assert.False(t, resp.Result.(mcp.CallToolResult).IsError)
+}
+
+func TestMCPServer_ToolMaxConcurrency(t *testing.T) {
+	newServer := func(opts ...ServerOption) (*MCPServer, chan struct{}, chan struct{}) {
+		started := make(chan struct{}, 10)
+		unblock := make(chan struct{})
+		server := NewMCPServer("test-server", "1.0.0", opts...)
+		server.AddTools(ServerTool{
+			Tool: mcp.NewTool("expensive"),
+			Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				started <- struct{}{}
+				<-unblock
+				return mcp.NewToolResultText("done"), nil
+			},
+			MaxConcurrency: 1,
+		})
+		return server, started, unblock
+	}
+
+	t.Run("calls over the limit are rejected", func(t *testing.T) {
+		var limitErrors atomic.Int32
+		hooks := &Hooks{}
+		hooks.AddOnError(func(ctx context.Context, id any, method mcp.MCPMethod, message any, err error) {
+			if errors.Is(err, ErrToolConcurrencyLimit) {
+				limitErrors.Add(1)
+			}
+		})
+		server, started, unblock := newServer(WithHooks(hooks))
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, ok := callTool(server, "expensive").(mcp.JSONRPCResponse)
+			assert.True(t, ok)
+		}()
+		<-started
+
+		response := callTool(server, "expensive")
+		errResp, ok := response.(mcp.JSONRPCError)
+		require.True(t, ok, "unexpected response %#v", response)
+		assert.Equal(t, mcp.SERVER_BUSY, errResp.Error.Code)
+		assert.Equal(t, int32(1), limitErrors.Load())
+
+		close(unblock)
+		wg.Wait()
+
+		// The slot is free again
+		_, ok = callTool(server, "expensive").(mcp.JSONRPCResponse)
+		assert.True(t, ok)
+	})
+
+	t.Run("calls over the limit wait in the queue", func(t *testing.T) {
+		server, started, unblock := newServer(WithToolQueueTimeout(time.Second))
+
+		var wg sync.WaitGroup
+		for i := 0; i < 2; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				response := callTool(server, "expensive")
+				_, ok := response.(mcp.JSONRPCResponse)
+				assert.True(t, ok, "unexpected response %#v", response)
+			}()
+		}
+		<-started
+		select {
+		case <-started:
+			t.Fatal("second call ran despite the concurrency limit")
+		case <-time.After(50 * time.Millisecond):
+		}
+		close(unblock)
+		<-started
+		wg.Wait()
+	})
+
+	t.Run("session tools have their own limit", func(t *testing.T) {
+		server, started, unblock := newServer()
+		defer close(unblock)
+
+		session := &sessionTestClientWithTools{
+			sessionID:           "session-1",
+			notificationChannel: make(chan mcp.JSONRPCNotification, 10),
+			initialized:         true,
+		}
+		require.NoError(t, server.RegisterSession(context.Background(), session))
+		require.NoError(t, server.AddSessionTools("session-1", ServerTool{
+			Tool: mcp.NewTool("expensive"),
+			Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return mcp.NewToolResultText("session"), nil
+			},
+			MaxConcurrency: 1,
+		}))
+
+		go callTool(server, "expensive")
+		<-started
+
+		// The global tool is busy, but the session tool of the same name isn't
+		ctx := server.WithContext(context.Background(), session)
+		response := server.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"expensive"}}`))
+		resp, ok := response.(mcp.JSONRPCResponse)
+		require.True(t, ok, "unexpected response %#v", response)
+		assert.Equal(t, "session", resp.Result.(mcp.CallToolResult).Content[0].(mcp.TextContent).Text)
+	})
+
+	t.Run("queued calls time out", func(t *testing.T) {
+		server, started, unblock := newServer(WithToolQueueTimeout(20 * time.Millisecond))
+		defer close(unblock)
+
+		go callTool(server, "expensive")
+		<-started
+
+		errResp, ok := callTool(server, "expensive").(mcp.JSONRPCError)
+		require.True(t, ok)
+		assert.Equal(t, mcp.SERVER_BUSY, errResp.Error.Code)
+	})
+}
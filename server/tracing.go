@@ -0,0 +1,124 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/tracing"
+)
+
+// WithTracer traces the server with the given tracer. A span is started
+// around the handling of every request, and child spans around the tool,
+// resource and prompt handlers and the sampling requests sent to clients.
+//
+// The parent of the request span is the span context in the _meta field of
+// the request, or else the remote parent of the request context, set for
+// instance with tracing.ContextFromHTTPRequest as HTTP context function.
+// Requests sent to clients carry the current span context in their _meta
+// field.
+func WithTracer(tracer tracing.Tracer) ServerOption {
+	return func(s *MCPServer) {
+		s.tracer = tracer
+	}
+}
+
+// startRequestSpan starts the span of a request and returns a function
+// ending it with the response.
+func (s *MCPServer) startRequestSpan(
+	ctx context.Context,
+	id any,
+	method mcp.MCPMethod,
+	message json.RawMessage,
+) (context.Context, func(response mcp.JSONRPCMessage)) {
+	// The parent propagated in _meta takes precedence over the HTTP headers
+	if bytes.Contains(message, []byte(`"_meta"`)) {
+		var request struct {
+			Params struct {
+				Meta map[string]any `json:"_meta"`
+			} `json:"params"`
+		}
+		if err := json.Unmarshal(message, &request); err == nil {
+			if sc, ok := tracing.FromMeta(request.Params.Meta); ok {
+				ctx = tracing.ContextWithRemoteParent(ctx, sc)
+			}
+		}
+	}
+	if s.tracer == nil {
+		return ctx, func(mcp.JSONRPCMessage) {}
+	}
+
+	attrs := []tracing.Attribute{
+		{Key: "mcp.method.name", Value: string(method)},
+		{Key: "jsonrpc.request.id", Value: fmt.Sprint(id)},
+	}
+	if session := ClientSessionFromContext(ctx); session != nil {
+		attrs = append(attrs, tracing.Attribute{Key: "mcp.session.id", Value: session.SessionID()})
+	}
+	ctx, span := tracing.Start(ctx, s.tracer, string(method), attrs...)
+	return ctx, func(response mcp.JSONRPCMessage) {
+		if errResp, ok := response.(mcp.JSONRPCError); ok {
+			span.SetAttributes(tracing.Attribute{Key: "rpc.jsonrpc.error_code", Value: errResp.Error.Code})
+			span.RecordError(errors.New(errResp.Error.Message))
+		}
+		span.End()
+	}
+}
+
+// traceToolHandler wraps a tool handler in a span, if the server is traced.
+func (s *MCPServer) traceToolHandler(handler ToolHandlerFunc) ToolHandlerFunc {
+	if s.tracer == nil {
+		return handler
+	}
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx, span := tracing.Start(ctx, s.tracer, "tool "+request.Params.Name,
+			tracing.Attribute{Key: "mcp.tool.name", Value: request.Params.Name})
+		defer span.End()
+		result, err := handler(ctx, request)
+		if err != nil {
+			span.RecordError(err)
+		} else if result != nil && result.IsError {
+			span.SetAttributes(tracing.Attribute{Key: "mcp.tool.is_error", Value: true})
+		}
+		return result, err
+	}
+}
+
+// traceResourceHandler wraps a resource handler in a span, if the server is
+// traced.
+func (s *MCPServer) traceResourceHandler(handler ResourceHandlerFunc) ResourceHandlerFunc {
+	if s.tracer == nil {
+		return handler
+	}
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		ctx, span := tracing.Start(ctx, s.tracer, "resource "+request.Params.URI,
+			tracing.Attribute{Key: "mcp.resource.uri", Value: request.Params.URI})
+		defer span.End()
+		contents, err := handler(ctx, request)
+		if err != nil {
+			span.RecordError(err)
+		}
+		return contents, err
+	}
+}
+
+// tracePromptHandler wraps a prompt handler in a span, if the server is
+// traced.
+func (s *MCPServer) tracePromptHandler(handler PromptHandlerFunc) PromptHandlerFunc {
+	if s.tracer == nil {
+		return handler
+	}
+	return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		ctx, span := tracing.Start(ctx, s.tracer, "prompt "+request.Params.Name,
+			tracing.Attribute{Key: "mcp.prompt.name", Value: request.Params.Name})
+		defer span.End()
+		result, err := handler(ctx, request)
+		if err != nil {
+			span.RecordError(err)
+		}
+		return result, err
+	}
+}
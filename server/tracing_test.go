@@ -0,0 +1,200 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/tracing"
+)
+
+const testTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+type recordedSpan struct {
+	name   string
+	parent string
+	attrs  map[string]any
+	errors []error
+	ended  bool
+	sc     tracing.SpanContext
+}
+
+func (s *recordedSpan) SetAttributes(attrs ...tracing.Attribute) {
+	for _, attr := range attrs {
+		s.attrs[attr.Key] = attr.Value
+	}
+}
+func (s *recordedSpan) RecordError(err error)            { s.errors = append(s.errors, err) }
+func (s *recordedSpan) End()                             { s.ended = true }
+func (s *recordedSpan) SpanContext() tracing.SpanContext { return s.sc }
+
+// recordingTracer records the started spans, in order, along with the trace
+// parent they were started with.
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string, attrs ...tracing.Attribute) (context.Context, tracing.Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	span := &recordedSpan{
+		name:  name,
+		attrs: map[string]any{},
+		sc: tracing.SpanContext{
+			TraceParent: fmt.Sprintf("00-4bf92f3577b34da6a3ce929d0e0e4736-%016x-01", len(t.spans)+1),
+		},
+	}
+	if sc, ok := tracing.CurrentSpanContext(ctx); ok {
+		span.parent = sc.TraceParent
+	}
+	span.SetAttributes(attrs...)
+	t.spans = append(t.spans, span)
+	return ctx, span
+}
+
+func (t *recordingTracer) recorded() []*recordedSpan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]*recordedSpan(nil), t.spans...)
+}
+
+func TestMCPServer_Tracing(t *testing.T) {
+	tracer := &recordingTracer{}
+	server := NewMCPServer("test-server", "1.0.0",
+		WithToolCapabilities(false),
+		WithResourceCapabilities(false, false),
+		WithTracer(tracer),
+	)
+	server.AddTool(mcp.NewTool("echo"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+	server.AddTool(mcp.NewTool("fail"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return nil, fmt.Errorf("boom")
+	})
+	server.AddResource(mcp.NewResource("test://doc", "doc"), func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return []mcp.ResourceContents{mcp.TextResourceContents{URI: "test://doc", Text: "doc"}}, nil
+	})
+
+	session := NewInProcessSession("trace-session", nil)
+	session.Initialize()
+	ctx := server.WithContext(context.Background(), session)
+
+	t.Run("parent from _meta", func(t *testing.T) {
+		response := server.HandleMessage(ctx, []byte(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"method": "tools/call",
+			"params": {"name": "echo", "_meta": {"traceparent": "`+testTraceParent+`"}}
+		}`))
+		_, ok := response.(mcp.JSONRPCResponse)
+		require.True(t, ok, "expected a response, got %#v", response)
+
+		spans := tracer.recorded()
+		require.Len(t, spans, 2)
+		request, tool := spans[0], spans[1]
+
+		assert.Equal(t, "tools/call", request.name)
+		assert.Equal(t, testTraceParent, request.parent)
+		assert.Equal(t, "tools/call", request.attrs["mcp.method.name"])
+		assert.Equal(t, "1", request.attrs["jsonrpc.request.id"])
+		assert.Equal(t, "trace-session", request.attrs["mcp.session.id"])
+		assert.True(t, request.ended)
+
+		assert.Equal(t, "tool echo", tool.name)
+		assert.Equal(t, request.sc.TraceParent, tool.parent)
+		assert.Equal(t, "echo", tool.attrs["mcp.tool.name"])
+		assert.True(t, tool.ended)
+	})
+
+	t.Run("parent from context", func(t *testing.T) {
+		tracer.spans = nil
+		ctx := tracing.ContextWithRemoteParent(ctx, tracing.SpanContext{TraceParent: testTraceParent})
+		server.HandleMessage(ctx, []byte(`{
+			"jsonrpc": "2.0",
+			"id": 2,
+			"method": "resources/read",
+			"params": {"uri": "test://doc"}
+		}`))
+
+		spans := tracer.recorded()
+		require.Len(t, spans, 2)
+		assert.Equal(t, "resources/read", spans[0].name)
+		assert.Equal(t, testTraceParent, spans[0].parent)
+		assert.Equal(t, "resource test://doc", spans[1].name)
+		assert.Equal(t, spans[0].sc.TraceParent, spans[1].parent)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tracer.spans = nil
+		response := server.HandleMessage(ctx, []byte(`{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"method": "tools/call",
+			"params": {"name": "fail"}
+		}`))
+		_, ok := response.(mcp.JSONRPCError)
+		require.True(t, ok, "expected an error, got %#v", response)
+
+		spans := tracer.recorded()
+		require.Len(t, spans, 2)
+		assert.Empty(t, spans[0].parent)
+		assert.Equal(t, mcp.INTERNAL_ERROR, spans[0].attrs["rpc.jsonrpc.error_code"])
+		assert.Len(t, spans[0].errors, 1)
+		assert.Len(t, spans[1].errors, 1)
+	})
+}
+
+type tracingSamplingHandler struct {
+	parent string
+}
+
+func (h *tracingSamplingHandler) CreateMessage(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	if sc, ok := tracing.CurrentSpanContext(ctx); ok {
+		h.parent = sc.TraceParent
+	}
+	return &mcp.CreateMessageResult{}, nil
+}
+
+func TestMCPServer_TracingSampling(t *testing.T) {
+	tracer := &recordingTracer{}
+	server := NewMCPServer("test-server", "1.0.0", WithTracer(tracer))
+	server.EnableSampling()
+
+	handler := &tracingSamplingHandler{}
+	session := NewInProcessSession("trace-session", handler)
+	session.Initialize()
+	ctx := server.WithContext(context.Background(), session)
+
+	_, err := server.RequestSampling(ctx, mcp.CreateMessageRequest{})
+	require.NoError(t, err)
+
+	spans := tracer.recorded()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "sampling/createMessage", spans[0].name)
+	assert.True(t, spans[0].ended)
+	assert.Equal(t, spans[0].sc.TraceParent, handler.parent)
+}
+
+func TestRequestClient_InjectsTraceContext(t *testing.T) {
+	var sent any
+	send := func(ctx context.Context, method mcp.MCPMethod, params any) (json.RawMessage, error) {
+		sent = params
+		return json.RawMessage(`{"roots": []}`), nil
+	}
+
+	ctx := tracing.ContextWithRemoteParent(context.Background(), tracing.SpanContext{TraceParent: testTraceParent})
+	_, err := requestClient[mcp.ListRootsResult](ctx, send, mcp.MethodListRoots, nil)
+	require.NoError(t, err)
+
+	params, ok := sent.(map[string]any)
+	require.True(t, ok, "expected params to be an object, got %#v", sent)
+	assert.Equal(t, map[string]any{tracing.TraceParentKey: testTraceParent}, params["_meta"])
+}
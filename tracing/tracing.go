@@ -0,0 +1,182 @@
+// Package tracing defines the tracing layer of MCP clients and servers and
+// propagates W3C trace context (https://www.w3.org/TR/trace-context/) across
+// them, through HTTP headers and the _meta field of requests.
+//
+// No tracing SDK is required: implement Tracer with an adapter around the SDK
+// of your choice, e.g. OpenTelemetry, and pass it to server.WithTracer and
+// client.WithTracer.
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"regexp"
+)
+
+const (
+	// TraceParentKey is the name of the HTTP header and of the _meta field
+	// carrying the parent span.
+	TraceParentKey = "traceparent"
+	// TraceStateKey is the name of the HTTP header and of the _meta field
+	// carrying vendor-specific trace state.
+	TraceStateKey = "tracestate"
+)
+
+// SpanContext identifies a span in W3C trace context form, for propagation.
+type SpanContext struct {
+	TraceParent string
+	TraceState  string
+}
+
+var traceParentPattern = regexp.MustCompile(`^[0-9a-f]{2}-[0-9a-f]{32}-[0-9a-f]{16}-[0-9a-f]{2}$`)
+
+// IsValid reports whether the traceparent is well formed and its IDs are not
+// all zeros.
+func (sc SpanContext) IsValid() bool {
+	if !traceParentPattern.MatchString(sc.TraceParent) || sc.TraceParent[:2] == "ff" {
+		return false
+	}
+	return sc.TraceParent[3:35] != "00000000000000000000000000000000" &&
+		sc.TraceParent[36:52] != "0000000000000000"
+}
+
+// Attribute is a key-value pair describing a span.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// Span is an operation being traced.
+type Span interface {
+	// SetAttributes sets attributes on the span.
+	SetAttributes(attrs ...Attribute)
+	// RecordError records an error and marks the span as failed.
+	RecordError(err error)
+	// End completes the span.
+	End()
+	// SpanContext returns the context of the span, propagated to the other
+	// side of the requests made within it.
+	SpanContext() SpanContext
+}
+
+// Tracer starts spans.
+type Tracer interface {
+	// Start starts a span named name. Its parent is the span of ctx if the
+	// tracer knows one, or else the remote parent returned by
+	// RemoteParentFromContext, if any. The returned context must carry the
+	// new span for the tracer.
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+type spanKey struct{}
+
+type remoteParentKey struct{}
+
+// Start starts a span with tracer and returns a context carrying it, which
+// SpanFromContext returns. A nil tracer starts a no-op span, leaving ctx
+// unchanged.
+func Start(ctx context.Context, tracer Tracer, name string, attrs ...Attribute) (context.Context, Span) {
+	if tracer == nil {
+		return ctx, noopSpan{}
+	}
+	ctx, span := tracer.Start(ctx, name, attrs...)
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// SpanFromContext returns the span started by Start that ctx carries, or nil.
+func SpanFromContext(ctx context.Context) Span {
+	span, _ := ctx.Value(spanKey{}).(Span)
+	return span
+}
+
+// ContextWithRemoteParent returns a context carrying the span context
+// received from the other side of a request, used as the parent of the next
+// span started. Invalid span contexts are ignored.
+func ContextWithRemoteParent(ctx context.Context, sc SpanContext) context.Context {
+	if !sc.IsValid() {
+		return ctx
+	}
+	return context.WithValue(ctx, remoteParentKey{}, sc)
+}
+
+// RemoteParentFromContext returns the span context set by
+// ContextWithRemoteParent, if any.
+func RemoteParentFromContext(ctx context.Context) (SpanContext, bool) {
+	sc, ok := ctx.Value(remoteParentKey{}).(SpanContext)
+	return sc, ok
+}
+
+// CurrentSpanContext returns the context to propagate with the requests made
+// within ctx: the one of the span started by Start, or else the remote
+// parent, so that the trace is continued even when nothing is traced locally.
+func CurrentSpanContext(ctx context.Context) (SpanContext, bool) {
+	if span := SpanFromContext(ctx); span != nil {
+		if sc := span.SpanContext(); sc.IsValid() {
+			return sc, true
+		}
+	}
+	return RemoteParentFromContext(ctx)
+}
+
+// ContextFromHTTPRequest returns a context carrying the span context of the
+// traceparent and tracestate headers of r as remote parent. It can be used
+// as, or called from, the context function of the HTTP server transports.
+func ContextFromHTTPRequest(ctx context.Context, r *http.Request) context.Context {
+	return ContextWithRemoteParent(ctx, SpanContext{
+		TraceParent: r.Header.Get(TraceParentKey),
+		TraceState:  r.Header.Get(TraceStateKey),
+	})
+}
+
+// FromMeta returns the span context carried by the _meta field of a request.
+func FromMeta(meta map[string]any) (SpanContext, bool) {
+	traceParent, _ := meta[TraceParentKey].(string)
+	traceState, _ := meta[TraceStateKey].(string)
+	sc := SpanContext{TraceParent: traceParent, TraceState: traceState}
+	return sc, sc.IsValid()
+}
+
+// InjectMeta returns params, which must encode to a JSON object, with the
+// current span context of ctx added to its _meta field. It returns params
+// unchanged when there is no span context to propagate.
+func InjectMeta(ctx context.Context, params any) (any, error) {
+	sc, ok := CurrentSpanContext(ctx)
+	if !ok {
+		return params, nil
+	}
+
+	fields := make(map[string]any)
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return nil, err
+		}
+		if fields == nil {
+			// params encoded to null
+			fields = make(map[string]any)
+		}
+	}
+	meta, _ := fields["_meta"].(map[string]any)
+	if meta == nil {
+		meta = make(map[string]any)
+	}
+	meta[TraceParentKey] = sc.TraceParent
+	if sc.TraceState != "" {
+		meta[TraceStateKey] = sc.TraceState
+	} else {
+		delete(meta, TraceStateKey)
+	}
+	fields["_meta"] = meta
+	return fields, nil
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...Attribute) {}
+func (noopSpan) RecordError(error)          {}
+func (noopSpan) End()                       {}
+func (noopSpan) SpanContext() SpanContext   { return SpanContext{} }
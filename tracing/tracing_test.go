@@ -0,0 +1,128 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	testSpanParent  = "00-4bf92f3577b34da6a3ce929d0e0e4736-b7ad6b7169203331-01"
+)
+
+type testSpan struct {
+	sc SpanContext
+}
+
+func (s *testSpan) SetAttributes(...Attribute) {}
+func (s *testSpan) RecordError(error)          {}
+func (s *testSpan) End()                       {}
+func (s *testSpan) SpanContext() SpanContext   { return s.sc }
+
+type testTracer struct{}
+
+func (testTracer) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	return ctx, &testSpan{sc: SpanContext{TraceParent: testSpanParent}}
+}
+
+func TestSpanContext_IsValid(t *testing.T) {
+	tests := []struct {
+		traceParent string
+		want        bool
+	}{
+		{testTraceParent, true},
+		{"", false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7", false},
+		{"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", false},
+		{"00-00000000000000000000000000000000-00f067aa0ba902b7-01", false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", false},
+		{"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, SpanContext{TraceParent: tt.traceParent}.IsValid(), tt.traceParent)
+	}
+}
+
+func TestCurrentSpanContext(t *testing.T) {
+	ctx := context.Background()
+	_, ok := CurrentSpanContext(ctx)
+	assert.False(t, ok)
+
+	// Invalid remote parents are ignored
+	assert.Equal(t, ctx, ContextWithRemoteParent(ctx, SpanContext{TraceParent: "invalid"}))
+
+	remote := SpanContext{TraceParent: testTraceParent, TraceState: "vendor=value"}
+	ctx = ContextWithRemoteParent(ctx, remote)
+	sc, ok := CurrentSpanContext(ctx)
+	require.True(t, ok)
+	assert.Equal(t, remote, sc)
+
+	// Without a tracer the trace is passed through
+	noopCtx, span := Start(ctx, nil, "noop")
+	span.End()
+	sc, ok = CurrentSpanContext(noopCtx)
+	require.True(t, ok)
+	assert.Equal(t, remote, sc)
+
+	// A started span takes precedence
+	ctx, span = Start(ctx, testTracer{}, "span")
+	assert.Equal(t, span, SpanFromContext(ctx))
+	sc, ok = CurrentSpanContext(ctx)
+	require.True(t, ok)
+	assert.Equal(t, testSpanParent, sc.TraceParent)
+}
+
+func TestContextFromHTTPRequest(t *testing.T) {
+	r, err := http.NewRequest(http.MethodPost, "http://localhost/mcp", nil)
+	require.NoError(t, err)
+	r.Header.Set("Traceparent", testTraceParent)
+	r.Header.Set("Tracestate", "vendor=value")
+
+	sc, ok := RemoteParentFromContext(ContextFromHTTPRequest(context.Background(), r))
+	require.True(t, ok)
+	assert.Equal(t, SpanContext{TraceParent: testTraceParent, TraceState: "vendor=value"}, sc)
+}
+
+func TestInjectMeta(t *testing.T) {
+	type params struct {
+		Name string         `json:"name"`
+		Meta map[string]any `json:"_meta,omitempty"`
+	}
+
+	t.Run("without span context", func(t *testing.T) {
+		in := params{Name: "tool"}
+		out, err := InjectMeta(context.Background(), in)
+		require.NoError(t, err)
+		assert.Equal(t, in, out)
+	})
+
+	ctx := ContextWithRemoteParent(context.Background(), SpanContext{TraceParent: testTraceParent})
+
+	t.Run("struct params", func(t *testing.T) {
+		out, err := InjectMeta(ctx, params{Name: "tool", Meta: map[string]any{"progressToken": "p1", TraceStateKey: "stale"}})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{
+			"name":  "tool",
+			"_meta": map[string]any{"progressToken": "p1", TraceParentKey: testTraceParent},
+		}, out)
+
+		sc, ok := FromMeta(out.(map[string]any)["_meta"].(map[string]any))
+		require.True(t, ok)
+		assert.Equal(t, testTraceParent, sc.TraceParent)
+	})
+
+	t.Run("nil params", func(t *testing.T) {
+		out, err := InjectMeta(ctx, nil)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"_meta": map[string]any{TraceParentKey: testTraceParent}}, out)
+	})
+
+	t.Run("non-object params", func(t *testing.T) {
+		_, err := InjectMeta(ctx, []string{"a"})
+		assert.Error(t, err)
+	})
+}
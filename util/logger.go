@@ -1,7 +1,12 @@
 package util
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"log/slog"
+	"strconv"
+	"strings"
 )
 
 // Logger defines a minimal logging interface
@@ -19,6 +24,14 @@ func DefaultLogger() Logger {
 	}
 }
 
+// FromLogLogger returns a Logger writing to the given log.Logger, with its
+// prefix and flags.
+func FromLogLogger(logger *log.Logger) Logger {
+	return &stdLogger{
+		logger: logger,
+	}
+}
+
 // stdLogger wraps the standard library's log.Logger.
 type stdLogger struct {
 	logger *log.Logger
@@ -31,3 +44,106 @@ func (l *stdLogger) Infof(format string, v ...any) {
 func (l *stdLogger) Errorf(format string, v ...any) {
 	l.logger.Printf("ERROR: "+format, v...)
 }
+
+// --- log/slog Adapters ---
+
+// FromSlog returns a Logger writing to the given slog.Logger, at the info
+// and error levels.
+func FromSlog(logger *slog.Logger) Logger {
+	return &slogLogger{logger: logger}
+}
+
+// slogLogger wraps a slog.Logger.
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+func (l *slogLogger) Infof(format string, v ...any) {
+	l.logger.Info(fmt.Sprintf(format, v...))
+}
+
+func (l *slogLogger) Errorf(format string, v ...any) {
+	l.logger.Error(fmt.Sprintf(format, v...))
+}
+
+// ToSlog returns a slog.Logger writing to the given Logger. Records of the
+// error level and above are written with Errorf, and the others with Infof;
+// debug records are dropped. Attributes are appended to the message as
+// key=value pairs.
+//
+// A Logger created with FromSlog is unwrapped, so that attributes keep their
+// structure.
+func ToSlog(logger Logger) *slog.Logger {
+	if l, ok := logger.(*slogLogger); ok {
+		return l.logger
+	}
+	return slog.New(&loggerHandler{logger: logger})
+}
+
+// loggerHandler is a slog.Handler writing to a Logger.
+type loggerHandler struct {
+	logger Logger
+	attrs  string // preformatted attributes of WithAttrs
+	prefix string // key prefix of the groups of WithGroup
+}
+
+func (h *loggerHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= slog.LevelInfo
+}
+
+func (h *loggerHandler) Handle(_ context.Context, record slog.Record) error {
+	var b strings.Builder
+	b.WriteString(record.Message)
+	b.WriteString(h.attrs)
+	record.Attrs(func(attr slog.Attr) bool {
+		appendAttr(&b, h.prefix, attr)
+		return true
+	})
+
+	if record.Level >= slog.LevelError {
+		h.logger.Errorf("%s", b.String())
+	} else {
+		h.logger.Infof("%s", b.String())
+	}
+	return nil
+}
+
+func (h *loggerHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var b strings.Builder
+	b.WriteString(h.attrs)
+	for _, attr := range attrs {
+		appendAttr(&b, h.prefix, attr)
+	}
+	return &loggerHandler{logger: h.logger, attrs: b.String(), prefix: h.prefix}
+}
+
+func (h *loggerHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &loggerHandler{logger: h.logger, attrs: h.attrs, prefix: h.prefix + name + "."}
+}
+
+// appendAttr writes attr to b as a space-separated key=value pair, flattening
+// groups into dotted keys.
+func appendAttr(b *strings.Builder, prefix string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, child := range attr.Value.Group() {
+			appendAttr(b, prefix, child)
+		}
+		return
+	}
+
+	value := attr.Value.String()
+	if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+		value = strconv.Quote(value)
+	}
+	b.WriteString(" " + prefix + attr.Key + "=" + value)
+}
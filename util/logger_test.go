@@ -0,0 +1,71 @@
+package util
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingLogger struct {
+	infos  []string
+	errors []string
+}
+
+func (l *recordingLogger) Infof(format string, v ...any) {
+	l.infos = append(l.infos, fmt.Sprintf(format, v...))
+}
+
+func (l *recordingLogger) Errorf(format string, v ...any) {
+	l.errors = append(l.errors, fmt.Sprintf(format, v...))
+}
+
+func TestToSlog(t *testing.T) {
+	logger := &recordingLogger{}
+	slogger := ToSlog(logger).With("sessionID", "abc")
+
+	slogger.Debug("dropped")
+	slogger.Info("handling message", "method", "tools/call", "requestID", 1)
+	slogger.Warn("slow", slog.Group("tool", "name", "echo"))
+	slogger.WithGroup("stream").Error("write failed", "id", "s1", "error", errors.New("broken pipe"))
+
+	assert.Equal(t, []string{
+		`handling message sessionID=abc method=tools/call requestID=1`,
+		`slow sessionID=abc tool.name=echo`,
+	}, logger.infos)
+	assert.Equal(t, []string{
+		`write failed sessionID=abc stream.id=s1 stream.error="broken pipe"`,
+	}, logger.errors)
+}
+
+func TestFromSlog(t *testing.T) {
+	var buf bytes.Buffer
+	slogger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	logger := FromSlog(slogger)
+	logger.Infof("connected to %s", "server")
+	logger.Errorf("failed: %v", errors.New("boom"))
+
+	assert.Equal(t, "level=INFO msg=\"connected to server\"\nlevel=ERROR msg=\"failed: boom\"\n", buf.String())
+	assert.Same(t, slogger, ToSlog(logger))
+}
+
+func TestFromLogLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := FromLogLogger(log.New(&buf, "[mcp] ", log.Lmsgprefix))
+	logger.Infof("connected to %s", "server")
+	ToSlog(logger).Error("failed", "error", errors.New("boom"))
+
+	assert.Equal(t, "[mcp] INFO: connected to server\n[mcp] ERROR: failed error=boom\n", buf.String())
+}